@@ -0,0 +1,188 @@
+// Package cli implements the non-interactive "solve" command of lab1.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"calcMat/format"
+	"calcMat/solver"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK             = 0
+	ExitNoSolve        = 1 // the solver failed, e.g. a singular matrix
+	ExitBadInput       = 2 // invalid arguments or an unreadable system
+	ExitDiverged       = 3 // the iteration diverged
+	ExitIterationLimit = 4 // the iteration limit was reached
+)
+
+// Run executes "solve" with the arguments that follow it and returns the
+// process exit code. Each system is read from a file given with -f or as a
+// positional argument ("-" reads standard input). Several files can only
+// be solved in one run with text output.
+//
+//	calcMat solve -f system.txt --method seidel --eps 1e-6 --format json
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var files []string
+	fs.Func("f", "system file to solve (repeatable, \"-\" for stdin)", func(v string) error {
+		files = append(files, v)
+		return nil
+	})
+	methodName := fs.String("method", "", "solution method: jacobi, seidel, sor, gauss, lu, cholesky, cg, gmres, bicgstab (default: from the file, else jacobi)")
+	eps := fs.Float64("eps", 0, "precision, overriding the one in the file")
+	omega := fs.Float64("omega", 0, "SOR relaxation factor (0 estimates it)")
+	precondName := fs.String("precond", "none", "Krylov preconditioner: none, jacobi, ilu0")
+	formatName := fs.String("format", "text", "output format: text, json, csv, mtx")
+	backendName := fs.String("backend", "float64", "arithmetic: float64, bigfloat, rational (Gaussian elimination only); the big backends read each coefficient as the shortest decimal that rounds to its float64 value, so inputs with up to 15 significant digits are exact")
+	bits := fs.Uint("bits", 256, "big.Float mantissa bits")
+	maxIter := fs.Int("max-iter", 10000, "iteration limit of iterative methods")
+	stopName := fs.String("stop", "abs", "stopping criterion: abs (‖Δx‖ < eps), rel (‖Δx‖ < eps‖x‖), residual (‖b-Ax‖ < eps)")
+	diverge := fs.Int("diverge", 0, "report divergence after this many consecutive growing steps (0 disables)")
+	if err := fs.Parse(args); err != nil {
+		return ExitBadInput
+	}
+	files = append(files, fs.Args()...)
+	var invalid string
+	fs.Visit(func(f *flag.Flag) {
+		switch {
+		case f.Name == "eps" && !(*eps > 0 && !math.IsInf(*eps, 1)):
+			invalid = fmt.Sprintf("--eps must be a positive number, got %g", *eps)
+		case f.Name == "max-iter" && *maxIter < 1:
+			invalid = fmt.Sprintf("--max-iter must be at least 1, got %d", *maxIter)
+		case f.Name == "diverge" && *diverge < 0:
+			invalid = fmt.Sprintf("--diverge must not be negative, got %d", *diverge)
+		}
+	})
+	if invalid != "" {
+		fmt.Fprintln(stderr, "solve:", invalid)
+		return ExitBadInput
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "solve: no input file given")
+		fs.Usage()
+		return ExitBadInput
+	}
+
+	out, err := format.ParseName(*formatName)
+	if err != nil {
+		fmt.Fprintln(stderr, "solve:", err)
+		return ExitBadInput
+	}
+	// Only text output separates the results of several systems; JSON, CSV
+	// and Matrix Market documents cannot simply be concatenated.
+	if len(files) > 1 && out != format.Text {
+		fmt.Fprintf(stderr, "solve: --format %s writes a single document; give one input file\n", *formatName)
+		return ExitBadInput
+	}
+	precond, err := solver.ParsePreconditioner(*precondName)
+	if err != nil {
+		fmt.Fprintln(stderr, "solve:", err)
+		return ExitBadInput
+	}
+	backend, err := solver.ParseBackend(*backendName)
+	if err != nil {
+		fmt.Fprintln(stderr, "solve:", err)
+		return ExitBadInput
+	}
+	stop, err := solver.ParseStopCriterion(*stopName)
+	if err != nil {
+		fmt.Fprintln(stderr, "solve:", err)
+		return ExitBadInput
+	}
+	var method *solver.Method
+	if *methodName != "" {
+		m, err := solver.ParseMethod(*methodName)
+		if err != nil {
+			fmt.Fprintln(stderr, "solve:", err)
+			return ExitBadInput
+		}
+		method = &m
+	}
+
+	code := ExitOK
+	for _, file := range files {
+		var content []byte
+		if file == "-" {
+			content, err = io.ReadAll(stdin)
+		} else {
+			content, err = os.ReadFile(file)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "solve: %s: %v\n", file, err)
+			code = ExitBadInput
+			continue
+		}
+
+		system, err := format.Parse(file, content)
+		if err != nil {
+			fmt.Fprintf(stderr, "solve: %s: invalid %s file: %v\n", file, format.Detect(file, content), err)
+			code = ExitBadInput
+			continue
+		}
+
+		opts := solver.Options{
+			Omega:           *omega,
+			Preconditioner:  precond,
+			Backend:         backend,
+			Bits:            *bits,
+			MaxIterations:   *maxIter,
+			Stop:            stop,
+			DivergenceSteps: *diverge,
+		}
+		switch {
+		case method != nil:
+			opts.Method = *method
+		case system.Method != nil:
+			opts.Method = *system.Method
+		}
+		precision := system.Precision
+		if *eps > 0 {
+			precision = *eps
+		}
+
+		var result *solver.Result
+		if system.Sparse != nil {
+			result, err = solver.SolveSparse(system.Sparse, system.Vector, precision, opts)
+		} else {
+			result, err = solver.Solve(system.Matrix, system.Vector, precision, opts)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "solve: %s: %v\n", file, err)
+			if code == ExitOK {
+				code = exitCode(err)
+			}
+			continue
+		}
+
+		if len(files) > 1 && out == format.Text {
+			fmt.Fprintf(stdout, "== %s ==\n", file)
+		}
+		if err := format.WriteSolution(stdout, out, result); err != nil {
+			fmt.Fprintf(stderr, "solve: %v\n", err)
+			return ExitNoSolve
+		}
+	}
+	return code
+}
+
+// exitCode maps a solver error to the exit code reported for it.
+func exitCode(err error) int {
+	var diverged *solver.DivergenceError
+	var limit *solver.IterationLimitError
+	switch {
+	case errors.As(err, &diverged):
+		return ExitDiverged
+	case errors.As(err, &limit):
+		return ExitIterationLimit
+	default:
+		return ExitNoSolve
+	}
+}
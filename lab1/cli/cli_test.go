@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSystem stores content in a temporary file and returns its path.
+func writeSystem(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	system := writeSystem(t, "system.txt", "2\n4 1 2\n1 3 1\n0.0001\n")
+	singular := writeSystem(t, "singular.txt", "2\n1 2 1\n2 4 2\n0.0001\n")
+	broken := writeSystem(t, "broken.txt", "2\n4 1 2\n1 3\n0.0001\n")
+	negative := writeSystem(t, "negative.json", `{"A": [[4, 1], [1, 3]], "b": [2, 1], "precision": -1}`)
+	// Jacobi converges (ρ ≈ 0.71) but ‖xₖ - xₖ₋₁‖∞ grows tenfold in the
+	// second step.
+	growing := writeSystem(t, "growing.json", `{"A": [[1, 10], [0.05, 1]], "b": [0, 1]}`)
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		code   int
+		stdout []string // substrings expected on standard output
+	}{
+		{"jacobi", []string{system}, "", ExitOK, []string{"Method: Jacobi", "x1 = 0.4545"}},
+		{"flag file", []string{"-f", system, "--method", "seidel"}, "", ExitOK, []string{"Method: Gauss-Seidel"}},
+		{"stdin", []string{"--method", "gauss", "-"}, "2\n4 1 2\n1 3 1\n0.0001\n", ExitOK, []string{"Method: Gaussian elimination"}},
+		{"json output", []string{"--method", "lu", "--format", "json", system}, "", ExitOK, []string{`"method": "lu"`, `"x": [`}},
+		{"eps", []string{"--eps", "1e-10", system}, "", ExitOK, []string{"x2 = 0.1818181818"}},
+		{"relative stop", []string{"--stop", "rel", "--eps", "1e-10", system}, "", ExitOK, []string{"x2 = 0.1818181818"}},
+		{"residual stop", []string{"--stop", "residual", system}, "", ExitOK, []string{"Method: Jacobi"}},
+		{"rational", []string{"--backend", "rational", "--method", "gauss", system}, "", ExitOK, []string{"x1 = 5/11", "x2 = 2/11"}},
+		{"bigfloat 64 bits", []string{"--backend", "bigfloat", "--bits", "64", "--method", "gauss", system}, "", ExitOK, []string{"x1 = 0.4545454545454545454\n"}},
+		{"bigfloat 128 bits", []string{"--backend", "bigfloat", "--bits", "128", "--method", "gauss", system}, "", ExitOK, []string{"x1 = 0.45454545454545454545454545454545454545"}},
+		{"singular", []string{"--method", "gauss", singular}, "", ExitNoSolve, nil},
+		{"rational iterative", []string{"--backend", "rational", system}, "", ExitNoSolve, nil},
+		{"no file", nil, "", ExitBadInput, nil},
+		{"missing file", []string{filepath.Join(t.TempDir(), "missing.txt")}, "", ExitBadInput, nil},
+		{"malformed file", []string{broken}, "", ExitBadInput, nil},
+		{"unknown method", []string{"--method", "newton", system}, "", ExitBadInput, nil},
+		{"unknown stop", []string{"--stop", "never", system}, "", ExitBadInput, nil},
+		{"unknown backend", []string{"--backend", "decimal", system}, "", ExitBadInput, nil},
+		{"bad bits", []string{"--bits", "many", system}, "", ExitBadInput, nil},
+		{"zero eps", []string{"--eps", "0", system}, "", ExitBadInput, nil},
+		{"negative eps", []string{"--eps", "-1e-6", system}, "", ExitBadInput, nil},
+		{"negative precision in file", []string{negative}, "", ExitBadInput, nil},
+		{"zero max-iter", []string{"--max-iter", "0", system}, "", ExitBadInput, nil},
+		{"negative diverge", []string{"--diverge", "-1", system}, "", ExitBadInput, nil},
+		{"unknown format", []string{"--format", "xml", system}, "", ExitBadInput, nil},
+		{"two files", []string{"-f", system, system}, "", ExitOK, []string{"== " + system + " ==", "Method: Jacobi"}},
+		{"two files as json", []string{"--format", "json", system, system}, "", ExitBadInput, nil},
+		{"two files as csv", []string{"--format", "csv", "-f", system, system}, "", ExitBadInput, nil},
+		{"unknown preconditioner", []string{"--method", "cg", "--precond", "ssor", system}, "", ExitBadInput, nil},
+		{"diverged", []string{"--diverge", "1", growing}, "", ExitDiverged, nil},
+		{"iteration limit", []string{"--max-iter", "2", "--eps", "1e-12", system}, "", ExitIterationLimit, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := Run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d (stderr: %s)", code, tt.code, stderr.String())
+			}
+			for _, want := range tt.stdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout does not contain %q:\n%s", want, stdout.String())
+				}
+			}
+			if tt.code != ExitOK && stderr.Len() == 0 {
+				t.Error("no message on stderr")
+			}
+		})
+	}
+}
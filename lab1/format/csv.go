@@ -0,0 +1,90 @@
+package format
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"calcMat/solver"
+)
+
+// ParseCSV reads the augmented matrix [A|b] as n rows of n+1
+// comma-separated numbers. An optional last row holding a single value is
+// the precision; otherwise DefaultPrecision is used.
+func ParseCSV(input string) (*System, error) {
+	r := csv.NewReader(strings.NewReader(strings.TrimSpace(input)))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	precision := DefaultPrecision
+	if last := records[len(records)-1]; len(records) > 1 && len(last) == 1 {
+		if precision, err = strconv.ParseFloat(strings.TrimSpace(last[0]), 64); err != nil {
+			return nil, fmt.Errorf("invalid precision value: %v", err)
+		}
+		records = records[:len(records)-1]
+	}
+
+	n := len(records)
+	matrix := newBuilder(n)
+	vector := make([]float64, n)
+	for i, record := range records {
+		if len(record) != n+1 {
+			return nil, fmt.Errorf("invalid number of coefficients in row %d", i+1)
+		}
+		for j, field := range record {
+			val, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value at row %d, col %d: %v", i+1, j+1, err)
+			}
+			if j == n {
+				vector[i] = val
+			} else {
+				matrix.add(i, j, val)
+			}
+		}
+	}
+
+	return matrix.system(vector, precision)
+}
+
+func writeSystemCSV(w io.Writer, s *System) error {
+	cw := csv.NewWriter(w)
+	A := s.Dense()
+	for i, row := range A {
+		record := make([]string, 0, len(row)+1)
+		for _, v := range row {
+			record = append(record, formatFloat(v))
+		}
+		record = append(record, formatFloat(s.Vector[i]))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := cw.Write([]string{formatFloat(s.Precision)}); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeSolutionCSV writes one row per unknown with its value and final
+// error, followed by the iteration count.
+func writeSolutionCSV(w io.Writer, res *solver.Result) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{{"component", "x", "error"}}
+	for i, v := range res.Solution {
+		records = append(records, []string{strconv.Itoa(i + 1), formatFloat(v), formatFloat(res.Errors[i])})
+	}
+	records = append(records, []string{"iterations", strconv.Itoa(res.Iterations), ""})
+	return cw.WriteAll(records)
+}
@@ -0,0 +1,235 @@
+// Package format reads and writes linear systems and their solutions in the
+// formats lab1 understands: the native text layout, Matrix Market, CSV and
+// JSON.
+package format
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"calcMat/solver"
+)
+
+// Format identifies a file format.
+type Format int
+
+const (
+	Text Format = iota
+	MatrixMarket
+	CSV
+	JSON
+)
+
+func (f Format) String() string {
+	switch f {
+	case Text:
+		return "text"
+	case MatrixMarket:
+		return "Matrix Market"
+	case CSV:
+		return "CSV"
+	case JSON:
+		return "JSON"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// MaxDenseDimension is the largest system kept in dense storage; larger
+// systems are stored as a sparse CSR matrix.
+const MaxDenseDimension = 200
+
+// DefaultPrecision is used when a format has no place for the precision and
+// the file does not give one.
+const DefaultPrecision = 1e-6
+
+// System is a linear system Ax = b read from a file. Exactly one of Matrix
+// and Sparse is set.
+type System struct {
+	Matrix    [][]float64
+	Sparse    *solver.CSR
+	Vector    []float64
+	Precision float64
+	// Method is the solution method requested by the file, or nil.
+	Method *solver.Method
+}
+
+// Dimension returns the number of unknowns.
+func (s *System) Dimension() int {
+	return len(s.Vector)
+}
+
+// Dense returns A as a dense matrix.
+func (s *System) Dense() [][]float64 {
+	if s.Sparse != nil {
+		return s.Sparse.Dense()
+	}
+	return s.Matrix
+}
+
+// ParseName returns the format with the given short name: text, mtx, csv
+// or json.
+func ParseName(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "text", "txt":
+		return Text, nil
+	case "mtx", "matrixmarket":
+		return MatrixMarket, nil
+	case "csv":
+		return CSV, nil
+	case "json":
+		return JSON, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q", name)
+	}
+}
+
+// ByName picks a format from a file extension, falling back to Text.
+func ByName(name string) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".mtx":
+		return MatrixMarket
+	case ".csv":
+		return CSV
+	case ".json":
+		return JSON
+	default:
+		return Text
+	}
+}
+
+// Detect picks a format from the file extension or, for unknown
+// extensions, from the content.
+func Detect(name string, content []byte) Format {
+	if f := ByName(name); f != Text {
+		return f
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	switch {
+	case strings.HasPrefix(trimmed, "%%MatrixMarket"):
+		return MatrixMarket
+	case strings.HasPrefix(trimmed, "{"):
+		return JSON
+	}
+	firstLine, _, _ := strings.Cut(trimmed, "\n")
+	if strings.Contains(firstLine, ",") {
+		return CSV
+	}
+	return Text
+}
+
+// Parse reads a system, detecting its format with Detect.
+func Parse(name string, content []byte) (*System, error) {
+	switch Detect(name, content) {
+	case MatrixMarket:
+		return ParseMatrixMarket(string(content))
+	case CSV:
+		return ParseCSV(string(content))
+	case JSON:
+		return ParseJSON(content)
+	default:
+		return ParseText(string(content))
+	}
+}
+
+// WriteSystem writes s in the given format.
+func WriteSystem(w io.Writer, f Format, s *System) error {
+	switch f {
+	case MatrixMarket:
+		return writeSystemMatrixMarket(w, s)
+	case CSV:
+		return writeSystemCSV(w, s)
+	case JSON:
+		return writeSystemJSON(w, s)
+	default:
+		return writeSystemText(w, s)
+	}
+}
+
+// WriteSolution writes the solution, iteration count and errors of res in
+// the given format.
+func WriteSolution(w io.Writer, f Format, res *solver.Result) error {
+	switch f {
+	case MatrixMarket:
+		return writeSolutionMatrixMarket(w, res)
+	case CSV:
+		return writeSolutionCSV(w, res)
+	case JSON:
+		return writeSolutionJSON(w, res)
+	default:
+		return writeSolutionText(w, res)
+	}
+}
+
+// builder assembles A in dense or sparse storage depending on its size.
+type builder struct {
+	dense [][]float64
+	coo   *solver.COO
+}
+
+func newBuilder(n int) *builder {
+	if n > MaxDenseDimension {
+		return &builder{coo: solver.NewCOO(n)}
+	}
+	dense := make([][]float64, n)
+	for i := range dense {
+		dense[i] = make([]float64, n)
+	}
+	return &builder{dense: dense}
+}
+
+// add accumulates v into aᵢⱼ (0-based).
+func (b *builder) add(i, j int, v float64) {
+	if b.coo != nil {
+		if v != 0 {
+			b.coo.Add(i, j, v)
+		}
+		return
+	}
+	b.dense[i][j] += v
+}
+
+func (b *builder) system(vector []float64, precision float64) (*System, error) {
+	if !(precision > 0) || math.IsInf(precision, 1) {
+		return nil, fmt.Errorf("precision must be a positive number, got %g", precision)
+	}
+	s := &System{Matrix: b.dense, Vector: vector, Precision: precision}
+	if b.coo != nil {
+		var err error
+		if s.Sparse, err = b.coo.ToCSR(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// entries calls fn for every non-zero aᵢⱼ of s in row-major order.
+func (s *System) entries(fn func(i, j int, v float64)) {
+	if s.Sparse != nil {
+		m := s.Sparse
+		for i := 0; i < m.N; i++ {
+			for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+				fn(i, m.ColIdx[k], m.Vals[k])
+			}
+		}
+		return
+	}
+	for i, row := range s.Matrix {
+		for j, v := range row {
+			if v != 0 {
+				fn(i, j, v)
+			}
+		}
+	}
+}
+
+// nonZeros returns the number of stored non-zero entries of A.
+func (s *System) nonZeros() int {
+	count := 0
+	s.entries(func(int, int, float64) { count++ })
+	return count
+}
@@ -0,0 +1,147 @@
+package format
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"calcMat/solver"
+)
+
+// tridiagonal returns a diagonally dominant tridiagonal system of size n,
+// stored sparse when n exceeds MaxDenseDimension.
+func tridiagonal(t *testing.T, n int) *System {
+	t.Helper()
+	b := newBuilder(n)
+	vector := make([]float64, n)
+	for i := 0; i < n; i++ {
+		b.add(i, i, 4)
+		if i > 0 {
+			b.add(i, i-1, -1)
+		}
+		if i+1 < n {
+			b.add(i, i+1, -1.5)
+		}
+		vector[i] = float64(i) + 0.25
+	}
+	s, err := b.system(vector, 1e-5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRoundTrip(t *testing.T) {
+	systems := map[string]*System{
+		"dense":  tridiagonal(t, 3),
+		"sparse": tridiagonal(t, MaxDenseDimension+1),
+	}
+	names := map[Format]string{Text: "system.txt", MatrixMarket: "system.mtx", CSV: "system.csv", JSON: "system.json"}
+	for kind, s := range systems {
+		for f, name := range names {
+			t.Run(kind+" "+f.String(), func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := WriteSystem(&buf, f, s); err != nil {
+					t.Fatal(err)
+				}
+				if got := Detect("system", buf.Bytes()); got != f {
+					t.Errorf("Detect() = %s, want %s", got, f)
+				}
+				got, err := Parse(name, buf.Bytes())
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				if (got.Sparse != nil) != (s.Sparse != nil) {
+					t.Errorf("sparse storage = %v, want %v", got.Sparse != nil, s.Sparse != nil)
+				}
+				if !reflect.DeepEqual(got.Dense(), s.Dense()) {
+					t.Error("A differs after the round trip")
+				}
+				if !reflect.DeepEqual(got.Vector, s.Vector) {
+					t.Errorf("b = %v, want %v", got.Vector, s.Vector)
+				}
+				if got.Precision != s.Precision {
+					t.Errorf("precision = %g, want %g", got.Precision, s.Precision)
+				}
+			})
+		}
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	s, err := ParseJSON([]byte(`{"A": [[2, 1], [1, 3]], "b": [1, 2], "method": "seidel"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Method == nil || *s.Method != solver.GaussSeidel {
+		t.Errorf("method = %v, want %s", s.Method, solver.GaussSeidel)
+	}
+	if s.Precision != DefaultPrecision {
+		t.Errorf("precision = %g, want the default %g", s.Precision, DefaultPrecision)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"mtx bad header", "a.mtx", "%%MatrixMarket vector coordinate real general\n2 3 2\n1 1 2\n2 2 3\n"},
+		{"mtx unknown field", "a.mtx", "%%MatrixMarket matrix coordinate complex general\n2 3 2\n1 1 2\n2 2 3\n"},
+		{"mtx nnz too large", "a.mtx", "%%MatrixMarket matrix coordinate real general\n2 3 3\n1 1 2\n2 2 3\n"},
+		{"mtx nnz too small", "a.mtx", "%%MatrixMarket matrix coordinate real general\n2 3 1\n1 1 2\n2 2 3\n"},
+		{"mtx row out of range", "a.mtx", "%%MatrixMarket matrix coordinate real general\n2 3 2\n1 1 2\n3 1 3\n"},
+		{"mtx column out of range", "a.mtx", "%%MatrixMarket matrix coordinate real general\n2 3 2\n1 1 2\n2 4 3\n"},
+		{"mtx not augmented", "a.mtx", "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 2\n2 2 3\n"},
+		{"mtx array too short", "a.mtx", "%%MatrixMarket matrix array real general\n2 3\n1\n2\n3\n"},
+		{"text short row", "a.txt", "2\n1 2 3\n4 5\n0.01\n"},
+		{"text bad precision", "a.txt", "1\n2 3\neps\n"},
+		{"text zero precision", "a.txt", "1\n2 3\n0\n"},
+		{"text negative precision", "a.txt", "1\n2 3\n-1e-3\n"},
+		{"csv negative precision", "a.csv", "2,3\n-0.1\n"},
+		{"mtx negative precision", "a.mtx", "%%MatrixMarket matrix coordinate real general\n% precision: -1\n1 2 2\n1 1 2\n1 2 3\n"},
+		{"json negative precision", "a.json", `{"A": [[1]], "b": [1], "precision": -1}`},
+		{"text nnz too large", "a.txt", "2 3\n1 1 2\n2 2 3\n1 1\n0.01\n"},
+		{"text index out of range", "a.txt", "2 2\n1 1 2\n2 3 3\n1 1\n0.01\n"},
+		{"text short b", "a.txt", "2 2\n1 1 2\n2 2 3\n1\n0.01\n"},
+		{"csv short row", "a.csv", "1,2,3\n4,5\n"},
+		{"csv bad value", "a.csv", "1,x\n"},
+		{"csv empty", "a.csv", ""},
+		{"csv blank", "a.csv", " \n\t\n"},
+		{"text empty", "a.txt", ""},
+		{"mtx empty", "a.mtx", ""},
+		{"json empty", "a.json", ""},
+		{"json short b", "a.json", `{"A": [[1, 0], [0, 1]], "b": [1]}`},
+		{"json short row", "a.json", `{"A": [[1, 0], [1]], "b": [1, 2]}`},
+		{"json unknown method", "a.json", `{"A": [[1]], "b": [1], "method": "newton"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.file, []byte(tt.content)); err == nil {
+				t.Error("Parse() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    Format
+	}{
+		{"a.mtx", "2\n1 2 3\n", MatrixMarket},
+		{"a.CSV", "", CSV},
+		{"a.json", "", JSON},
+		{"a", "%%MatrixMarket matrix array real general\n", MatrixMarket},
+		{"a.dat", "  {\"A\": []}", JSON},
+		{"a", "1, 2, 3\n", CSV},
+		{"a.txt", "2\n1 2 3\n4 5 6\n0.01\n", Text},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.file, []byte(tt.content)); got != tt.want {
+			t.Errorf("Detect(%q) = %s, want %s", tt.file, got, tt.want)
+		}
+	}
+}
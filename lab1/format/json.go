@@ -0,0 +1,94 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"calcMat/solver"
+)
+
+// jsonSystem is the JSON document {A, b, precision, method}.
+type jsonSystem struct {
+	A         [][]float64 `json:"A"`
+	B         []float64   `json:"b"`
+	Precision float64     `json:"precision,omitempty"`
+	Method    string      `json:"method,omitempty"`
+}
+
+type jsonSolution struct {
+	Method     string    `json:"method"`
+	X          []float64 `json:"x"`
+	Iterations int       `json:"iterations"`
+	Errors     []float64 `json:"errors"`
+	Residuals  []float64 `json:"residuals,omitempty"`
+	Exact      []string  `json:"exact,omitempty"`
+}
+
+// ParseJSON reads a document {"A": [[...]], "b": [...], "precision": ε,
+// "method": "seidel"}. Precision and method are optional.
+func ParseJSON(input []byte) (*System, error) {
+	var doc jsonSystem
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	n := len(doc.A)
+	if n == 0 {
+		return nil, fmt.Errorf("matrix A is empty")
+	}
+	if len(doc.B) != n {
+		return nil, fmt.Errorf("b has %d entries, expected %d", len(doc.B), n)
+	}
+
+	matrix := newBuilder(n)
+	for i, row := range doc.A {
+		if len(row) != n {
+			return nil, fmt.Errorf("invalid number of coefficients in row %d", i+1)
+		}
+		for j, v := range row {
+			matrix.add(i, j, v)
+		}
+	}
+
+	precision := doc.Precision
+	if precision == 0 {
+		precision = DefaultPrecision
+	}
+	s, err := matrix.system(doc.B, precision)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Method != "" {
+		method, err := solver.ParseMethod(doc.Method)
+		if err != nil {
+			return nil, err
+		}
+		s.Method = &method
+	}
+	return s, nil
+}
+
+func writeSystemJSON(w io.Writer, s *System) error {
+	doc := jsonSystem{A: s.Dense(), B: s.Vector, Precision: s.Precision}
+	if s.Method != nil {
+		doc.Method = s.Method.Name()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func writeSolutionJSON(w io.Writer, res *solver.Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonSolution{
+		Method:     res.Method.Name(),
+		X:          res.Solution,
+		Iterations: res.Iterations,
+		Errors:     res.Errors,
+		Residuals:  res.Residuals,
+		Exact:      res.ExactSolution,
+	})
+}
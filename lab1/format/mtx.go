@@ -0,0 +1,201 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"calcMat/solver"
+)
+
+// ParseMatrixMarket reads an n×(n+1) augmented matrix [A|b] in the NIST
+// Matrix Market coordinate or array format. Real, integer and pattern
+// fields and the general, symmetric and skew-symmetric qualifiers are
+// understood; symmetry applies to A only. The precision may be given in a
+// comment line "% precision: 1e-4", otherwise DefaultPrecision is used.
+func ParseMatrixMarket(input string) (*System, error) {
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+	header := strings.Fields(strings.ToLower(lines[0]))
+	if len(header) != 5 || header[0] != "%%matrixmarket" || header[1] != "matrix" {
+		return nil, fmt.Errorf("invalid Matrix Market header")
+	}
+	layout, field, symmetry := header[2], header[3], header[4]
+	if layout != "coordinate" && layout != "array" {
+		return nil, fmt.Errorf("unsupported Matrix Market layout %q", layout)
+	}
+	if field != "real" && field != "integer" && field != "double" && !(field == "pattern" && layout == "coordinate") {
+		return nil, fmt.Errorf("unsupported Matrix Market field %q", field)
+	}
+	if symmetry != "general" && symmetry != "symmetric" && symmetry != "skew-symmetric" {
+		return nil, fmt.Errorf("unsupported Matrix Market symmetry %q", symmetry)
+	}
+
+	precision := DefaultPrecision
+	var data []string
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "%") {
+			comment := strings.TrimSpace(strings.TrimLeft(line, "%"))
+			if value, ok := strings.CutPrefix(comment, "precision:"); ok {
+				p, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid precision value: %v", err)
+				}
+				precision = p
+			}
+			continue
+		}
+		if line != "" {
+			data = append(data, line)
+		}
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing size line")
+	}
+
+	size := strings.Fields(data[0])
+	if len(size) < 2 {
+		return nil, fmt.Errorf("invalid size line")
+	}
+	rows, errR := strconv.Atoi(size[0])
+	cols, errC := strconv.Atoi(size[1])
+	if errR != nil || errC != nil || rows <= 0 {
+		return nil, fmt.Errorf("invalid size line")
+	}
+	if cols != rows+1 {
+		return nil, fmt.Errorf("expected an augmented %dx%d matrix [A|b], got %dx%d", rows, rows+1, rows, cols)
+	}
+	n := rows
+
+	matrix := newBuilder(n)
+	vector := make([]float64, n)
+	set := func(i, j int, v float64) {
+		if j == n {
+			vector[i] += v
+			return
+		}
+		matrix.add(i, j, v)
+		if i != j && symmetry == "symmetric" {
+			matrix.add(j, i, v)
+		} else if i != j && symmetry == "skew-symmetric" {
+			matrix.add(j, i, -v)
+		}
+	}
+
+	if layout == "coordinate" {
+		if len(size) != 3 {
+			return nil, fmt.Errorf("coordinate size line must be \"rows cols entries\"")
+		}
+		nnz, err := strconv.Atoi(size[2])
+		if err != nil || nnz < 0 {
+			return nil, fmt.Errorf("invalid number of entries")
+		}
+		if len(data)-1 != nnz {
+			return nil, fmt.Errorf("expected %d entries, got %d", nnz, len(data)-1)
+		}
+		for k, line := range data[1:] {
+			fields := strings.Fields(line)
+			want := 3
+			if field == "pattern" {
+				want = 2
+			}
+			if len(fields) != want {
+				return nil, fmt.Errorf("invalid entry %d", k+1)
+			}
+			i, errI := strconv.Atoi(fields[0])
+			j, errJ := strconv.Atoi(fields[1])
+			if errI != nil || errJ != nil || i < 1 || i > n || j < 1 || j > n+1 {
+				return nil, fmt.Errorf("invalid index in entry %d", k+1)
+			}
+			v := 1.0
+			if field != "pattern" {
+				if v, err = strconv.ParseFloat(fields[2], 64); err != nil {
+					return nil, fmt.Errorf("invalid value in entry %d: %v", k+1, err)
+				}
+			}
+			set(i-1, j-1, v)
+		}
+		return matrix.system(vector, precision)
+	}
+
+	// Array layout lists values in column-major order
+	var values []float64
+	for _, line := range data[1:] {
+		for _, f := range strings.Fields(line) {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value: %v", err)
+			}
+			values = append(values, v)
+		}
+	}
+	k := 0
+	for j := 0; j <= n; j++ {
+		start := 0
+		if symmetry != "general" && j < n {
+			// Only the lower triangle of A is stored
+			start = j
+			if symmetry == "skew-symmetric" {
+				start = j + 1
+			}
+		}
+		for i := start; i < n; i++ {
+			if k >= len(values) {
+				return nil, fmt.Errorf("insufficient matrix data")
+			}
+			set(i, j, values[k])
+			k++
+		}
+	}
+	if k != len(values) {
+		return nil, fmt.Errorf("too many values: expected %d, got %d", k, len(values))
+	}
+	return matrix.system(vector, precision)
+}
+
+// writeSystemMatrixMarket writes [A|b] in coordinate format with the
+// precision in a comment.
+func writeSystemMatrixMarket(w io.Writer, s *System) error {
+	bw := bufio.NewWriter(w)
+	n := s.Dimension()
+	bw.WriteString("%%MatrixMarket matrix coordinate real general\n")
+	fmt.Fprintf(bw, "%% precision: %s\n", formatFloat(s.Precision))
+
+	rhs := 0
+	for _, v := range s.Vector {
+		if v != 0 {
+			rhs++
+		}
+	}
+	fmt.Fprintf(bw, "%d %d %d\n", n, n+1, s.nonZeros()+rhs)
+
+	s.entries(func(i, j int, v float64) {
+		fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, formatFloat(v))
+	})
+	for i, v := range s.Vector {
+		if v != 0 {
+			fmt.Fprintf(bw, "%d %d %s\n", i+1, n+1, formatFloat(v))
+		}
+	}
+	return bw.Flush()
+}
+
+// writeSolutionMatrixMarket writes an n×2 array whose columns are x and the
+// final errors, with the method and iteration count in comments.
+func writeSolutionMatrixMarket(w io.Writer, res *solver.Result) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("%%MatrixMarket matrix array real general\n")
+	fmt.Fprintf(bw, "%% method: %s\n", res.Method)
+	fmt.Fprintf(bw, "%% iterations: %d\n", res.Iterations)
+	bw.WriteString("% columns: solution, error\n")
+	fmt.Fprintf(bw, "%d 2\n", len(res.Solution))
+	for _, v := range res.Solution {
+		bw.WriteString(formatFloat(v) + "\n")
+	}
+	for _, e := range res.Errors {
+		bw.WriteString(formatFloat(e) + "\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,184 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"calcMat/solver"
+)
+
+// ParseText reads a system in the native lab1 layout. The dense layout is
+// the dimension n, n rows of n+1 numbers (a row of A and bᵢ) and the
+// precision. The coordinate layout starts with "n nnz", followed by nnz
+// lines "i j aᵢⱼ" (1-based), the n entries of b and the precision.
+func ParseText(input string) (*System, error) {
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("insufficient input data")
+	}
+
+	header := strings.Fields(lines[0])
+	if len(header) == 2 {
+		return parseCoordinateText(header, lines[1:])
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid dimension: %v", err)
+	}
+
+	if n <= 0 {
+		return nil, fmt.Errorf("dimension must be positive")
+	}
+
+	if len(lines) < n+2 {
+		return nil, fmt.Errorf("insufficient matrix data")
+	}
+
+	matrix := newBuilder(n)
+	vector := make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		nums := strings.Fields(lines[i+1])
+		if len(nums) != n+1 {
+			return nil, fmt.Errorf("invalid number of coefficients in row %d", i+1)
+		}
+
+		for j := 0; j < n; j++ {
+			val, err := strconv.ParseFloat(nums[j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid coefficient at row %d, col %d: %v", i+1, j+1, err)
+			}
+			matrix.add(i, j, val)
+		}
+
+		bVal, err := strconv.ParseFloat(nums[n], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid right-hand side value at row %d: %v", i+1, err)
+		}
+		vector[i] = bVal
+	}
+
+	precision, err := strconv.ParseFloat(strings.TrimSpace(lines[n+1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid precision value: %v", err)
+	}
+
+	return matrix.system(vector, precision)
+}
+
+func parseCoordinateText(header []string, lines []string) (*System, error) {
+	n, err := strconv.Atoi(header[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid dimension: %v", err)
+	}
+	nnz, err := strconv.Atoi(header[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid number of entries: %v", err)
+	}
+	if n <= 0 || nnz < 0 {
+		return nil, fmt.Errorf("dimension must be positive")
+	}
+	if len(lines) < nnz+2 {
+		return nil, fmt.Errorf("insufficient matrix data")
+	}
+
+	matrix := newBuilder(n)
+	for k := 0; k < nnz; k++ {
+		fields := strings.Fields(lines[k])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("entry %d must be \"row col value\"", k+1)
+		}
+		i, errI := strconv.Atoi(fields[0])
+		j, errJ := strconv.Atoi(fields[1])
+		if errI != nil || errJ != nil || i < 1 || i > n || j < 1 || j > n {
+			return nil, fmt.Errorf("invalid index in entry %d", k+1)
+		}
+		val, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coefficient in entry %d: %v", k+1, err)
+		}
+		matrix.add(i-1, j-1, val)
+	}
+
+	rest := lines[nnz:]
+	vector := make([]float64, 0, n)
+	for _, line := range rest[:len(rest)-1] {
+		for _, field := range strings.Fields(line) {
+			val, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid right-hand side value: %v", err)
+			}
+			vector = append(vector, val)
+		}
+	}
+	if len(vector) != n {
+		return nil, fmt.Errorf("expected %d right-hand side values, got %d", n, len(vector))
+	}
+
+	precision, err := strconv.ParseFloat(strings.TrimSpace(rest[len(rest)-1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid precision value: %v", err)
+	}
+
+	return matrix.system(vector, precision)
+}
+
+// writeSystemText uses the dense layout for dense systems and the
+// coordinate layout for sparse ones.
+func writeSystemText(w io.Writer, s *System) error {
+	bw := bufio.NewWriter(w)
+	n := s.Dimension()
+	if s.Sparse != nil {
+		fmt.Fprintf(bw, "%d %d\n", n, s.nonZeros())
+		s.entries(func(i, j int, v float64) {
+			fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, formatFloat(v))
+		})
+		for i, v := range s.Vector {
+			if i > 0 {
+				bw.WriteString(" ")
+			}
+			bw.WriteString(formatFloat(v))
+		}
+		bw.WriteString("\n")
+	} else {
+		fmt.Fprintf(bw, "%d\n", n)
+		for i, row := range s.Matrix {
+			for _, v := range row {
+				bw.WriteString(formatFloat(v) + " ")
+			}
+			bw.WriteString(formatFloat(s.Vector[i]) + "\n")
+		}
+	}
+	fmt.Fprintf(bw, "%s\n", formatFloat(s.Precision))
+	return bw.Flush()
+}
+
+func writeSolutionText(w io.Writer, res *solver.Result) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "Method: %s\n", res.Method)
+	fmt.Fprintf(bw, "Iterations: %d\n\n", res.Iterations)
+	bw.WriteString("Solution vector:\n")
+	for i, v := range res.Solution {
+		if res.ExactSolution != nil {
+			fmt.Fprintf(bw, "x%d = %s\n", i+1, res.ExactSolution[i])
+		} else {
+			fmt.Fprintf(bw, "x%d = %s\n", i+1, formatFloat(v))
+		}
+	}
+	bw.WriteString("\nFinal errors:\n")
+	for i, e := range res.Errors {
+		fmt.Fprintf(bw, "e%d = %s\n", i+1, formatFloat(e))
+	}
+	return bw.Flush()
+}
+
+// formatFloat prints v with the shortest representation that reads back
+// exactly.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
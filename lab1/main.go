@@ -1,11 +1,17 @@
 package main
 
 import (
+	"calcMat/cli"
 	"calcMat/ui"
 	"log"
+	"os"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "solve" {
+		os.Exit(cli.Run(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	p := ui.NewProgram()
 	if err := p.Start(); err != nil {
 		log.Fatal("Error running program:", err)
@@ -0,0 +1,330 @@
+// Package session saves and restores the state of a lab1 TUI session: the
+// system, the solver settings and the last result.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"calcMat/solver"
+)
+
+// Session is the content of a session file.
+type Session struct {
+	Name      string      `json:"name"`
+	Saved     time.Time   `json:"saved"`
+	Matrix    [][]float64 `json:"A,omitempty"`
+	Sparse    *solver.CSR `json:"sparse,omitempty"`
+	Vector    []float64   `json:"b"`
+	Precision float64     `json:"precision,omitempty"`
+	Settings  Settings    `json:"settings"`
+	Result    *Result     `json:"result,omitempty"`
+}
+
+// Settings are the solver options chosen in the TUI, stored by name.
+type Settings struct {
+	Method         string  `json:"method"`
+	Omega          float64 `json:"omega,omitempty"`
+	Preconditioner string  `json:"preconditioner,omitempty"`
+	Backend        string  `json:"backend,omitempty"`
+	Stop           string  `json:"stop,omitempty"`
+}
+
+// NewSettings records the given solver options.
+func NewSettings(method solver.Method, omega float64, precond solver.Preconditioner, backend solver.Backend, stop solver.StopCriterion) Settings {
+	return Settings{
+		Method:         method.Name(),
+		Omega:          omega,
+		Preconditioner: precond.String(),
+		Backend:        backend.String(),
+		Stop:           stop.String(),
+	}
+}
+
+// Options parses the stored settings back into solver options.
+func (s Settings) Options() (solver.Options, error) {
+	var opts solver.Options
+	var err error
+	if opts.Method, err = solver.ParseMethod(s.Method); err != nil {
+		return opts, err
+	}
+	if opts.Preconditioner, err = solver.ParsePreconditioner(s.Preconditioner); err != nil {
+		return opts, err
+	}
+	if opts.Backend, err = solver.ParseBackend(s.Backend); err != nil {
+		return opts, err
+	}
+	if s.Stop != "" {
+		if opts.Stop, err = solver.ParseStopCriterion(s.Stop); err != nil {
+			return opts, err
+		}
+	}
+	opts.Omega = s.Omega
+	return opts, nil
+}
+
+// Result is the part of a solver.Result the result screen shows. JSON has
+// no infinity, so an unbounded a posteriori estimate is stored as null.
+type Result struct {
+	Solution          []float64           `json:"x"`
+	Iterations        int                 `json:"iterations"`
+	Errors            []float64           `json:"errors,omitempty"`
+	Residuals         []float64           `json:"residuals,omitempty"`
+	MatrixNorm        float64             `json:"matrixNorm"`
+	Method            string              `json:"method"`
+	Omega             float64             `json:"omega,omitempty"`
+	Determinant       float64             `json:"determinant,omitempty"`
+	Convergence       *solver.Convergence `json:"convergence,omitempty"`
+	Q                 float64             `json:"q,omitempty"`
+	BoundsEstimated   bool                `json:"boundsEstimated,omitempty"`
+	APrioriIterations int                 `json:"aprioriIterations,omitempty"`
+	APosterioriBound  *float64            `json:"aposterioriBound,omitempty"`
+	Backend           string              `json:"backend,omitempty"`
+	ExactSolution     []string            `json:"exact,omitempty"`
+}
+
+// NewResult records r. The iteration trace is not saved.
+func NewResult(r *solver.Result) *Result {
+	saved := &Result{
+		Solution:          r.Solution,
+		Iterations:        r.Iterations,
+		Errors:            r.Errors,
+		Residuals:         r.Residuals,
+		MatrixNorm:        r.MatrixNorm,
+		Method:            r.Method.Name(),
+		Omega:             r.Omega,
+		Determinant:       r.Determinant,
+		Q:                 r.Q,
+		BoundsEstimated:   r.BoundsEstimated,
+		APrioriIterations: r.APrioriIterations,
+		Backend:           r.Backend.String(),
+		ExactSolution:     r.ExactSolution,
+	}
+	if c := r.Convergence; c != nil && finite(c.NormInf, c.NormOne, c.NormFrobenius, c.JacobiRadius, c.SpectralRadius, c.SweepNorm) {
+		saved.Convergence = c
+	}
+	if finite(r.APosterioriBound) {
+		bound := r.APosterioriBound
+		saved.APosterioriBound = &bound
+	}
+	return saved
+}
+
+// Restore converts the saved result back into a solver.Result.
+func (r *Result) Restore() (*solver.Result, error) {
+	method, err := solver.ParseMethod(r.Method)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := solver.ParseBackend(r.Backend)
+	if err != nil {
+		return nil, err
+	}
+	res := &solver.Result{
+		Solution:          r.Solution,
+		Iterations:        r.Iterations,
+		Errors:            r.Errors,
+		Residuals:         r.Residuals,
+		MatrixNorm:        r.MatrixNorm,
+		Method:            method,
+		Omega:             r.Omega,
+		Determinant:       r.Determinant,
+		Convergence:       r.Convergence,
+		Q:                 r.Q,
+		BoundsEstimated:   r.BoundsEstimated,
+		APrioriIterations: r.APrioriIterations,
+		APosterioriBound:  math.Inf(1),
+		Backend:           backend,
+		ExactSolution:     r.ExactSolution,
+	}
+	if r.APosterioriBound != nil {
+		res.APosterioriBound = *r.APosterioriBound
+	}
+	return res, nil
+}
+
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Dir returns the directory session files are kept in, creating it if
+// needed: calcMat/sessions under the user configuration directory.
+func Dir() (string, error) {
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(config, "calcMat", "sessions")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Save writes s to the session directory under a file name derived from
+// s.Name, replacing an earlier session of the same name, and returns the
+// path written. Different names that map to the same file name, such as
+// "a b" and "a/b", are kept apart by a numeric suffix.
+func Save(s *Session) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	s.Saved = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := sessionPath(dir, s.Name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// sessionPath returns the file the session called name is saved to: the
+// file of an earlier session with that name, otherwise the first of
+// stem.json, stem-2.json, ... that does not exist yet. A file that cannot
+// be read is never reused.
+func sessionPath(dir, name string) string {
+	stem := fileStem(name)
+	for k := 1; ; k++ {
+		file := stem + ".json"
+		if k > 1 {
+			file = fmt.Sprintf("%s-%d.json", stem, k)
+		}
+		path := filepath.Join(dir, file)
+		entry, err := readEntry(path)
+		if errors.Is(err, fs.ErrNotExist) || err == nil && entry.Name == name {
+			return path
+		}
+	}
+}
+
+// fileStem turns a session name into a safe file name without extension.
+func fileStem(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString("session")
+	}
+	return b.String()
+}
+
+// Load reads a session file.
+func Load(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %v", filepath.Base(path), err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %v", filepath.Base(path), err)
+	}
+	return &s, nil
+}
+
+// validate checks that the system in s has consistent sizes, so that a
+// hand-edited or truncated file is rejected on load rather than making the
+// solvers index out of range.
+func (s *Session) validate() error {
+	n := len(s.Vector)
+	if s.Sparse != nil {
+		if s.Sparse.N != n {
+			return fmt.Errorf("A and b have different sizes")
+		}
+		if err := s.Sparse.Validate(); err != nil {
+			return err
+		}
+	} else {
+		if len(s.Matrix) != n {
+			return fmt.Errorf("A and b have different sizes")
+		}
+		for i, row := range s.Matrix {
+			if len(row) != n {
+				return fmt.Errorf("row %d of A has %d entries, want %d", i+1, len(row), n)
+			}
+		}
+	}
+	if r := s.Result; r != nil {
+		for _, v := range [][]float64{r.Errors, r.Residuals} {
+			if len(r.Solution) != n || len(v) != 0 && len(v) != n {
+				return fmt.Errorf("the saved result does not match the size of the system")
+			}
+		}
+	}
+	return nil
+}
+
+// Entry describes a saved session.
+type Entry struct {
+	Name  string
+	Path  string
+	Saved time.Time
+}
+
+// Recent lists up to limit saved sessions, most recently saved first.
+func Recent(limit int) ([]Entry, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, path := range files {
+		if entry, err := readEntry(path); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Saved.After(entries[j].Saved)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// readEntry decodes only the header of a session file; the system is
+// checked when the session is actually loaded.
+func readEntry(path string) (Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	var header struct {
+		Name  string    `json:"name"`
+		Saved time.Time `json:"saved"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Entry{}, err
+	}
+	return Entry{Name: header.Name, Path: path, Saved: header.Saved}, nil
+}
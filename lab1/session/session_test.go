@@ -0,0 +1,165 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"calcMat/solver"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{"dense", `{"A": [[2, 1], [1, 3]], "b": [1, 2]}`, false},
+		{"sparse", `{"sparse": {"N": 2, "RowPtr": [0, 2, 3], "ColIdx": [0, 1, 1], "Vals": [2, 1, 3]}, "b": [1, 2]}`, false},
+		{"short row", `{"A": [[2, 1], [1]], "b": [1, 2]}`, true},
+		{"b too long", `{"A": [[2, 1], [1, 3]], "b": [1, 2, 3]}`, true},
+		{"column out of range", `{"sparse": {"N": 2, "RowPtr": [0, 2, 3], "ColIdx": [0, 2, 1], "Vals": [2, 1, 3]}, "b": [1, 2]}`, true},
+		{"negative column", `{"sparse": {"N": 2, "RowPtr": [0, 1, 2], "ColIdx": [-1, 1], "Vals": [2, 3]}, "b": [1, 2]}`, true},
+		{"unsorted columns", `{"sparse": {"N": 2, "RowPtr": [0, 2, 3], "ColIdx": [1, 0, 1], "Vals": [1, 2, 3]}, "b": [1, 2]}`, true},
+		{"row offsets past the end", `{"sparse": {"N": 2, "RowPtr": [0, 2, 4], "ColIdx": [0, 1, 1], "Vals": [2, 1, 3]}, "b": [1, 2]}`, true},
+		{"decreasing row offsets", `{"sparse": {"N": 2, "RowPtr": [0, 3, 2], "ColIdx": [0, 1, 1], "Vals": [2, 1, 3]}, "b": [1, 2]}`, true},
+		{"result of another size", `{"A": [[2, 1], [1, 3]], "b": [1, 2], "result": {"x": [1], "method": "jacobi"}}`, true},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "session.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecentReadsOnlyHeaders(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"old.json":     `{"name": "old", "saved": "2024-01-01T00:00:00Z", "A": [[1]], "b": [1]}`,
+		"new.json":     `{"name": "new", "saved": "2024-02-01T00:00:00Z", "A": [[1, 2]], "b": [1]}`,
+		"garbage.json": `not json`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := Recent(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name != "new" || entries[1].Name != "old" {
+		t.Errorf("Recent() = %+v, want new and old", entries)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	A := [][]float64{{4, 1}, {1, 3}}
+	b := []float64{2, 1}
+	tests := []struct {
+		name string
+		opts solver.Options
+	}{
+		{"jacobi", solver.Options{Method: solver.Jacobi}},
+		{"sor", solver.Options{Method: solver.SOR, Omega: 1.2, Stop: solver.StopResidual}},
+		{"rational", solver.Options{Method: solver.Gauss, Backend: solver.Rational}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := solver.Solve(A, b, 1e-8, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved := &Session{
+				Name:      tt.name,
+				Matrix:    A,
+				Vector:    b,
+				Precision: 1e-8,
+				Settings:  NewSettings(tt.opts.Method, tt.opts.Omega, tt.opts.Preconditioner, tt.opts.Backend, tt.opts.Stop),
+				Result:    NewResult(res),
+			}
+			path, err := Save(saved)
+			if err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded.Name != tt.name || !loaded.Saved.Equal(saved.Saved) || loaded.Precision != 1e-8 ||
+				!reflect.DeepEqual(loaded.Matrix, A) || !reflect.DeepEqual(loaded.Vector, b) {
+				t.Errorf("loaded %+v, want %+v", loaded, saved)
+			}
+			opts, err := loaded.Settings.Options()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.Method != tt.opts.Method || opts.Omega != tt.opts.Omega || opts.Backend != tt.opts.Backend || opts.Stop != tt.opts.Stop {
+				t.Errorf("settings restored as %+v, want %+v", opts, tt.opts)
+			}
+			restored, err := loaded.Result.Restore()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(restored, res) {
+				t.Errorf("result restored as %+v, want %+v", restored, res)
+			}
+		})
+	}
+}
+
+func TestSaveKeepsDistinctNames(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	paths := make(map[string]string)
+	for _, name := range []string{"a b", "a_b", "a/b", "a b"} {
+		path, err := Save(&Session{Name: name, Matrix: [][]float64{{1}}, Vector: []float64{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if earlier, ok := paths[name]; ok && earlier != path {
+			t.Errorf("%q saved again to %s, want %s", name, path, earlier)
+		}
+		paths[name] = path
+	}
+	seen := make(map[string]string)
+	for name, path := range paths {
+		if other, ok := seen[path]; ok {
+			t.Errorf("%q and %q share %s", name, other, path)
+		}
+		seen[path] = name
+		s, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Name != name {
+			t.Errorf("%s holds %q, want %q", path, s.Name, name)
+		}
+	}
+	entries, err := Recent(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("Recent() = %+v, want 3 sessions", entries)
+	}
+}
@@ -0,0 +1,215 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MatrixAnalysis collects the determinant, inverse and condition numbers of
+// a square matrix, all derived from one LU factorization.
+type MatrixAnalysis struct {
+	Determinant float64
+	// Inverse is nil when the matrix is singular.
+	Inverse [][]float64
+	// Singular reports that LU found no usable pivot; the condition numbers
+	// are then +Inf.
+	Singular      bool
+	NormOne       float64
+	NormInf       float64
+	NormFrobenius float64
+	Norm2         float64
+	CondOne       float64
+	CondInf       float64
+	CondFrobenius float64
+	// Cond2 is the spectral condition number σmax/σmin, with both singular
+	// values estimated by power iteration.
+	Cond2 float64
+}
+
+// Inverse returns A⁻¹ by solving Ax = eᵢ for every unit vector.
+func (f *LU) Inverse() [][]float64 {
+	inv, _ := f.inverse(context.Background())
+	return inv
+}
+
+// inverse is Inverse checking ctx before every column.
+func (f *LU) inverse(ctx context.Context) ([][]float64, error) {
+	n := len(f.lu)
+	inv := make([][]float64, n)
+	for i := range inv {
+		inv[i] = make([]float64, n)
+	}
+	e := make([]float64, n)
+	for j := 0; j < n; j++ {
+		if err := cancelled(ctx); err != nil {
+			return nil, err
+		}
+		e[j] = 1
+		col, _ := f.Solve(e)
+		e[j] = 0
+		for i := 0; i < n; i++ {
+			inv[i][j] = col[i]
+		}
+	}
+	return inv, nil
+}
+
+// Determinant returns det(A); it is 0 for a singular matrix. Other
+// factorization errors are returned as they are.
+func Determinant(A [][]float64) (float64, error) {
+	if err := checkSquare(A); err != nil {
+		return 0, err
+	}
+	f, err := Factorize(A)
+	if errors.Is(err, ErrSingular) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return f.Determinant(), nil
+}
+
+// Inverse returns A⁻¹.
+func Inverse(A [][]float64) ([][]float64, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+	f, err := Factorize(A)
+	if err != nil {
+		return nil, err
+	}
+	return f.Inverse(), nil
+}
+
+// AnalyzeMatrix computes the determinant, the inverse and the condition
+// number ‖A‖·‖A⁻¹‖ in the 1-, ∞-, Frobenius and 2-norms.
+func AnalyzeMatrix(A [][]float64) (*MatrixAnalysis, error) {
+	return AnalyzeMatrixContext(context.Background(), A)
+}
+
+// AnalyzeMatrixContext is AnalyzeMatrix with cancellation: the
+// factorization, the inversion and the power iterations behind ‖·‖₂ check
+// ctx at every step and return its error, wrapped, once it is done.
+func AnalyzeMatrixContext(ctx context.Context, A [][]float64) (*MatrixAnalysis, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+
+	a := &MatrixAnalysis{
+		NormOne:       normOne(A),
+		NormInf:       calculateNorm(A),
+		NormFrobenius: normFrobenius(A),
+	}
+	var err error
+	if a.Norm2, err = norm2(ctx, A); err != nil {
+		return nil, err
+	}
+
+	f, err := factorize(ctx, A)
+	if err != nil && !errors.Is(err, ErrSingular) {
+		return nil, err
+	}
+	if err != nil {
+		a.Singular = true
+		a.CondOne = math.Inf(1)
+		a.CondInf = math.Inf(1)
+		a.CondFrobenius = math.Inf(1)
+		a.Cond2 = math.Inf(1)
+		return a, nil
+	}
+
+	a.Determinant = f.Determinant()
+	if a.Inverse, err = f.inverse(ctx); err != nil {
+		return nil, err
+	}
+	a.CondOne = a.NormOne * normOne(a.Inverse)
+	a.CondInf = a.NormInf * calculateNorm(a.Inverse)
+	a.CondFrobenius = a.NormFrobenius * normFrobenius(a.Inverse)
+	norm2Inv, err := norm2(ctx, a.Inverse)
+	if err != nil {
+		return nil, err
+	}
+	a.Cond2 = a.Norm2 * norm2Inv
+	return a, nil
+}
+
+func checkSquare(A [][]float64) error {
+	if len(A) == 0 {
+		return fmt.Errorf("invalid matrix dimensions")
+	}
+	for i := range A {
+		if len(A[i]) != len(A) {
+			return fmt.Errorf("matrix must be square")
+		}
+	}
+	return nil
+}
+
+// normOne returns the largest absolute column sum of A.
+func normOne(A [][]float64) float64 {
+	n := len(A)
+	maxSum := 0.0
+	for j := 0; j < n; j++ {
+		sum := 0.0
+		for i := 0; i < n; i++ {
+			sum += math.Abs(A[i][j])
+		}
+		maxSum = math.Max(maxSum, sum)
+	}
+	return maxSum
+}
+
+func normFrobenius(A [][]float64) float64 {
+	sum := 0.0
+	for _, row := range A {
+		for _, v := range row {
+			sum += v * v
+		}
+	}
+	return math.Sqrt(sum)
+}
+
+// norm2 estimates the largest singular value of A as the square root of
+// the dominant eigenvalue of AᵀA, found by power iteration. It fails once
+// ctx is done.
+func norm2(ctx context.Context, A [][]float64) (float64, error) {
+	n := len(A)
+	v := make([]float64, n)
+	av := make([]float64, n)
+	for i := range v {
+		v[i] = 1 / math.Sqrt(float64(n))
+	}
+
+	lambda := 0.0
+	for k := 0; k < 500; k++ {
+		if err := cancelled(ctx); err != nil {
+			return 0, err
+		}
+		for i := 0; i < n; i++ {
+			av[i] = dot(A[i], v)
+		}
+		// w = Aᵀ(Av)
+		w := make([]float64, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				w[j] += A[i][j] * av[i]
+			}
+		}
+		norm := math.Sqrt(dot(w, w))
+		if norm == 0 {
+			return 0, nil
+		}
+		for i := range v {
+			v[i] = w[i] / norm
+		}
+		if math.Abs(norm-lambda) <= 1e-12*norm {
+			lambda = norm
+			break
+		}
+		lambda = norm
+	}
+	return math.Sqrt(lambda), nil
+}
@@ -0,0 +1,56 @@
+package solver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       [][]float64
+		want    float64
+		wantErr bool
+	}{
+		{"identity", [][]float64{{1, 0}, {0, 1}}, 1, false},
+		{"needs pivoting", [][]float64{{0, 1}, {1, 0}}, -1, false},
+		{"3x3", [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}, 4, false},
+		{"singular", [][]float64{{1, 2}, {2, 4}}, 0, false},
+		{"not square", [][]float64{{1, 2}, {3}}, 0, true},
+		{"empty", nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Determinant(tt.A)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("det = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMatrix(t *testing.T) {
+	a, err := AnalyzeMatrix([][]float64{{2, 0}, {0, 0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Singular || math.Abs(a.Determinant-1) > 1e-12 {
+		t.Errorf("det = %g, singular = %v", a.Determinant, a.Singular)
+	}
+	for name, cond := range map[string]float64{"1": a.CondOne, "∞": a.CondInf, "2": a.Cond2} {
+		if math.Abs(cond-4) > 1e-9 {
+			t.Errorf("cond_%s = %g, want 4", name, cond)
+		}
+	}
+
+	a, err = AnalyzeMatrix([][]float64{{1, 2}, {2, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Singular || !math.IsInf(a.CondInf, 1) || a.Inverse != nil {
+		t.Errorf("singular matrix analysed as %+v", a)
+	}
+}
@@ -0,0 +1,373 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Backend selects the number type the solver computes with.
+//
+// Matrices reach the solver as float64, so the big.Float and big.Rat
+// backends take every coefficient as the shortest decimal that rounds to
+// it: decimal input with up to 15 significant digits, such as 0.1, enters
+// exactly (1/10 rather than the binary 3602879701896397/36028797018963968).
+// Longer decimals have already been rounded to float64 by the time the
+// backend sees them.
+type Backend int
+
+const (
+	Float64 Backend = iota
+	// BigFloat uses math/big.Float with Options.Bits of mantissa.
+	BigFloat
+	// Rational uses math/big.Rat; it is exact and only supported by
+	// Gaussian elimination.
+	Rational
+)
+
+// defaultBits is the big.Float mantissa size used when Options.Bits is 0.
+const defaultBits = 256
+
+func (b Backend) String() string {
+	switch b {
+	case Float64:
+		return "float64"
+	case BigFloat:
+		return "big.Float"
+	case Rational:
+		return "big.Rat"
+	default:
+		return fmt.Sprintf("Backend(%d)", int(b))
+	}
+}
+
+// ParseBackend returns the backend with the given short name: float64,
+// bigfloat or rational.
+func ParseBackend(name string) (Backend, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "float64", "float":
+		return Float64, nil
+	case "bigfloat", "big", "big.float":
+		return BigFloat, nil
+	case "rational", "rat", "exact", "big.rat":
+		return Rational, nil
+	default:
+		return 0, fmt.Errorf("unknown backend %q", name)
+	}
+}
+
+// arith is the arithmetic the generic solvers need from a number type.
+type arith[T any] interface {
+	fromFloat(v float64) T
+	add(a, b T) T
+	sub(a, b T) T
+	mul(a, b T) T
+	quo(a, b T) T
+	abs(a T) T
+	cmp(a, b T) int
+	isZero(a T) bool
+	float(a T) float64
+	text(a T) string
+}
+
+type bigFloatArith struct{ prec uint }
+
+func (r bigFloatArith) new() *big.Float { return new(big.Float).SetPrec(r.prec) }
+
+func (r bigFloatArith) fromFloat(v float64) *big.Float { return decimalFloat(v, r.prec) }
+func (r bigFloatArith) add(a, b *big.Float) *big.Float { return r.new().Add(a, b) }
+func (r bigFloatArith) sub(a, b *big.Float) *big.Float { return r.new().Sub(a, b) }
+func (r bigFloatArith) mul(a, b *big.Float) *big.Float { return r.new().Mul(a, b) }
+func (r bigFloatArith) quo(a, b *big.Float) *big.Float { return r.new().Quo(a, b) }
+func (r bigFloatArith) abs(a *big.Float) *big.Float    { return r.new().Abs(a) }
+func (r bigFloatArith) cmp(a, b *big.Float) int        { return a.Cmp(b) }
+func (r bigFloatArith) isZero(a *big.Float) bool       { return a.Sign() == 0 }
+func (r bigFloatArith) float(a *big.Float) float64     { f, _ := a.Float64(); return f }
+func (r bigFloatArith) text(a *big.Float) string {
+	digits := int(float64(r.prec) * math.Log10(2))
+	return a.Text('g', digits)
+}
+
+type ratArith struct{}
+
+func (ratArith) fromFloat(v float64) *big.Rat { return decimalRat(v) }
+func (ratArith) add(a, b *big.Rat) *big.Rat   { return new(big.Rat).Add(a, b) }
+func (ratArith) sub(a, b *big.Rat) *big.Rat   { return new(big.Rat).Sub(a, b) }
+func (ratArith) mul(a, b *big.Rat) *big.Rat   { return new(big.Rat).Mul(a, b) }
+func (ratArith) quo(a, b *big.Rat) *big.Rat   { return new(big.Rat).Quo(a, b) }
+func (ratArith) abs(a *big.Rat) *big.Rat      { return new(big.Rat).Abs(a) }
+func (ratArith) cmp(a, b *big.Rat) int        { return a.Cmp(b) }
+func (ratArith) isZero(a *big.Rat) bool       { return a.Sign() == 0 }
+func (ratArith) float(a *big.Rat) float64     { f, _ := a.Float64(); return f }
+func (ratArith) text(a *big.Rat) string       { return a.RatString() }
+
+// decimalFloat and decimalRat convert v through the shortest decimal that
+// rounds to it, which is the number the user wrote whenever it had at most
+// 15 significant digits.
+func decimalFloat(v float64, prec uint) *big.Float {
+	f := new(big.Float).SetPrec(prec)
+	if _, ok := f.SetString(strconv.FormatFloat(v, 'g', -1, 64)); ok {
+		return f
+	}
+	return f.SetFloat64(v)
+}
+
+func decimalRat(v float64) *big.Rat {
+	if q, ok := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64)); ok {
+		return q
+	}
+	return new(big.Rat).SetFloat64(v)
+}
+
+// solveBig solves Ax = b with a big.Float or big.Rat backend. The
+// convergence analysis is done in float64; only the solve itself uses the
+// wider type.
+func solveBig(A [][]float64, b []float64, precision float64, opts Options) (*Result, error) {
+	n := len(A)
+	if n == 0 || len(b) != n {
+		return nil, fmt.Errorf("invalid matrix or vector dimensions")
+	}
+
+	switch opts.Backend {
+	case BigFloat:
+		bits := opts.Bits
+		if bits == 0 {
+			bits = defaultBits
+		}
+		return solveWith[*big.Float](bigFloatArith{prec: bits}, A, b, precision, opts)
+	case Rational:
+		if opts.Method != Gauss {
+			return nil, fmt.Errorf("the %s backend only supports Gaussian elimination", opts.Backend)
+		}
+		return solveWith[*big.Rat](ratArith{}, A, b, precision, opts)
+	default:
+		return nil, fmt.Errorf("unknown backend %s", opts.Backend)
+	}
+}
+
+func solveWith[T any](ar arith[T], A [][]float64, b []float64, precision float64, opts Options) (*Result, error) {
+	var x []T
+	res := &Result{Method: opts.Method, Backend: opts.Backend}
+
+	switch opts.Method {
+	case Gauss:
+		var det T
+		var err error
+		x, det, err = gaussianEliminationWith(opts.ctx, ar, A, b)
+		if err != nil {
+			return nil, err
+		}
+		res.Errors = make([]float64, len(b))
+		res.MatrixNorm = calculateNorm(A)
+		res.Determinant = ar.float(det)
+	case Jacobi, GaussSeidel, SOR:
+		if opts.Method == SOR && opts.Omega != 0 && (opts.Omega <= 0 || opts.Omega >= 2) {
+			return nil, fmt.Errorf("relaxation factor must be in (0, 2), got %g", opts.Omega)
+		}
+		conv, omega, err := AnalyzeConvergence(A, opts)
+		if err != nil {
+			return nil, err
+		}
+		orderedA := permuteRows(A, conv.Permutation)
+		orderedB := make([]float64, len(b))
+		for i, r := range conv.Permutation {
+			orderedB[i] = b[r]
+		}
+		var firstStep float64
+		if x, firstStep, err = iterateWith(ar, orderedA, orderedB, precision, opts, omega, res); err != nil {
+			return nil, err
+		}
+		res.MatrixNorm = conv.NormInf
+		res.Omega = omega
+		res.Convergence = conv
+		res.Q, res.BoundsEstimated = conv.contraction()
+		res.APosterioriBound = math.Inf(1)
+		if res.Q < 1 {
+			res.APosterioriBound = res.Q / (1 - res.Q) * maxNorm(res.Errors)
+		}
+		res.APrioriIterations = aprioriIterations(res.Q, precision, firstStep)
+	default:
+		return nil, fmt.Errorf("the %s backend supports Jacobi, Gauss-Seidel, SOR and Gaussian elimination only", opts.Backend)
+	}
+
+	res.Solution = make([]float64, len(x))
+	res.ExactSolution = make([]string, len(x))
+	for i, v := range x {
+		res.Solution[i] = ar.float(v)
+		res.ExactSolution[i] = ar.text(v)
+	}
+	r := residualWith(ar, A, x, b)
+	res.Residuals = make([]float64, len(r))
+	for i, v := range r {
+		res.Residuals[i] = ar.float(v)
+	}
+	return res, nil
+}
+
+// gaussianEliminationWith is gaussianElimination over an arbitrary number
+// type. Only an exactly zero pivot is treated as singular.
+func gaussianEliminationWith[T any](ctx context.Context, ar arith[T], A [][]float64, b []float64) ([]T, T, error) {
+	n := len(A)
+	a := make([][]T, n)
+	for i := range A {
+		if len(A[i]) != n {
+			var zero T
+			return nil, zero, fmt.Errorf("matrix must be square")
+		}
+		a[i] = make([]T, n+1)
+		for j, v := range A[i] {
+			a[i][j] = ar.fromFloat(v)
+		}
+		a[i][n] = ar.fromFloat(b[i])
+	}
+
+	det := ar.fromFloat(1)
+	for k := 0; k < n; k++ {
+		if err := cancelled(ctx); err != nil {
+			var zero T
+			return nil, zero, err
+		}
+		p := k
+		for i := k + 1; i < n; i++ {
+			if ar.cmp(ar.abs(a[i][k]), ar.abs(a[p][k])) > 0 {
+				p = i
+			}
+		}
+		if ar.isZero(a[p][k]) {
+			var zero T
+			return nil, zero, ErrSingular
+		}
+		if p != k {
+			a[p], a[k] = a[k], a[p]
+			det = ar.sub(ar.fromFloat(0), det)
+		}
+		det = ar.mul(det, a[k][k])
+
+		for i := k + 1; i < n; i++ {
+			factor := ar.quo(a[i][k], a[k][k])
+			for j := k; j <= n; j++ {
+				a[i][j] = ar.sub(a[i][j], ar.mul(factor, a[k][j]))
+			}
+		}
+	}
+
+	x := make([]T, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := a[i][n]
+		for j := i + 1; j < n; j++ {
+			sum = ar.sub(sum, ar.mul(a[i][j], x[j]))
+		}
+		x[i] = ar.quo(sum, a[i][i])
+	}
+
+	return x, det, nil
+}
+
+// iterateWith runs the Jacobi, Gauss-Seidel or SOR sweep over an arbitrary
+// number type, filling Iterations, Errors and Trace of res. It also returns
+// ‖x₁ - x₀‖∞ for the a priori estimate.
+func iterateWith[T any](ar arith[T], A [][]float64, b []float64, precision float64, opts Options, omega float64, res *Result) ([]T, float64, error) {
+	n := len(A)
+	a := make([][]T, n)
+	rhs := make([]T, n)
+	x := make([]T, n)
+	xPrev := make([]T, n)
+	for i := range A {
+		a[i] = make([]T, n)
+		for j, v := range A[i] {
+			a[i][j] = ar.fromFloat(v)
+		}
+		rhs[i] = ar.fromFloat(b[i])
+		x[i] = ar.fromFloat(0)
+		xPrev[i] = x[i]
+	}
+	w := ar.fromFloat(omega)
+	oneMinusW := ar.fromFloat(1 - omega)
+
+	src := xPrev
+	if opts.Method != Jacobi {
+		src = x
+	}
+	errors := make([]T, n)
+	stop := newStopper(opts, precision)
+	firstStep := 0.0
+
+	for res.Iterations = 1; ; res.Iterations++ {
+		for i := 0; i < n; i++ {
+			sum := ar.fromFloat(0)
+			for j := 0; j < n; j++ {
+				if j != i {
+					sum = ar.add(sum, ar.mul(a[i][j], src[j]))
+				}
+			}
+			next := ar.quo(ar.sub(rhs[i], sum), a[i][i])
+			if opts.Method == SOR {
+				next = ar.add(ar.mul(oneMinusW, xPrev[i]), ar.mul(w, next))
+			}
+			x[i] = next
+		}
+
+		maxError := ar.fromFloat(0)
+		for i := 0; i < n; i++ {
+			errors[i] = ar.abs(ar.sub(x[i], xPrev[i]))
+			if ar.cmp(errors[i], maxError) > 0 {
+				maxError = errors[i]
+			}
+		}
+
+		if res.Iterations == 1 {
+			firstStep = ar.float(maxError)
+		}
+		if opts.Trace {
+			step := TraceStep{Iteration: res.Iterations, X: make([]float64, n), Difference: ar.float(maxError)}
+			for i, v := range x {
+				step.X[i] = ar.float(v)
+			}
+			for _, r := range residualWith(ar, A, x, b) {
+				step.Residual = math.Max(step.Residual, math.Abs(ar.float(r)))
+			}
+			res.Trace = append(res.Trace, step)
+		}
+
+		xNorm := 0.0
+		for _, v := range x {
+			xNorm = math.Max(xNorm, math.Abs(ar.float(v)))
+		}
+		converged, err := stop.check(res.Iterations, ar.float(maxError), xNorm, func() float64 {
+			r := 0.0
+			for _, v := range residualWith(ar, A, x, b) {
+				r = math.Max(r, math.Abs(ar.float(v)))
+			}
+			return r
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		if converged {
+			break
+		}
+		copy(xPrev, x)
+	}
+
+	res.Errors = make([]float64, n)
+	for i, e := range errors {
+		res.Errors[i] = ar.float(e)
+	}
+	return x, firstStep, nil
+}
+
+// residualWith returns b - Ax over an arbitrary number type.
+func residualWith[T any](ar arith[T], A [][]float64, x []T, b []float64) []T {
+	r := make([]T, len(b))
+	for i := range A {
+		sum := ar.fromFloat(b[i])
+		for j, v := range A[i] {
+			sum = ar.sub(sum, ar.mul(ar.fromFloat(v), x[j]))
+		}
+		r[i] = sum
+	}
+	return r
+}
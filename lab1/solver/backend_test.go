@@ -0,0 +1,117 @@
+package solver
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestRational(t *testing.T) {
+	tests := []struct {
+		name string
+		A    [][]float64
+		b    []float64
+		want []string
+		det  float64
+	}{
+		{"2x2", [][]float64{{4, 1}, {1, 3}}, []float64{2, 1}, []string{"5/11", "2/11"}, 11},
+		{"needs pivoting", [][]float64{{0, 1, 1}, {1, 0, 1}, {1, 1, 0}}, []float64{1, 1, 1}, []string{"1/2", "1/2", "1/2"}, 2},
+		{"integer", [][]float64{{2, 0}, {0, 0.5}}, []float64{3, 0.25}, []string{"3/2", "1/2"}, 1},
+		{"decimal input", [][]float64{{0.1, 0.2}, {0.3, 0.4}}, []float64{0.1, 0.1}, []string{"-1", "1"}, -0.02},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Solve(tt.A, tt.b, 0, Options{Method: Gauss, Backend: Rational})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res.ExactSolution, tt.want) {
+				t.Errorf("x = %v, want %v", res.ExactSolution, tt.want)
+			}
+			if res.Determinant != tt.det {
+				t.Errorf("det = %g, want %g", res.Determinant, tt.det)
+			}
+			for i, r := range res.Residuals {
+				if r != 0 {
+					t.Errorf("residual %g in row %d, want exactly 0", r, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestBigFloatPrecision(t *testing.T) {
+	A := [][]float64{{4, 1}, {1, 3}}
+	b := []float64{2, 1}
+	want := new(big.Float).SetPrec(512).SetRat(big.NewRat(5, 11))
+
+	// 0.1·x = 0.3 must give 3 to the full mantissa, not the ratio of the
+	// float64 approximations of 0.3 and 0.1.
+	res, err := Solve([][]float64{{0.1}}, []float64{0.3}, 0, Options{Method: Gauss, Backend: BigFloat})
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, _, err := big.ParseFloat(res.ExactSolution[0], 10, 512, big.ToNearestEven)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, _ := new(big.Float).Sub(x, big.NewFloat(3)).Float64(); math.Abs(diff) > 1e-70 {
+		t.Errorf("0.1·x = 0.3: x - 3 = %g", diff)
+	}
+
+	tests := []struct {
+		bits      uint
+		precision float64
+		maxError  float64 // largest acceptable |x₁ - 5/11|
+		minError  float64 // the error a narrower mantissa cannot avoid
+	}{
+		{256, 1e-42, 1e-40, 0},
+		{512, 1e-42, 1e-40, 0},
+		{64, 1e-18, 1e-17, 1e-30},
+	}
+	for _, tt := range tests {
+		for _, method := range []Method{Gauss, Jacobi, GaussSeidel} {
+			res, err := Solve(A, b, tt.precision, Options{Method: method, Backend: BigFloat, Bits: tt.bits})
+			if err != nil {
+				t.Errorf("%d bits, %s: %v", tt.bits, method, err)
+				continue
+			}
+			x, _, err := big.ParseFloat(res.ExactSolution[0], 10, 512, big.ToNearestEven)
+			if err != nil {
+				t.Fatalf("%d bits, %s: %v", tt.bits, method, err)
+			}
+			diff, _ := new(big.Float).Sub(x, want).Float64()
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > tt.maxError || diff < tt.minError {
+				t.Errorf("%d bits, %s: |x₁ - 5/11| = %g, want within [%g, %g]", tt.bits, method, diff, tt.minError, tt.maxError)
+			}
+		}
+	}
+}
+
+func TestBackendMethods(t *testing.T) {
+	A := [][]float64{{4, 1}, {1, 3}}
+	b := []float64{2, 1}
+	tests := []struct {
+		backend Backend
+		method  Method
+		wantErr bool
+	}{
+		{Rational, Gauss, false},
+		{Rational, Jacobi, true},
+		{Rational, GaussSeidel, true},
+		{Rational, SOR, true},
+		{Rational, LUDecomposition, true},
+		{BigFloat, SOR, false},
+		{BigFloat, ConjugateGradient, true},
+	}
+	for _, tt := range tests {
+		_, err := Solve(A, b, 1e-9, Options{Method: tt.method, Backend: tt.backend})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s with %s: error = %v, want error %v", tt.method, tt.backend, err, tt.wantErr)
+		}
+	}
+}
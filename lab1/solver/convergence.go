@@ -0,0 +1,541 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrConvergenceNotGuaranteed is returned when no ordering of the equations
+// gives an iteration operator with spectral radius below one.
+var ErrConvergenceNotGuaranteed = errors.New("convergence cannot be guaranteed")
+
+// Convergence describes the iteration matrix C = I - D⁻¹A of a system after
+// its rows have been reordered.
+type Convergence struct {
+	// Permutation maps rows of the reordered system to rows of A: row i of
+	// the reordered system is row Permutation[i] of the original.
+	Permutation []int
+	// Dominant reports strict diagonal dominance of the reordered matrix.
+	Dominant      bool
+	NormInf       float64
+	NormOne       float64
+	NormFrobenius float64
+	// JacobiRadius is ρ(C), estimated by power iteration.
+	JacobiRadius float64
+	// SpectralRadius is the estimated spectral radius of the iteration
+	// operator of the selected method (equal to JacobiRadius for Jacobi).
+	SpectralRadius float64
+	// SweepNorm bounds ‖T‖∞ of the iteration operator T of the selected
+	// method: NormInf for Jacobi, the Sassenfeld bound for Gauss-Seidel and
+	// SOR. Unlike the spectral radius it is a contraction constant, so the
+	// error bounds of Result are guaranteed only when it is below one.
+	SweepNorm float64
+}
+
+// Guaranteed reports whether the selected method is known to converge:
+// either ρ < 1, or, for Jacobi, one of the norms of C is below one.
+func (c *Convergence) Guaranteed(method Method) bool {
+	if method == Jacobi && (c.NormInf < 1 || c.NormOne < 1 || c.NormFrobenius < 1) {
+		return true
+	}
+	return c.SpectralRadius < 1
+}
+
+// AnalyzeConvergence searches for a row ordering of A under which the
+// method selected in opts converges and returns the analysis of the best
+// ordering found, together with the SOR relaxation factor it implies.
+//
+// For Jacobi, swapping columns as well as rows only renumbers the unknowns:
+// a symmetric permutation PAPᵀ turns C into PCPᵀ, which has the same
+// spectrum and norms, so all that matters is which entry of each row ends
+// up on the diagonal. The original ordering is tried first and kept if the
+// method converges under it. Otherwise the diagonal is chosen by a
+// bipartite matching between rows and columns: the matching that minimises
+// the largest off-diagonal to diagonal ratio is found by bisection on that
+// ratio. If that does not converge either, further perfect matchings of
+// systems with at most altSearchRows equations are enumerated by a bounded
+// backtracking search (see altMatchings) until one does. Gauss-Seidel and SOR also depend on the order in which the unknowns
+// are updated, which a symmetric permutation does change, so for them the
+// unknowns are kept in their original order and ρ is measured for exactly
+// that sweep order; only the row orders are searched.
+//
+// The analysis stops with an error once the context passed to SolveContext
+// is done.
+func AnalyzeConvergence(A [][]float64, opts Options) (*Convergence, float64, error) {
+	n := len(A)
+	for i := range A {
+		if len(A[i]) != n {
+			return nil, 0, fmt.Errorf("matrix must be square")
+		}
+	}
+	return analyzeConvergence(denseRows(A), func(perm []int) rows {
+		return denseRows(permuteRows(A, perm))
+	}, opts)
+}
+
+// analyzeConvergence is AnalyzeConvergence for any row storage; permute
+// returns the rows of A taken in a given order.
+func analyzeConvergence(A rows, permute func(perm []int) rows, opts Options) (*Convergence, float64, error) {
+	n := A.size()
+	var best *Convergence
+	bestOmega := 1.0
+	tried := make(map[string]bool)
+	try := func(perm []int) (bool, error) {
+		key := fmt.Sprint(perm)
+		if tried[key] {
+			return false, nil
+		}
+		tried[key] = true
+		c, omega, err := analyzeRows(permute(perm), perm, opts)
+		if err != nil {
+			return false, err
+		}
+		if best == nil || better(c, best, opts.Method) {
+			best, bestOmega = c, omega
+		}
+		return best.Guaranteed(opts.Method), nil
+	}
+
+	identity := make([]int, n)
+	hasDiagonal := true
+	for i := range identity {
+		identity[i] = i
+		if A.diagonal(i) == 0 {
+			hasDiagonal = false
+		}
+	}
+	if hasDiagonal {
+		if ok, err := try(identity); err != nil || ok {
+			return best, bestOmega, err
+		}
+	}
+
+	entries, levels := diagonalEntries(A)
+	perm, err := bottleneckMatching(opts.ctx, entries, levels)
+	if err != nil {
+		return nil, 0, err
+	}
+	if perm != nil {
+		if _, err := try(perm); err != nil {
+			return nil, 0, err
+		}
+	}
+	if best == nil {
+		return nil, 0, fmt.Errorf("%w: no ordering puts non-zero entries on the diagonal", ErrConvergenceNotGuaranteed)
+	}
+	if !best.Guaranteed(opts.Method) && n <= altSearchRows {
+		if err := altMatchings(opts.ctx, entries, try); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if !best.Guaranteed(opts.Method) {
+		return best, bestOmega, fmt.Errorf("%w: ‖C‖∞ = %.4f, ρ = %.4f",
+			ErrConvergenceNotGuaranteed, best.NormInf, best.SpectralRadius)
+	}
+	return best, bestOmega, nil
+}
+
+// better reports whether ordering a should be preferred over b.
+func better(a, b *Convergence, method Method) bool {
+	ga, gb := a.Guaranteed(method), b.Guaranteed(method)
+	if ga != gb {
+		return ga
+	}
+	return a.SpectralRadius < b.SpectralRadius
+}
+
+// analyzeRows measures the iteration matrix of a system whose rows are
+// already in their final order.
+func analyzeRows(pa rows, perm []int, opts Options) (*Convergence, float64, error) {
+	n := pa.size()
+	c := &Convergence{Permutation: perm, Dominant: true}
+	colSums := make([]float64, n)
+	frob := 0.0
+	for i := 0; i < n; i++ {
+		rowSum := 0.0
+		diag := pa.diagonal(i)
+		pa.eachOffDiagonal(i, func(j int, a float64) {
+			v := math.Abs(a / diag)
+			rowSum += v
+			colSums[j] += v
+			frob += v * v
+		})
+		c.NormInf = math.Max(c.NormInf, rowSum)
+		if rowSum >= 1 {
+			c.Dominant = false
+		}
+	}
+	for _, s := range colSums {
+		c.NormOne = math.Max(c.NormOne, s)
+	}
+	c.NormFrobenius = math.Sqrt(frob)
+	var err error
+	if c.JacobiRadius, err = spectralRadius(opts.ctx, n, sweepOperator(pa, Jacobi, 1)); err != nil {
+		return nil, 0, err
+	}
+
+	omega := 1.0
+	switch opts.Method {
+	case Jacobi:
+		c.SpectralRadius = c.JacobiRadius
+		c.SweepNorm = c.NormInf
+	case GaussSeidel:
+		c.SpectralRadius, err = spectralRadius(opts.ctx, n, sweepOperator(pa, GaussSeidel, 1))
+		c.SweepNorm = sassenfeld(pa, 1)
+	case SOR:
+		omega = opts.Omega
+		if omega == 0 {
+			omega = optimalOmega(c.JacobiRadius)
+		}
+		c.SpectralRadius, err = spectralRadius(opts.ctx, n, sweepOperator(pa, SOR, omega))
+		c.SweepNorm = sassenfeld(pa, omega)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return c, omega, nil
+}
+
+// sassenfeld bounds ‖T‖∞ of the SOR sweep with relaxation factor omega
+// (Gauss-Seidel for omega = 1) in the sweep order of the rows: with
+// cᵢⱼ = aᵢⱼ/aᵢᵢ, βᵢ = |1-ω| + ω(Σⱼ<ᵢ |cᵢⱼ|βⱼ + Σⱼ>ᵢ |cᵢⱼ|), and one sweep
+// shrinks the error by at least max βᵢ in the maximum norm.
+func sassenfeld(pa rows, omega float64) float64 {
+	n := pa.size()
+	beta := make([]float64, n)
+	norm := 0.0
+	for i := 0; i < n; i++ {
+		diag := pa.diagonal(i)
+		sum := 0.0
+		pa.eachOffDiagonal(i, func(j int, a float64) {
+			v := math.Abs(a / diag)
+			if j < i {
+				v *= beta[j]
+			}
+			sum += v
+		})
+		beta[i] = math.Abs(1-omega) + omega*sum
+		norm = math.Max(norm, beta[i])
+	}
+	return norm
+}
+
+// contraction returns the factor q used by the error bounds: SweepNorm
+// when it is below one, and otherwise the spectral radius, in which case
+// the bounds are only asymptotic estimates and estimated is true.
+func (c *Convergence) contraction() (q float64, estimated bool) {
+	if c.SweepNorm < 1 {
+		return c.SweepNorm, false
+	}
+	return c.SpectralRadius, true
+}
+
+// entry is a non-zero aᵣc together with the ratio Σⱼ≠c |aᵣⱼ| / |aᵣc| it
+// would give row r if placed on the diagonal.
+type entry struct {
+	col   int
+	ratio float64
+}
+
+// diagonalEntries lists the entries of every row of A that could be
+// placed on the diagonal, and all their ratios as the levels
+// bottleneckMatching bisects over. Only non-zero entries are considered,
+// so sparse rows cost time proportional to their length.
+func diagonalEntries(A rows) ([][]entry, []float64) {
+	n := A.size()
+	entries := make([][]entry, n)
+	var levels []float64
+	for r := 0; r < n; r++ {
+		total := 0.0
+		var cols []int
+		var vals []float64
+		if d := A.diagonal(r); d != 0 {
+			cols, vals = append(cols, r), append(vals, math.Abs(d))
+		}
+		A.eachOffDiagonal(r, func(j int, a float64) {
+			cols, vals = append(cols, j), append(vals, math.Abs(a))
+		})
+		for _, v := range vals {
+			total += v
+		}
+		for k, c := range cols {
+			ratio := (total - vals[k]) / vals[k]
+			entries[r] = append(entries[r], entry{c, ratio})
+			levels = append(levels, ratio)
+		}
+	}
+	return entries, levels
+}
+
+// bottleneckMatching assigns every row a distinct diagonal column so that
+// the largest ratio Σⱼ≠c |aᵣⱼ| / |aᵣc| over the assignment is minimal,
+// bisecting over the distinct ratios in levels. It returns the rows in
+// their new order, or nil if A is structurally singular. ctx is checked
+// before every matching.
+func bottleneckMatching(ctx context.Context, entries [][]entry, levels []float64) ([]int, error) {
+	sort.Float64s(levels)
+	distinct := levels[:0]
+	for i, v := range levels {
+		if i == 0 || v != levels[i-1] {
+			distinct = append(distinct, v)
+		}
+	}
+
+	m := newMatcher(entries)
+	var best []int
+	lo, hi := 0, len(distinct)-1
+	for lo <= hi {
+		if err := cancelled(ctx); err != nil {
+			return nil, err
+		}
+		mid := (lo + hi) / 2
+		if m.match(distinct[mid]) {
+			best = append(best[:0], m.rowOf...)
+			hi = mid - 1
+		} else {
+			lo = mid + 1
+		}
+	}
+	return best, nil
+}
+
+// matcher finds perfect matchings of rows to columns using only entries
+// whose ratio does not exceed a limit (Hopcroft–Karp, O(nnz·√n) per
+// call). The matching left by one call, minus the entries above the next
+// limit, is the starting point of the next, so the bisection in
+// bottleneckMatching only has to re-match the rows that lost their column.
+type matcher struct {
+	entries [][]entry
+	colOf   []int // row -> matched column
+	rowOf   []int // column -> matched row
+	dist    []int // BFS layer of every row in the current phase
+	queue   []int
+}
+
+func newMatcher(entries [][]entry) *matcher {
+	n := len(entries)
+	m := &matcher{entries: entries, colOf: make([]int, n), rowOf: make([]int, n), dist: make([]int, n)}
+	for i := 0; i < n; i++ {
+		m.colOf[i], m.rowOf[i] = -1, -1
+	}
+	return m
+}
+
+// match reports whether every row can be matched within limit; on success
+// rowOf holds the matching.
+func (m *matcher) match(limit float64) bool {
+	for r, c := range m.colOf {
+		if c == -1 {
+			continue
+		}
+		for _, e := range m.entries[r] {
+			if e.col == c && e.ratio > limit {
+				m.colOf[r], m.rowOf[c] = -1, -1
+				break
+			}
+		}
+	}
+
+	const unreached = -1
+	var augment func(r int) bool
+	augment = func(r int) bool {
+		for _, e := range m.entries[r] {
+			if e.ratio > limit {
+				continue
+			}
+			next := m.rowOf[e.col]
+			if next == -1 || (m.dist[next] == m.dist[r]+1 && augment(next)) {
+				m.colOf[r], m.rowOf[e.col] = e.col, r
+				return true
+			}
+		}
+		m.dist[r] = unreached
+		return false
+	}
+
+	for {
+		// Layer the rows by breadth-first search from the free ones; the
+		// phase ends once no free column is reachable.
+		m.queue = m.queue[:0]
+		for r, c := range m.colOf {
+			m.dist[r] = unreached
+			if c == -1 {
+				m.dist[r] = 0
+				m.queue = append(m.queue, r)
+			}
+		}
+		if len(m.queue) == 0 {
+			return true
+		}
+		reachable := false
+		for k := 0; k < len(m.queue); k++ {
+			r := m.queue[k]
+			for _, e := range m.entries[r] {
+				if e.ratio > limit {
+					continue
+				}
+				next := m.rowOf[e.col]
+				if next == -1 {
+					reachable = true
+				} else if m.dist[next] == unreached {
+					m.dist[next] = m.dist[r] + 1
+					m.queue = append(m.queue, next)
+				}
+			}
+		}
+		if !reachable {
+			return false
+		}
+		for r, c := range m.colOf {
+			if c == -1 {
+				augment(r)
+			}
+		}
+	}
+}
+
+// Limits of the search for further row orders: altMatchings reports at
+// most maxAltMatchings matchings and gives up after altSearchSteps
+// assignments, so a system with many zero-free diagonals cannot stall the
+// analysis. Each matching found costs a full analysis, so systems with more
+// than altSearchRows equations skip the search.
+const (
+	maxAltMatchings = 32
+	altSearchSteps  = 1 << 14
+	altSearchRows   = 2000
+)
+
+// altMatchings enumerates perfect matchings of rows to columns by
+// backtracking and calls visit with each one as a row order, stopping once
+// visit reports success or fails. Rows with the fewest candidate entries
+// are assigned first, and each row tries its entries in increasing order
+// of ratio, so the matchings found early have small ratios. The search is
+// bounded by maxAltMatchings and altSearchSteps and checks ctx as it goes.
+func altMatchings(ctx context.Context, entries [][]entry, visit func(perm []int) (bool, error)) error {
+	n := len(entries)
+	order := make([]int, n)
+	sorted := make([][]entry, n)
+	for r := range entries {
+		order[r] = r
+		sorted[r] = append([]entry(nil), entries[r]...)
+		sort.SliceStable(sorted[r], func(i, j int) bool { return sorted[r][i].ratio < sorted[r][j].ratio })
+	}
+	sort.SliceStable(order, func(i, j int) bool { return len(entries[order[i]]) < len(entries[order[j]]) })
+
+	rowOf := make([]int, n) // column -> assigned row
+	for c := range rowOf {
+		rowOf[c] = -1
+	}
+	found, steps := 0, 0
+	var done bool
+	var err error
+	var assign func(k int)
+	assign = func(k int) {
+		if k == n {
+			found++
+			done, err = visit(append([]int(nil), rowOf...))
+			if found >= maxAltMatchings {
+				done = true
+			}
+			return
+		}
+		r := order[k]
+		for _, e := range sorted[r] {
+			if rowOf[e.col] != -1 {
+				continue
+			}
+			if steps++; steps > altSearchSteps {
+				done = true
+				return
+			}
+			if err = cancelled(ctx); err != nil {
+				return
+			}
+			rowOf[e.col] = r
+			assign(k + 1)
+			rowOf[e.col] = -1
+			if done || err != nil {
+				return
+			}
+		}
+	}
+	assign(0)
+	return err
+}
+
+// sweepOperator returns the homogeneous (b = 0) iteration step of the given
+// method, i.e. the linear map whose spectral radius governs convergence.
+func sweepOperator(A rows, method Method, omega float64) func(dst, src []float64) {
+	n := A.size()
+	return func(dst, src []float64) {
+		from := src
+		if method != Jacobi {
+			copy(dst, src)
+			from = dst
+		}
+		for i := 0; i < n; i++ {
+			sum, diag := A.offDiagonalDot(i, from)
+			next := -sum / diag
+			if method == SOR {
+				next = (1-omega)*src[i] + omega*next
+			}
+			dst[i] = next
+		}
+	}
+}
+
+// spectralRadius estimates the spectral radius of a linear operator by
+// power iteration. The growth rate is averaged over the second half of the
+// run so that complex or negative dominant eigenvalues still give a stable
+// estimate. It fails once ctx is done.
+func spectralRadius(ctx context.Context, n int, apply func(dst, src []float64)) (float64, error) {
+	v := make([]float64, n)
+	w := make([]float64, n)
+	for i := range v {
+		v[i] = 1 - float64(i)/float64(2*n)
+	}
+
+	const steps = 200
+	logGrowth := 0.0
+	for k := 0; k < steps; k++ {
+		if err := cancelled(ctx); err != nil {
+			return 0, err
+		}
+		apply(w, v)
+		norm := 0.0
+		for _, x := range w {
+			norm = math.Max(norm, math.Abs(x))
+		}
+		if norm == 0 {
+			return 0, nil
+		}
+		if math.IsInf(norm, 0) || math.IsNaN(norm) {
+			// A single sweep can overflow when the rows of a long
+			// system each amplify the error.
+			return math.Inf(1), nil
+		}
+		if k >= steps/2 {
+			logGrowth += math.Log(norm)
+		}
+		for i := range w {
+			v[i] = w[i] / norm
+		}
+	}
+
+	return math.Exp(logGrowth / (steps - steps/2)), nil
+}
+
+// permuteRows returns a copy of A with its rows taken in the order perm.
+func permuteRows(A [][]float64, perm []int) [][]float64 {
+	pa := make([][]float64, len(perm))
+	for i, r := range perm {
+		pa[i] = make([]float64, len(A[r]))
+		copy(pa[i], A[r])
+	}
+	return pa
+}
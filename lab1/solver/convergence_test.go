@@ -0,0 +1,149 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeConvergence(t *testing.T) {
+	tests := []struct {
+		name     string
+		A        [][]float64
+		method   Method
+		wantPerm []int
+		wantErr  bool
+	}{
+		{"dominant", [][]float64{{4, 1, 0}, {1, 4, 1}, {0, 1, 4}}, Jacobi, []int{0, 1, 2}, false},
+		{"rows swapped", [][]float64{{1, 5, 1}, {5, 1, 1}, {1, 1, 5}}, Jacobi, []int{1, 0, 2}, false},
+		{"rows rotated", [][]float64{{1, 1, 6}, {6, 1, 1}, {1, 6, 1}}, GaussSeidel, []int{1, 2, 0}, false},
+		{"only another matching", [][]float64{{1, 2, -2}, {-2, 0, 2}, {3, 4, 0}}, GaussSeidel, []int{1, 2, 0}, false},
+		{"structurally singular", [][]float64{{1, 2}, {0, 0}}, Jacobi, nil, true},
+		{"no convergent ordering", [][]float64{{1, 1}, {1, -1}}, GaussSeidel, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv, _, err := AnalyzeConvergence(tt.A, Options{Method: tt.method})
+			if tt.wantErr {
+				if !errors.Is(err, ErrConvergenceNotGuaranteed) {
+					t.Fatalf("error = %v, want ErrConvergenceNotGuaranteed", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range tt.wantPerm {
+				if conv.Permutation[i] != tt.wantPerm[i] {
+					t.Fatalf("permutation %v, want %v", conv.Permutation, tt.wantPerm)
+				}
+			}
+		})
+	}
+}
+
+// The spectral radius reported for Gauss-Seidel and SOR must be that of the
+// sweep actually run on the reordered rows: it is compared with the rate at
+// which ‖xₖ - xₖ₋₁‖ decreases.
+func TestSpectralRadiusMatchesSweep(t *testing.T) {
+	A := [][]float64{
+		{1, 3, 1, 0.5},
+		{4, 1, 1, 1},
+		{0.5, 1, 1, 3},
+		{1, 1, 4, 1},
+	}
+	b := []float64{1, 2, 3, 4}
+	for _, method := range []Method{Jacobi, GaussSeidel, SOR} {
+		res, err := Solve(A, b, 1e-13, Options{Method: method, Omega: 1.2, Trace: true})
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		k := len(res.Trace) / 2
+		if k < 5 {
+			t.Fatalf("%s: only %d iterations", method, len(res.Trace))
+		}
+		rate := math.Pow(res.Trace[2*k-1].Difference/res.Trace[k-1].Difference, 1/float64(k))
+		if rho := res.Convergence.SpectralRadius; math.Abs(rate-rho) > 0.05 {
+			t.Errorf("%s: ρ = %.4f, observed rate %.4f", method, rho, rate)
+		}
+	}
+}
+
+// bottleneckMatching must find the smallest largest ratio over all perfect
+// matchings; it is checked against every permutation of small random
+// sparse matrices.
+func TestBottleneckMatching(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		n := 1 + rng.Intn(6)
+		A := make([][]float64, n)
+		for i := range A {
+			A[i] = make([]float64, n)
+			for j := range A[i] {
+				if rng.Float64() < 0.5 {
+					A[i][j] = float64(rng.Intn(9) - 4)
+				}
+			}
+		}
+		entries, levels := diagonalEntries(denseRows(A))
+		ratio := func(perm []int) float64 {
+			worst := 0.0
+			for c, r := range perm {
+				found := math.Inf(1)
+				for _, e := range entries[r] {
+					if e.col == c {
+						found = e.ratio
+					}
+				}
+				worst = math.Max(worst, found)
+			}
+			return worst
+		}
+
+		want := math.Inf(1)
+		perm := make([]int, n)
+		var permute func(k int, used int)
+		permute = func(k int, used int) {
+			if k == n {
+				want = math.Min(want, ratio(perm))
+				return
+			}
+			for r := 0; r < n; r++ {
+				if used&(1<<r) == 0 {
+					perm[k] = r
+					permute(k+1, used|1<<r)
+				}
+			}
+		}
+		permute(0, 0)
+
+		got, err := bottleneckMatching(context.Background(), entries, levels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case got == nil && !math.IsInf(want, 1):
+			t.Errorf("%v: no matching, want largest ratio %g", A, want)
+		case got != nil && ratio(got) != want:
+			t.Errorf("%v: matching %v has largest ratio %g, want %g", A, got, ratio(got), want)
+		}
+	}
+}
+
+// A long tridiagonal system that is not diagonally dominant has to be
+// rejected without a search whose cost grows with the square of its size.
+func TestAnalyzeConvergenceLarge(t *testing.T) {
+	const n = 20000
+	A, b := tridiagonal(diagonals(n, 2, 1, 2))
+	start := time.Now()
+	_, err := SolveSparse(A, b, 1e-6, Options{Method: GaussSeidel})
+	if !errors.Is(err, ErrConvergenceNotGuaranteed) {
+		t.Fatalf("error = %v, want ErrConvergenceNotGuaranteed", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("analysis took %v", elapsed)
+	}
+}
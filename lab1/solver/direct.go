@@ -0,0 +1,333 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrSingular is returned by the direct solvers and factorizations when a
+// pivot is too small for the matrix to be treated as non-singular.
+var ErrSingular = errors.New("matrix is singular")
+
+// singularEps is the pivot size, relative to ‖A‖∞, below which a matrix is
+// treated as singular by the direct solvers.
+const singularEps = 1e-12
+
+// GaussianElimination solves Ax = b by Gaussian elimination with partial
+// pivoting and returns the solution together with det(A).
+func GaussianElimination(A [][]float64, b []float64) ([]float64, float64, error) {
+	return gaussianElimination(context.Background(), A, b)
+}
+
+// gaussianElimination is GaussianElimination checking ctx before every
+// elimination step.
+func gaussianElimination(ctx context.Context, A [][]float64, b []float64) ([]float64, float64, error) {
+	n := len(A)
+	if n == 0 || len(b) != n {
+		return nil, 0, fmt.Errorf("invalid matrix or vector dimensions")
+	}
+	if err := checkSquare(A); err != nil {
+		return nil, 0, err
+	}
+
+	tol := singularEps * calculateNorm(A)
+	a := make([][]float64, n)
+	for i := range A {
+		a[i] = make([]float64, n+1)
+		copy(a[i], A[i])
+		a[i][n] = b[i]
+	}
+
+	det := 1.0
+	for k := 0; k < n; k++ {
+		if err := cancelled(ctx); err != nil {
+			return nil, 0, err
+		}
+		// Choose the largest pivot in column k
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i][k]) > math.Abs(a[p][k]) {
+				p = i
+			}
+		}
+		if math.Abs(a[p][k]) <= tol {
+			return nil, 0, ErrSingular
+		}
+		if p != k {
+			a[p], a[k] = a[k], a[p]
+			det = -det
+		}
+		det *= a[k][k]
+
+		for i := k + 1; i < n; i++ {
+			factor := a[i][k] / a[k][k]
+			for j := k; j <= n; j++ {
+				a[i][j] -= factor * a[k][j]
+			}
+		}
+	}
+
+	// Back substitution
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := a[i][n]
+		for j := i + 1; j < n; j++ {
+			sum -= a[i][j] * x[j]
+		}
+		x[i] = sum / a[i][i]
+	}
+
+	return x, det, nil
+}
+
+// LU is a PA = LU factorization with partial pivoting. The factors are
+// stored in a single matrix (unit diagonal of L implied), so one
+// factorization can be reused for any number of right-hand sides.
+type LU struct {
+	lu   [][]float64
+	perm []int
+	sign float64
+}
+
+// Factorize computes the LU factorization of the square matrix A.
+func Factorize(A [][]float64) (*LU, error) {
+	return factorize(context.Background(), A)
+}
+
+// factorize is Factorize checking ctx before every elimination step.
+func factorize(ctx context.Context, A [][]float64) (*LU, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+
+	n := len(A)
+	tol := singularEps * calculateNorm(A)
+	lu := make([][]float64, n)
+	perm := make([]int, n)
+	for i := range A {
+		lu[i] = make([]float64, n)
+		copy(lu[i], A[i])
+		perm[i] = i
+	}
+
+	sign := 1.0
+	for k := 0; k < n; k++ {
+		if err := cancelled(ctx); err != nil {
+			return nil, err
+		}
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu[i][k]) > math.Abs(lu[p][k]) {
+				p = i
+			}
+		}
+		if math.Abs(lu[p][k]) <= tol {
+			return nil, ErrSingular
+		}
+		if p != k {
+			lu[p], lu[k] = lu[k], lu[p]
+			perm[p], perm[k] = perm[k], perm[p]
+			sign = -sign
+		}
+
+		for i := k + 1; i < n; i++ {
+			lu[i][k] /= lu[k][k]
+			for j := k + 1; j < n; j++ {
+				lu[i][j] -= lu[i][k] * lu[k][j]
+			}
+		}
+	}
+
+	return &LU{lu: lu, perm: perm, sign: sign}, nil
+}
+
+// Solve returns x such that Ax = b for the factorized A.
+func (f *LU) Solve(b []float64) ([]float64, error) {
+	n := len(f.lu)
+	if len(b) != n {
+		return nil, fmt.Errorf("invalid vector dimension")
+	}
+
+	// Forward substitution with L applied to Pb
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := b[f.perm[i]]
+		for j := 0; j < i; j++ {
+			sum -= f.lu[i][j] * y[j]
+		}
+		y[i] = sum
+	}
+
+	// Back substitution with U
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for j := i + 1; j < n; j++ {
+			sum -= f.lu[i][j] * x[j]
+		}
+		x[i] = sum / f.lu[i][i]
+	}
+
+	return x, nil
+}
+
+// Determinant returns det(A) from the diagonal of U and the pivot sign.
+func (f *LU) Determinant() float64 {
+	det := f.sign
+	for i := range f.lu {
+		det *= f.lu[i][i]
+	}
+	return det
+}
+
+// Cholesky is an A = LLᵀ factorization of a symmetric positive-definite
+// matrix.
+type Cholesky struct {
+	l [][]float64
+}
+
+// FactorizeCholesky computes the Cholesky factor of A. It fails if A is not
+// symmetric or not positive definite.
+func FactorizeCholesky(A [][]float64) (*Cholesky, error) {
+	return factorizeCholesky(context.Background(), A)
+}
+
+// factorizeCholesky is FactorizeCholesky checking ctx before every row.
+func factorizeCholesky(ctx context.Context, A [][]float64) (*Cholesky, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+
+	n := len(A)
+	tol := singularEps * calculateNorm(A)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if math.Abs(A[i][j]-A[j][i]) > tol {
+				return nil, fmt.Errorf("matrix is not symmetric")
+			}
+		}
+	}
+
+	l := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		if err := cancelled(ctx); err != nil {
+			return nil, err
+		}
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := A[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= tol {
+					return nil, fmt.Errorf("matrix is not positive definite")
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+
+	return &Cholesky{l: l}, nil
+}
+
+// Solve returns x such that Ax = b for the factorized A.
+func (c *Cholesky) Solve(b []float64) ([]float64, error) {
+	n := len(c.l)
+	if len(b) != n {
+		return nil, fmt.Errorf("invalid vector dimension")
+	}
+
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= c.l[i][k] * y[k]
+		}
+		y[i] = sum / c.l[i][i]
+	}
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < n; k++ {
+			sum -= c.l[k][i] * x[k]
+		}
+		x[i] = sum / c.l[i][i]
+	}
+
+	return x, nil
+}
+
+// Determinant returns det(A) = (∏ lᵢᵢ)².
+func (c *Cholesky) Determinant() float64 {
+	det := 1.0
+	for i := range c.l {
+		det *= c.l[i][i]
+	}
+	return det * det
+}
+
+// solveDirect dispatches to the given direct method and fills in the
+// determinant and residual of the result. The elimination stops with an
+// error once ctx is done.
+func solveDirect(ctx context.Context, A [][]float64, b []float64, method Method) (*Result, error) {
+	n := len(A)
+	if n == 0 || len(b) != n {
+		return nil, fmt.Errorf("invalid matrix or vector dimensions")
+	}
+
+	var x []float64
+	var det float64
+	switch method {
+	case Gauss:
+		var err error
+		x, det, err = gaussianElimination(ctx, A, b)
+		if err != nil {
+			return nil, err
+		}
+	case LUDecomposition:
+		f, err := factorize(ctx, A)
+		if err != nil {
+			return nil, err
+		}
+		if x, err = f.Solve(b); err != nil {
+			return nil, err
+		}
+		det = f.Determinant()
+	case CholeskyDecomposition:
+		c, err := factorizeCholesky(ctx, A)
+		if err != nil {
+			return nil, err
+		}
+		if x, err = c.Solve(b); err != nil {
+			return nil, err
+		}
+		det = c.Determinant()
+	default:
+		return nil, fmt.Errorf("%s is not a direct method", method)
+	}
+
+	return &Result{
+		Solution:    x,
+		Errors:      make([]float64, n),
+		MatrixNorm:  calculateNorm(A),
+		Method:      method,
+		Determinant: det,
+		Residuals:   residual(denseRows(A), x, b),
+	}, nil
+}
+
+// residual returns r = b - Ax.
+func residual(a rows, x, b []float64) []float64 {
+	r := make([]float64, len(b))
+	for i := range r {
+		sum, diag := a.offDiagonalDot(i, x)
+		r[i] = b[i] - sum - diag*x[i]
+	}
+	return r
+}
@@ -0,0 +1,92 @@
+package solver
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDirect(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       [][]float64
+		det     float64
+		methods []Method
+	}{
+		{"spd", [][]float64{{4, 1, 0}, {1, 4, 1}, {0, 1, 4}}, 56, []Method{Gauss, LUDecomposition, CholeskyDecomposition}},
+		{"needs pivoting", [][]float64{{0, 2, 1}, {1, 1, 1}, {2, 1, 0}}, 3, []Method{Gauss, LUDecomposition}},
+		{"nonsymmetric", [][]float64{{2, -1, 3}, {4, 1, -2}, {-1, 5, 1}}, 87, []Method{Gauss, LUDecomposition}},
+		{"1x1", [][]float64{{-3}}, -3, []Method{Gauss, LUDecomposition}},
+	}
+	for _, tt := range tests {
+		n := len(tt.A)
+		want := exactSolution(n)
+		b := make([]float64, n)
+		DenseToCSR(tt.A).MulVec(b, want)
+		for _, method := range tt.methods {
+			res, err := Solve(tt.A, b, 0, Options{Method: method})
+			if err != nil {
+				t.Errorf("%s, %s: %v", tt.name, method, err)
+				continue
+			}
+			if math.Abs(res.Determinant-tt.det) > 1e-9*math.Abs(tt.det) {
+				t.Errorf("%s, %s: det = %g, want %g", tt.name, method, res.Determinant, tt.det)
+			}
+			for i, x := range res.Solution {
+				if math.Abs(x-want[i]) > 1e-12*float64(n) {
+					t.Errorf("%s, %s: x = %v, want %v", tt.name, method, res.Solution, want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestDirectErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		A        [][]float64
+		method   Method
+		singular bool
+	}{
+		{"singular gauss", [][]float64{{1, 2}, {2, 4}}, Gauss, true},
+		{"singular lu", [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, LUDecomposition, true},
+		{"cholesky not symmetric", [][]float64{{4, 1}, {2, 3}}, CholeskyDecomposition, false},
+		{"cholesky indefinite", [][]float64{{1, 2}, {2, 1}}, CholeskyDecomposition, false},
+		{"not square", [][]float64{{1, 2}, {3}}, Gauss, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Solve(tt.A, make([]float64, len(tt.A)), 0, Options{Method: tt.method})
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if errors.Is(err, ErrSingular) != tt.singular {
+				t.Errorf("error = %v, want ErrSingular %v", err, tt.singular)
+			}
+		})
+	}
+}
+
+func TestLUReuse(t *testing.T) {
+	A := [][]float64{{2, -1, 3}, {4, 1, -2}, {-1, 5, 1}}
+	f, err := Factorize(A)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv := f.Inverse()
+	for _, b := range [][]float64{{1, 0, 0}, {0, 1, 0}, {1, 2, 3}} {
+		x, err := f.Solve(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range A {
+			if r := dot(A[i], x) - b[i]; math.Abs(r) > 1e-12 {
+				t.Errorf("b = %v: residual %g in row %d", b, r, i+1)
+			}
+			if y := dot(inv[i], b); math.Abs(y-x[i]) > 1e-12 {
+				t.Errorf("b = %v: A⁻¹b = %g, LU solve %g in row %d", b, y, x[i], i+1)
+			}
+		}
+	}
+}
@@ -0,0 +1,589 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// EigenMethod selects the eigenvalue algorithm used by Eigen.
+type EigenMethod int
+
+const (
+	PowerIteration EigenMethod = iota
+	InverseIteration
+	QRAlgorithm
+)
+
+func (m EigenMethod) String() string {
+	switch m {
+	case PowerIteration:
+		return "Power method"
+	case InverseIteration:
+		return "Inverse iteration"
+	case QRAlgorithm:
+		return "QR algorithm"
+	default:
+		return fmt.Sprintf("EigenMethod(%d)", int(m))
+	}
+}
+
+// EigenStep is one recorded iteration of an eigenvalue method.
+type EigenStep struct {
+	Iteration int
+	// Value is the current eigenvalue estimate.
+	Value float64
+	// Difference is |λₖ - λₖ₋₁| for the vector iterations and the size of
+	// the subdiagonal entry being deflated for QR.
+	Difference float64
+}
+
+// EigenResult holds eigenvalues and, for real ones, unit eigenvectors.
+type EigenResult struct {
+	Method EigenMethod
+	Values []complex128
+	// Vectors[i] belongs to Values[i]; it is nil for complex eigenvalues
+	// and for real ones whose inverse iteration did not converge.
+	Vectors    [][]float64
+	Iterations int
+	History    []EigenStep
+}
+
+// SpectralRadius returns max |λ| over the computed eigenvalues.
+func (r *EigenResult) SpectralRadius() float64 {
+	rho := 0.0
+	for _, v := range r.Values {
+		rho = math.Max(rho, cmplx.Abs(v))
+	}
+	return rho
+}
+
+// maxEigenIter bounds every eigenvalue iteration.
+const maxEigenIter = 10000
+
+// Eigen runs the selected method on A. shift is only used by inverse
+// iteration, which converges to the eigenvalue closest to it.
+func Eigen(A [][]float64, method EigenMethod, shift, precision float64) (*EigenResult, error) {
+	return EigenContext(context.Background(), A, method, shift, precision)
+}
+
+// EigenContext is Eigen with cancellation: every iteration checks ctx and
+// returns its error, wrapped, once it is done.
+func EigenContext(ctx context.Context, A [][]float64, method EigenMethod, shift, precision float64) (*EigenResult, error) {
+	switch method {
+	case PowerIteration:
+		return powerMethod(ctx, A, precision)
+	case InverseIteration:
+		return inversePowerMethod(ctx, A, shift, precision)
+	case QRAlgorithm:
+		return qrEigen(ctx, A, precision)
+	default:
+		return nil, fmt.Errorf("unknown eigenvalue method %s", method)
+	}
+}
+
+// eigenTolerance is the bound on ‖Av - λv‖₂ under which λ and v are
+// accepted as an eigenpair.
+func eigenTolerance(A [][]float64, precision float64) float64 {
+	return math.Max(precision, 100*epsilon) * calculateNorm(A)
+}
+
+// PowerMethod finds the eigenvalue of largest modulus and its eigenvector.
+// The estimate is the Rayleigh quotient vᵀAv of the normalized iterate. It
+// is accepted once it changes by less than precision and the residual
+// ‖Av - λv‖₂ is at most precision·‖A‖∞; a stalled quotient alone is not
+// enough, since for a dominant pair of complex or opposite eigenvalues it
+// can settle on a value that is not an eigenvalue. Such matrices return an
+// error after maxEigenIter iterations.
+func PowerMethod(A [][]float64, precision float64) (*EigenResult, error) {
+	return powerMethod(context.Background(), A, precision)
+}
+
+func powerMethod(ctx context.Context, A [][]float64, precision float64) (*EigenResult, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+	n := len(A)
+	v := startVector(n)
+	w := make([]float64, n)
+	res := &EigenResult{Method: PowerIteration}
+	tol := eigenTolerance(A, precision)
+
+	lambda := 0.0
+	for k := 1; ; k++ {
+		if err := cancelled(ctx); err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			w[i] = dot(A[i], v)
+		}
+		next := dot(v, w)
+		norm := math.Sqrt(dot(w, w))
+		residual := 0.0
+		for i := range w {
+			residual += (w[i] - next*v[i]) * (w[i] - next*v[i])
+		}
+		residual = math.Sqrt(residual)
+		if norm == 0 {
+			// v lies in the null space: λ = 0 is dominant only for A = 0
+			res.Values = []complex128{0}
+			res.Vectors = [][]float64{v}
+			res.Iterations = k
+			return res, nil
+		}
+		for i := range v {
+			v[i] = w[i] / norm
+		}
+
+		diff := math.Abs(next - lambda)
+		lambda = next
+		res.History = append(res.History, EigenStep{Iteration: k, Value: lambda, Difference: diff})
+		if k > 1 && diff < precision && residual <= tol {
+			res.Iterations = k
+			break
+		}
+		if k >= maxEigenIter {
+			return nil, fmt.Errorf("power method did not converge within %d iterations; the dominant eigenvalue may be complex or not unique", maxEigenIter)
+		}
+	}
+
+	res.Values = []complex128{complex(lambda, 0)}
+	res.Vectors = [][]float64{normalizeSign(v)}
+	return res, nil
+}
+
+// InversePowerMethod finds the eigenvalue closest to shift by power
+// iteration on (A - σI)⁻¹, reusing one LU factorization for every step. As
+// in PowerMethod, the estimate is accepted only when the residual
+// ‖Av - λv‖₂ is at most precision·‖A‖∞, so a shift equidistant from several
+// eigenvalues returns an error instead of a spurious value.
+func InversePowerMethod(A [][]float64, shift, precision float64) (*EigenResult, error) {
+	return inversePowerMethod(context.Background(), A, shift, precision)
+}
+
+func inversePowerMethod(ctx context.Context, A [][]float64, shift, precision float64) (*EigenResult, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+	n := len(A)
+	tol := eigenTolerance(A, precision)
+	f, err := factorizeShifted(ctx, A, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	v := startVector(n)
+	res := &EigenResult{Method: InverseIteration}
+	lambda := shift
+	for k := 1; ; k++ {
+		if err := cancelled(ctx); err != nil {
+			return nil, err
+		}
+		w, _ := f.lu.Solve(v)
+		mu := dot(v, w)
+		norm := math.Sqrt(dot(w, w))
+		for i := range v {
+			v[i] = w[i] / norm
+		}
+		if mu == 0 {
+			return nil, fmt.Errorf("inverse iteration broke down: (A - σI)⁻¹v is orthogonal to v")
+		}
+
+		next := f.shift + 1/mu
+		diff := math.Abs(next - lambda)
+		lambda = next
+		res.History = append(res.History, EigenStep{Iteration: k, Value: lambda, Difference: diff})
+		if k > 1 && diff < precision && eigenResidual(A, v, lambda) <= tol {
+			res.Iterations = k
+			break
+		}
+		if k >= maxEigenIter {
+			return nil, fmt.Errorf("inverse iteration did not converge within %d iterations; the eigenvalue closest to the shift may be complex or not unique", maxEigenIter)
+		}
+	}
+
+	res.Values = []complex128{complex(lambda, 0)}
+	res.Vectors = [][]float64{normalizeSign(v)}
+	return res, nil
+}
+
+// eigenResidual returns ‖Av - λv‖₂.
+func eigenResidual(A [][]float64, v []float64, lambda float64) float64 {
+	sum := 0.0
+	for i := range A {
+		d := dot(A[i], v) - lambda*v[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+type shiftedLU struct {
+	lu    *LU
+	shift float64
+}
+
+// factorizeShifted factorizes A - σI, nudging σ off an exact eigenvalue
+// when the shifted matrix is singular.
+func factorizeShifted(ctx context.Context, A [][]float64, shift float64) (*shiftedLU, error) {
+	n := len(A)
+	for attempt := 0; attempt < 5; attempt++ {
+		shifted := make([][]float64, n)
+		for i := range A {
+			shifted[i] = append([]float64(nil), A[i]...)
+			shifted[i][i] -= shift
+		}
+		f, err := factorize(ctx, shifted)
+		if err == nil {
+			return &shiftedLU{lu: f, shift: shift}, nil
+		}
+		if !errors.Is(err, ErrSingular) {
+			return nil, err
+		}
+		shift += 1e-10 * (1 + math.Abs(shift))
+	}
+	return nil, fmt.Errorf("A - σI is singular for every shift tried near %g", shift)
+}
+
+// QREigen finds all eigenvalues of A: A is reduced to upper Hessenberg form
+// by Householder reflections and the Hessenberg matrix is iterated with
+// Francis double-shift QR steps, deflating a subdiagonal entry once it is
+// below precision relative to its diagonal neighbours. Eigenvectors of real
+// eigenvalues are then found by inverse iteration.
+func QREigen(A [][]float64, precision float64) (*EigenResult, error) {
+	return qrEigen(context.Background(), A, precision)
+}
+
+func qrEigen(ctx context.Context, A [][]float64, precision float64) (*EigenResult, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+	n := len(A)
+	h := hessenberg(A)
+	tol := math.Max(precision, epsilon)
+
+	res := &EigenResult{Method: QRAlgorithm}
+	wr, wi, err := hqr(ctx, h, tol, res)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Values = make([]complex128, n)
+	res.Vectors = make([][]float64, n)
+	for i := range wr {
+		res.Values[i] = complex(wr[i], wi[i])
+		if wi[i] == 0 {
+			inv, err := inversePowerMethod(ctx, A, wr[i], 1e-12)
+			if err == nil {
+				res.Vectors[i] = inv.Vectors[0]
+			} else if err := cancelled(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// epsilon is the float64 machine epsilon.
+const epsilon = 0x1p-52
+
+// hessenberg returns an upper Hessenberg matrix similar to A.
+func hessenberg(A [][]float64) [][]float64 {
+	n := len(A)
+	h := make([][]float64, n)
+	for i := range A {
+		h[i] = append([]float64(nil), A[i]...)
+	}
+
+	v := make([]float64, n)
+	for k := 0; k < n-2; k++ {
+		alpha := 0.0
+		for i := k + 1; i < n; i++ {
+			alpha += h[i][k] * h[i][k]
+		}
+		alpha = math.Sqrt(alpha)
+		if alpha == 0 {
+			continue
+		}
+		if h[k+1][k] > 0 {
+			alpha = -alpha
+		}
+
+		// Householder vector v = x - αe₁ for the column below the diagonal
+		vnorm := 0.0
+		for i := k + 1; i < n; i++ {
+			v[i] = h[i][k]
+			if i == k+1 {
+				v[i] -= alpha
+			}
+			vnorm += v[i] * v[i]
+		}
+		if vnorm == 0 {
+			continue
+		}
+
+		// H = (I - 2vvᵀ/vᵀv) H (I - 2vvᵀ/vᵀv)
+		for j := 0; j < n; j++ {
+			s := 0.0
+			for i := k + 1; i < n; i++ {
+				s += v[i] * h[i][j]
+			}
+			s = 2 * s / vnorm
+			for i := k + 1; i < n; i++ {
+				h[i][j] -= s * v[i]
+			}
+		}
+		for i := 0; i < n; i++ {
+			s := 0.0
+			for j := k + 1; j < n; j++ {
+				s += h[i][j] * v[j]
+			}
+			s = 2 * s / vnorm
+			for j := k + 1; j < n; j++ {
+				h[i][j] -= s * v[j]
+			}
+		}
+		for i := k + 2; i < n; i++ {
+			h[i][k] = 0
+		}
+	}
+	return h
+}
+
+// hqr computes the eigenvalues of the upper Hessenberg matrix a (destroyed)
+// by the Francis double-shift QR algorithm, returning real and imaginary
+// parts. Each QR step is recorded in res.History, and ctx is checked
+// before it.
+func hqr(ctx context.Context, a [][]float64, tol float64, res *EigenResult) ([]float64, []float64, error) {
+	n := len(a)
+	wr := make([]float64, n)
+	wi := make([]float64, n)
+
+	anorm := 0.0
+	for i := 0; i < n; i++ {
+		for j := i - 1; j < n; j++ {
+			if j >= 0 {
+				anorm += math.Abs(a[i][j])
+			}
+		}
+	}
+
+	var p, q, r, s, t, w, x, y, z float64
+	nn := n - 1
+	for nn >= 0 {
+		its := 0
+		for {
+			// Look for a single small subdiagonal element
+			l := nn
+			for ; l >= 1; l-- {
+				s = math.Abs(a[l-1][l-1]) + math.Abs(a[l][l])
+				if s == 0 {
+					s = anorm
+				}
+				if math.Abs(a[l][l-1]) <= tol*s {
+					a[l][l-1] = 0
+					break
+				}
+			}
+
+			x = a[nn][nn]
+			if l == nn {
+				// One root found
+				wr[nn] = x + t
+				wi[nn] = 0
+				nn--
+				break
+			}
+			y = a[nn-1][nn-1]
+			w = a[nn][nn-1] * a[nn-1][nn]
+			if l == nn-1 {
+				// Two roots found
+				p = 0.5 * (y - x)
+				q = p*p + w
+				z = math.Sqrt(math.Abs(q))
+				x += t
+				if q >= 0 {
+					z = p + math.Copysign(z, p)
+					wr[nn-1] = x + z
+					wr[nn] = wr[nn-1]
+					if z != 0 {
+						wr[nn] = x - w/z
+					}
+					wi[nn-1], wi[nn] = 0, 0
+				} else {
+					wr[nn-1] = x + p
+					wr[nn] = x + p
+					wi[nn-1] = -z
+					wi[nn] = z
+				}
+				nn -= 2
+				break
+			}
+
+			if res.Iterations >= maxEigenIter {
+				return nil, nil, fmt.Errorf("QR algorithm did not converge within %d iterations", maxEigenIter)
+			}
+			if err := cancelled(ctx); err != nil {
+				return nil, nil, err
+			}
+			if its == 10 || its == 20 {
+				// Exceptional shift to break a cycle
+				t += x
+				for i := 0; i <= nn; i++ {
+					a[i][i] -= x
+				}
+				s = math.Abs(a[nn][nn-1]) + math.Abs(a[nn-1][nn-2])
+				x = 0.75 * s
+				y = x
+				w = -0.4375 * s * s
+			}
+			its++
+			res.Iterations++
+			res.History = append(res.History, EigenStep{
+				Iteration:  res.Iterations,
+				Value:      a[nn][nn] + t,
+				Difference: math.Abs(a[nn][nn-1]),
+			})
+
+			// Form the shift and look for two consecutive small
+			// subdiagonal elements
+			m := nn - 2
+			for ; m >= l; m-- {
+				z = a[m][m]
+				r = x - z
+				s = y - z
+				p = (r*s-w)/a[m+1][m] + a[m][m+1]
+				q = a[m+1][m+1] - z - r - s
+				r = a[m+2][m+1]
+				s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+				p /= s
+				q /= s
+				r /= s
+				if m == l {
+					break
+				}
+				u := math.Abs(a[m][m-1]) * (math.Abs(q) + math.Abs(r))
+				v := math.Abs(p) * (math.Abs(a[m-1][m-1]) + math.Abs(z) + math.Abs(a[m+1][m+1]))
+				if u <= epsilon*v {
+					break
+				}
+			}
+			for i := m + 2; i <= nn; i++ {
+				a[i][i-2] = 0
+				if i != m+2 {
+					a[i][i-3] = 0
+				}
+			}
+
+			// Double QR step on rows l..nn and columns m..nn
+			for k := m; k <= nn-1; k++ {
+				if k != m {
+					p = a[k][k-1]
+					q = a[k+1][k-1]
+					r = 0
+					if k != nn-1 {
+						r = a[k+2][k-1]
+					}
+					x = math.Abs(p) + math.Abs(q) + math.Abs(r)
+					if x != 0 {
+						p /= x
+						q /= x
+						r /= x
+					}
+				}
+				s = math.Copysign(math.Sqrt(p*p+q*q+r*r), p)
+				if s == 0 {
+					continue
+				}
+				if k == m {
+					if l != m {
+						a[k][k-1] = -a[k][k-1]
+					}
+				} else {
+					a[k][k-1] = -s * x
+				}
+				p += s
+				x = p / s
+				y = q / s
+				z = r / s
+				q /= p
+				r /= p
+				for j := k; j <= nn; j++ {
+					p = a[k][j] + q*a[k+1][j]
+					if k != nn-1 {
+						p += r * a[k+2][j]
+						a[k+2][j] -= p * z
+					}
+					a[k+1][j] -= p * y
+					a[k][j] -= p * x
+				}
+				mmin := nn
+				if k+3 < nn {
+					mmin = k + 3
+				}
+				for i := l; i <= mmin; i++ {
+					p = x*a[i][k] + y*a[i][k+1]
+					if k != nn-1 {
+						p += z * a[i][k+2]
+						a[i][k+2] -= p * r
+					}
+					a[i][k+1] -= p * q
+					a[i][k] -= p
+				}
+			}
+		}
+	}
+	return wr, wi, nil
+}
+
+// IterationMatrix returns the Jacobi iteration matrix C = I - D⁻¹A.
+func IterationMatrix(A [][]float64) ([][]float64, error) {
+	if err := checkSquare(A); err != nil {
+		return nil, err
+	}
+	n := len(A)
+	c := make([][]float64, n)
+	for i := range A {
+		if A[i][i] == 0 {
+			return nil, fmt.Errorf("zero on the diagonal in row %d", i+1)
+		}
+		c[i] = make([]float64, n)
+		for j := range A[i] {
+			if j != i {
+				c[i][j] = -A[i][j] / A[i][i]
+			}
+		}
+	}
+	return c, nil
+}
+
+func startVector(n int) []float64 {
+	v := make([]float64, n)
+	norm := 0.0
+	for i := range v {
+		v[i] = 1 - float64(i)/float64(2*n)
+		norm += v[i] * v[i]
+	}
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+// normalizeSign flips v so that its largest component is positive.
+func normalizeSign(v []float64) []float64 {
+	k := 0
+	for i := range v {
+		if math.Abs(v[i]) > math.Abs(v[k]) {
+			k = i
+		}
+	}
+	if v[k] < 0 {
+		for i := range v {
+			v[i] = -v[i]
+		}
+	}
+	return v
+}
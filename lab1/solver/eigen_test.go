@@ -0,0 +1,127 @@
+package solver
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+)
+
+var (
+	rotation  = [][]float64{{0, 1}, {-1, 0}}
+	fourCycle = [][]float64{{0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}, {1, 0, 0, 0}}
+	symmetric = [][]float64{{2, 1}, {1, 2}}
+)
+
+func TestPowerMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       [][]float64
+		want    float64
+		wantErr bool
+	}{
+		{"symmetric", symmetric, 3, false},
+		{"triangular", [][]float64{{5, 1, 0}, {0, 2, 1}, {0, 0, 1}}, 5, false},
+		{"negative dominant", [][]float64{{-4, 1}, {0, 1}}, -4, false},
+		{"rotation", rotation, 0, true},
+		{"permutation cycle", fourCycle, 0, true},
+		{"opposite eigenvalues", [][]float64{{1, 0}, {0, -1}}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := PowerMethod(tt.A, 1e-10)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got λ = %v", res.Values[0])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := real(res.Values[0]); math.Abs(got-tt.want) > 1e-8 {
+				t.Errorf("λ = %g, want %g", got, tt.want)
+			}
+			if r := eigenResidual(tt.A, res.Vectors[0], real(res.Values[0])); r > 1e-8 {
+				t.Errorf("‖Av - λv‖ = %g", r)
+			}
+		})
+	}
+}
+
+func TestInversePowerMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       [][]float64
+		shift   float64
+		want    float64
+		wantErr bool
+	}{
+		{"symmetric lower", symmetric, 0.9, 1, false},
+		{"symmetric upper", symmetric, 2.6, 3, false},
+		{"shift on eigenvalue", symmetric, 1, 1, false},
+		{"rotation", rotation, 0.5, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := InversePowerMethod(tt.A, tt.shift, 1e-10)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got λ = %v", res.Values[0])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := real(res.Values[0]); math.Abs(got-tt.want) > 1e-8 {
+				t.Errorf("λ = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQREigen(t *testing.T) {
+	tests := []struct {
+		name string
+		A    [][]float64
+		want []complex128
+	}{
+		{"rotation", rotation, []complex128{-1i, 1i}},
+		{"permutation cycle", fourCycle, []complex128{-1, -1i, 1i, 1}},
+		{"companion of (x-1)(x-2)(x-3)", [][]float64{{6, -11, 6}, {1, 0, 0}, {0, 1, 0}}, []complex128{1, 2, 3}},
+		{"complex pair", [][]float64{{0, -2}, {1, 2}}, []complex128{1 - 1i, 1 + 1i}},
+		{"symmetric", [][]float64{{4, 1, 0}, {1, 3, 1}, {0, 1, 2}}, []complex128{complex(3-math.Sqrt(3), 0), 3, complex(3+math.Sqrt(3), 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := QREigen(tt.A, 1e-12)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := append([]complex128(nil), res.Values...)
+			sort.Slice(got, func(i, j int) bool {
+				if math.Abs(real(got[i])-real(got[j])) > 1e-9 {
+					return real(got[i]) < real(got[j])
+				}
+				return imag(got[i]) < imag(got[j])
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d eigenvalues, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if cmplx.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("eigenvalues %v, want %v", got, tt.want)
+					break
+				}
+			}
+			for i, v := range res.Vectors {
+				if v != nil {
+					if r := eigenResidual(tt.A, v, real(res.Values[i])); r > 1e-8 {
+						t.Errorf("eigenvector %d: ‖Av - λv‖ = %g", i, r)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,495 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Preconditioner selects the preconditioner applied by the Krylov methods.
+type Preconditioner int
+
+const (
+	NoPreconditioner Preconditioner = iota
+	JacobiPreconditioner
+	ILU0
+)
+
+func (p Preconditioner) String() string {
+	switch p {
+	case NoPreconditioner:
+		return "none"
+	case JacobiPreconditioner:
+		return "Jacobi"
+	case ILU0:
+		return "ILU(0)"
+	default:
+		return fmt.Sprintf("Preconditioner(%d)", int(p))
+	}
+}
+
+// ParsePreconditioner returns the preconditioner with the given short name:
+// none, jacobi or ilu0.
+func ParsePreconditioner(name string) (Preconditioner, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return NoPreconditioner, nil
+	case "jacobi":
+		return JacobiPreconditioner, nil
+	case "ilu0", "ilu", "ilu(0)":
+		return ILU0, nil
+	default:
+		return 0, fmt.Errorf("unknown preconditioner %q", name)
+	}
+}
+
+// defaultRestart is the GMRES restart length used when Options.Restart is 0.
+const defaultRestart = 30
+
+// solveKrylov dispatches to the Krylov method selected in opts.
+func solveKrylov(A *CSR, b []float64, precision float64, opts Options) (*Result, error) {
+	n := A.N
+	if n == 0 || len(b) != n {
+		return nil, fmt.Errorf("invalid matrix or vector dimensions")
+	}
+
+	precond, err := newPreconditioner(A, opts.Preconditioner)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := newKrylovRecorder(A, b, precision, opts)
+	var x []float64
+	switch opts.Method {
+	case ConjugateGradient:
+		x, err = conjugateGradient(A, b, precond, rec)
+	case GMRES:
+		restart := opts.Restart
+		if restart <= 0 {
+			restart = defaultRestart
+		}
+		x, err = gmres(A, b, precond, restart, rec)
+	case BiCGSTAB:
+		x, err = biCGSTAB(A, b, precond, rec)
+	default:
+		return nil, fmt.Errorf("%s is not a Krylov method", opts.Method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Solution:   x,
+		Iterations: rec.iterations,
+		Errors:     rec.errors,
+		MatrixNorm: sparseNorm(A),
+		Method:     opts.Method,
+		Residuals:  residual(A, x, b),
+		Trace:      rec.steps,
+	}, nil
+}
+
+// krylovRecorder applies the stopping rule selected in Options to the
+// iterates of a Krylov method and records the trace.
+type krylovRecorder struct {
+	A          *CSR
+	b          []float64
+	trace      bool
+	stop       *stopper
+	iterations int
+	xPrev      []float64
+	errors     []float64
+	steps      []TraceStep
+}
+
+func newKrylovRecorder(A *CSR, b []float64, precision float64, opts Options) *krylovRecorder {
+	return &krylovRecorder{
+		A:      A,
+		b:      b,
+		trace:  opts.Trace,
+		stop:   newStopper(opts, precision),
+		xPrev:  make([]float64, A.N),
+		errors: make([]float64, A.N),
+	}
+}
+
+// step records the iterate x and reports whether the method has converged.
+// It fails on divergence or once the iteration limit is reached.
+func (r *krylovRecorder) step(x []float64) (bool, error) {
+	r.iterations++
+	maxError := 0.0
+	for i := range x {
+		r.errors[i] = math.Abs(x[i] - r.xPrev[i])
+		maxError = math.Max(maxError, r.errors[i])
+	}
+	copy(r.xPrev, x)
+
+	if r.trace {
+		r.steps = append(r.steps, TraceStep{
+			Iteration:  r.iterations,
+			X:          append([]float64(nil), x...),
+			Difference: maxError,
+			Residual:   maxNorm(residual(r.A, x, r.b)),
+		})
+	}
+
+	return r.stop.check(r.iterations, maxError, maxNorm(x), func() float64 {
+		return maxNorm(residual(r.A, x, r.b))
+	})
+}
+
+// conjugateGradient runs preconditioned CG. A must be symmetric positive
+// definite.
+func conjugateGradient(A *CSR, b []float64, precond preconditioner, rec *krylovRecorder) ([]float64, error) {
+	if !A.isSymmetric() {
+		return nil, fmt.Errorf("conjugate gradient requires a symmetric matrix; use GMRES or BiCGSTAB")
+	}
+
+	n := A.N
+	x := make([]float64, n)
+	r := append([]float64(nil), b...)
+	z := make([]float64, n)
+	precond.apply(z, r)
+	p := append([]float64(nil), z...)
+	ap := make([]float64, n)
+	rz := dot(r, z)
+
+	for {
+		if rz == 0 {
+			_, err := rec.step(x)
+			return x, err
+		}
+
+		A.MulVec(ap, p)
+		pap := dot(p, ap)
+		if pap <= 0 {
+			return nil, fmt.Errorf("matrix is not positive definite")
+		}
+		alpha := rz / pap
+		for i := range x {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+		}
+
+		if done, err := rec.step(x); done || err != nil {
+			return x, err
+		}
+
+		precond.apply(z, r)
+		rzNew := dot(r, z)
+		beta := rzNew / rz
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+		rz = rzNew
+	}
+}
+
+// gmres runs right-preconditioned GMRES(restart) with modified Gram-Schmidt
+// and Givens rotations. Every inner step counts as one iteration.
+func gmres(A *CSR, b []float64, precond preconditioner, restart int, rec *krylovRecorder) ([]float64, error) {
+	n := A.N
+	if restart > n {
+		restart = n
+	}
+	x0 := make([]float64, n)
+	x := make([]float64, n)
+	r := make([]float64, n)
+	w := make([]float64, n)
+	z := make([]float64, n)
+
+	v := make([][]float64, restart+1)
+	h := make([][]float64, restart+1)
+	for i := range v {
+		v[i] = make([]float64, n)
+		h[i] = make([]float64, restart)
+	}
+	cs := make([]float64, restart)
+	sn := make([]float64, restart)
+	g := make([]float64, restart+1)
+	y := make([]float64, restart)
+
+	for {
+		A.MulVec(r, x0)
+		for i := range r {
+			r[i] = b[i] - r[i]
+		}
+		beta := math.Sqrt(dot(r, r))
+		if beta == 0 {
+			_, err := rec.step(x0)
+			return x0, err
+		}
+		for i := range r {
+			v[0][i] = r[i] / beta
+		}
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = beta
+
+		for j := 0; j < restart; j++ {
+			precond.apply(z, v[j])
+			A.MulVec(w, z)
+			for i := 0; i <= j; i++ {
+				h[i][j] = dot(w, v[i])
+				for k := range w {
+					w[k] -= h[i][j] * v[i][k]
+				}
+			}
+			h[j+1][j] = math.Sqrt(dot(w, w))
+			lucky := h[j+1][j] <= 1e-14*beta
+			if !lucky {
+				for k := range w {
+					v[j+1][k] = w[k] / h[j+1][j]
+				}
+			}
+
+			// Apply the previous rotations to the new column, then zero h[j+1][j]
+			for i := 0; i < j; i++ {
+				hi, hi1 := h[i][j], h[i+1][j]
+				h[i][j] = cs[i]*hi + sn[i]*hi1
+				h[i+1][j] = -sn[i]*hi + cs[i]*hi1
+			}
+			denom := math.Hypot(h[j][j], h[j+1][j])
+			if denom == 0 {
+				return nil, fmt.Errorf("GMRES breakdown: singular Hessenberg matrix")
+			}
+			cs[j], sn[j] = h[j][j]/denom, h[j+1][j]/denom
+			h[j][j] = denom
+			h[j+1][j] = 0
+			g[j+1] = -sn[j] * g[j]
+			g[j] = cs[j] * g[j]
+
+			// x = x0 + M⁻¹Vy with Hy = g
+			for i := j; i >= 0; i-- {
+				sum := g[i]
+				for k := i + 1; k <= j; k++ {
+					sum -= h[i][k] * y[k]
+				}
+				y[i] = sum / h[i][i]
+			}
+			for k := range w {
+				w[k] = 0
+				for i := 0; i <= j; i++ {
+					w[k] += y[i] * v[i][k]
+				}
+			}
+			precond.apply(z, w)
+			for k := range x {
+				x[k] = x0[k] + z[k]
+			}
+
+			if done, err := rec.step(x); done || err != nil {
+				return x, err
+			}
+			if lucky {
+				break
+			}
+		}
+		copy(x0, x)
+	}
+}
+
+// biCGSTAB runs right-preconditioned BiCGSTAB.
+func biCGSTAB(A *CSR, b []float64, precond preconditioner, rec *krylovRecorder) ([]float64, error) {
+	n := A.N
+	x := make([]float64, n)
+	r := append([]float64(nil), b...)
+	rHat := append([]float64(nil), b...)
+	p := make([]float64, n)
+	v := make([]float64, n)
+	s := make([]float64, n)
+	t := make([]float64, n)
+	y := make([]float64, n)
+	z := make([]float64, n)
+	rho, alpha, omega := 1.0, 1.0, 1.0
+
+	for {
+		if dot(r, r) == 0 {
+			_, err := rec.step(x)
+			return x, err
+		}
+
+		rhoNew := dot(rHat, r)
+		if rhoNew == 0 {
+			return nil, fmt.Errorf("BiCGSTAB breakdown: ρ = 0")
+		}
+		beta := (rhoNew / rho) * (alpha / omega)
+		for i := range p {
+			p[i] = r[i] + beta*(p[i]-omega*v[i])
+		}
+		precond.apply(y, p)
+		A.MulVec(v, y)
+		rv := dot(rHat, v)
+		if rv == 0 {
+			return nil, fmt.Errorf("BiCGSTAB breakdown: (r̂, v) = 0")
+		}
+		alpha = rhoNew / rv
+		for i := range s {
+			s[i] = r[i] - alpha*v[i]
+		}
+
+		precond.apply(z, s)
+		A.MulVec(t, z)
+		tt := dot(t, t)
+		if tt == 0 {
+			for i := range x {
+				x[i] += alpha * y[i]
+			}
+			_, err := rec.step(x)
+			return x, err
+		}
+		omega = dot(t, s) / tt
+		for i := range x {
+			x[i] += alpha*y[i] + omega*z[i]
+			r[i] = s[i] - omega*t[i]
+		}
+
+		if done, err := rec.step(x); done || err != nil {
+			return x, err
+		}
+		if omega == 0 {
+			return nil, fmt.Errorf("BiCGSTAB breakdown: ω = 0")
+		}
+		rho = rhoNew
+	}
+}
+
+// preconditioner applies M⁻¹ to a vector.
+type preconditioner interface {
+	apply(dst, src []float64)
+}
+
+func newPreconditioner(A *CSR, kind Preconditioner) (preconditioner, error) {
+	switch kind {
+	case NoPreconditioner:
+		return identityPreconditioner{}, nil
+	case JacobiPreconditioner:
+		d := make([]float64, A.N)
+		for i := range d {
+			d[i] = A.diagonal(i)
+			if d[i] == 0 {
+				return nil, fmt.Errorf("Jacobi preconditioner needs a non-zero diagonal (row %d)", i+1)
+			}
+		}
+		return jacobiPreconditioner(d), nil
+	case ILU0:
+		return newILU0(A)
+	default:
+		return nil, fmt.Errorf("unknown preconditioner %s", kind)
+	}
+}
+
+type identityPreconditioner struct{}
+
+func (identityPreconditioner) apply(dst, src []float64) { copy(dst, src) }
+
+type jacobiPreconditioner []float64
+
+func (d jacobiPreconditioner) apply(dst, src []float64) {
+	for i := range dst {
+		dst[i] = src[i] / d[i]
+	}
+}
+
+// ilu0 is an incomplete LU factorization with the sparsity pattern of A.
+// L (unit diagonal) and U share the CSR arrays; diag[i] is the position of
+// uᵢᵢ in row i.
+type ilu0 struct {
+	m    *CSR
+	diag []int
+}
+
+func newILU0(A *CSR) (*ilu0, error) {
+	n := A.N
+	m := &CSR{
+		N:      n,
+		RowPtr: A.RowPtr,
+		ColIdx: A.ColIdx,
+		Vals:   append([]float64(nil), A.Vals...),
+	}
+	diag := make([]int, n)
+	pos := make([]int, n) // column -> position in the current row, or -1
+	for j := range pos {
+		pos[j] = -1
+	}
+
+	for i := 0; i < n; i++ {
+		diag[i] = -1
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			pos[m.ColIdx[k]] = k
+			if m.ColIdx[k] == i {
+				diag[i] = k
+			}
+		}
+		if diag[i] == -1 {
+			return nil, fmt.Errorf("ILU(0) needs a non-zero diagonal (row %d)", i+1)
+		}
+
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1] && m.ColIdx[k] < i; k++ {
+			col := m.ColIdx[k]
+			m.Vals[k] /= m.Vals[diag[col]]
+			for kk := diag[col] + 1; kk < m.RowPtr[col+1]; kk++ {
+				if p := pos[m.ColIdx[kk]]; p != -1 {
+					m.Vals[p] -= m.Vals[k] * m.Vals[kk]
+				}
+			}
+		}
+		if m.Vals[diag[i]] == 0 {
+			return nil, fmt.Errorf("ILU(0) breakdown: zero pivot in row %d", i+1)
+		}
+
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			pos[m.ColIdx[k]] = -1
+		}
+	}
+
+	return &ilu0{m: m, diag: diag}, nil
+}
+
+func (f *ilu0) apply(dst, src []float64) {
+	m := f.m
+	for i := 0; i < m.N; i++ {
+		sum := src[i]
+		for k := m.RowPtr[i]; k < f.diag[i]; k++ {
+			sum -= m.Vals[k] * dst[m.ColIdx[k]]
+		}
+		dst[i] = sum
+	}
+	for i := m.N - 1; i >= 0; i-- {
+		sum := dst[i]
+		for k := f.diag[i] + 1; k < m.RowPtr[i+1]; k++ {
+			sum -= m.Vals[k] * dst[m.ColIdx[k]]
+		}
+		dst[i] = sum / m.Vals[f.diag[i]]
+	}
+}
+
+// isSymmetric reports whether aᵢⱼ = aⱼᵢ for every stored entry.
+func (m *CSR) isSymmetric() bool {
+	for i := 0; i < m.N; i++ {
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			j := m.ColIdx[k]
+			if j > i && math.Abs(m.Vals[k]-m.At(j, i)) > 1e-12*math.Max(1, math.Abs(m.Vals[k])) {
+				return false
+			}
+		}
+	}
+	// Entries below the diagonal without a partner above it
+	for i := 0; i < m.N; i++ {
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			if j := m.ColIdx[k]; j < i && m.At(j, i) == 0 && m.Vals[k] != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
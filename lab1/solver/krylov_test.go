@@ -0,0 +1,101 @@
+package solver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKrylov(t *testing.T) {
+	n := 50
+	lower, diag, upper := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range diag {
+		lower[i], diag[i], upper[i] = -1, 2, -1
+	}
+	poisson, poissonB := tridiagonal(lower, diag, upper)
+	for i := range diag {
+		lower[i], diag[i], upper[i] = -2, 5, 1
+	}
+	convection, convectionB := tridiagonal(lower, diag, upper)
+	dense := DenseToCSR([][]float64{{4, -1, 2}, {3, 6, -1}, {1, -2, 5}})
+	denseB := make([]float64, 3)
+	dense.MulVec(denseB, exactSolution(3))
+
+	tests := []struct {
+		name    string
+		A       *CSR
+		b       []float64
+		methods []Method
+	}{
+		{"symmetric", poisson, poissonB, []Method{ConjugateGradient, GMRES, BiCGSTAB}},
+		{"nonsymmetric", convection, convectionB, []Method{GMRES, BiCGSTAB}},
+		{"dense nonsymmetric", dense, denseB, []Method{GMRES, BiCGSTAB}},
+	}
+	for _, tt := range tests {
+		want := exactSolution(tt.A.N)
+		for _, method := range tt.methods {
+			for _, precond := range []Preconditioner{NoPreconditioner, JacobiPreconditioner, ILU0} {
+				opts := Options{Method: method, Preconditioner: precond, Stop: StopResidual}
+				res, err := SolveSparse(tt.A, tt.b, 1e-10, opts)
+				if err != nil {
+					t.Errorf("%s, %s, %s: %v", tt.name, method, precond, err)
+					continue
+				}
+				for i, x := range res.Solution {
+					if math.Abs(x-want[i]) > 1e-6*float64(tt.A.N) {
+						t.Errorf("%s, %s, %s: x[%d] = %g, want %g", tt.name, method, precond, i, x, want[i])
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConjugateGradientNonsymmetric(t *testing.T) {
+	A := DenseToCSR([][]float64{{4, 1}, {2, 3}})
+	if _, err := SolveSparse(A, []float64{1, 1}, 1e-10, Options{Method: ConjugateGradient}); err == nil {
+		t.Error("no error for a nonsymmetric matrix")
+	}
+}
+
+func TestILU0(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       [][]float64
+		exact   bool // the pattern of A admits no fill-in, so ILU(0) = LU
+		wantErr bool
+	}{
+		{"tridiagonal", [][]float64{{4, -1, 0, 0}, {-2, 4, -1, 0}, {0, -2, 4, -1}, {0, 0, -2, 4}}, true, false},
+		{"dense", [][]float64{{4, -1, 2}, {3, 6, -1}, {1, -2, 5}}, true, false},
+		{"arrow", [][]float64{{4, 1, 1, 1}, {1, 4, 0, 0}, {1, 0, 4, 0}, {1, 0, 0, 4}}, false, false},
+		{"zero diagonal", [][]float64{{0, 1}, {1, 0}}, false, true},
+		{"zero pivot", [][]float64{{1, 1}, {1, 1}}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			A := DenseToCSR(tt.A)
+			f, err := newILU0(A)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// M⁻¹Ax = x when M = A; otherwise M⁻¹ only approximates A⁻¹
+			n := A.N
+			x, ax, got := exactSolution(n), make([]float64, n), make([]float64, n)
+			A.MulVec(ax, x)
+			f.apply(got, ax)
+			tol := 1e-12
+			if !tt.exact {
+				tol = 0.5
+			}
+			for i := range x {
+				if math.Abs(got[i]-x[i]) > tol*math.Abs(x[i]) {
+					t.Errorf("M⁻¹Ax = %v, want %v", got, x)
+					break
+				}
+			}
+		})
+	}
+}
@@ -1,47 +1,297 @@
 package solver
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"strings"
 )
 
+// Method selects the iteration scheme used by Solve.
+type Method int
+
+const (
+	Jacobi Method = iota
+	GaussSeidel
+	SOR
+	Gauss
+	LUDecomposition
+	CholeskyDecomposition
+	ConjugateGradient
+	GMRES
+	BiCGSTAB
+)
+
+func (m Method) String() string {
+	switch m {
+	case Jacobi:
+		return "Jacobi"
+	case GaussSeidel:
+		return "Gauss-Seidel"
+	case SOR:
+		return "SOR"
+	case Gauss:
+		return "Gaussian elimination"
+	case LUDecomposition:
+		return "LU decomposition"
+	case CholeskyDecomposition:
+		return "Cholesky decomposition"
+	case ConjugateGradient:
+		return "Conjugate gradient"
+	case GMRES:
+		return "GMRES"
+	case BiCGSTAB:
+		return "BiCGSTAB"
+	default:
+		return fmt.Sprintf("Method(%d)", int(m))
+	}
+}
+
+// methodNames maps the short names accepted by ParseMethod to methods. The
+// first name listed for a method is its canonical name.
+var methodNames = []struct {
+	name   string
+	method Method
+}{
+	{"jacobi", Jacobi},
+	{"simple", Jacobi},
+	{"seidel", GaussSeidel},
+	{"gauss-seidel", GaussSeidel},
+	{"sor", SOR},
+	{"gauss", Gauss},
+	{"lu", LUDecomposition},
+	{"cholesky", CholeskyDecomposition},
+	{"cg", ConjugateGradient},
+	{"gmres", GMRES},
+	{"bicgstab", BiCGSTAB},
+}
+
+// ParseMethod returns the method with the given short name, such as
+// "seidel" or "gmres".
+func ParseMethod(name string) (Method, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, m := range methodNames {
+		if m.name == name {
+			return m.method, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown method %q", name)
+}
+
+// Name returns the canonical short name of m accepted by ParseMethod.
+func (m Method) Name() string {
+	for _, entry := range methodNames {
+		if entry.method == m {
+			return entry.name
+		}
+	}
+	return m.String()
+}
+
+// IsDirect reports whether m solves the system by factorization rather
+// than by iteration.
+func (m Method) IsDirect() bool {
+	return m == Gauss || m == LUDecomposition || m == CholeskyDecomposition
+}
+
+// IsKrylov reports whether m is a Krylov subspace method.
+func (m Method) IsKrylov() bool {
+	return m == ConjugateGradient || m == GMRES || m == BiCGSTAB
+}
+
+// Options configures Solve. The zero value runs Jacobi iteration.
+type Options struct {
+	Method Method
+	// Omega is the SOR relaxation factor. Zero means estimate it from the
+	// spectral radius of the Jacobi iteration matrix.
+	Omega float64
+	// Preconditioner is applied by the Krylov methods.
+	Preconditioner Preconditioner
+	// Restart is the GMRES restart length; zero means 30.
+	Restart int
+	// Backend selects the number type; the zero value is float64.
+	Backend Backend
+	// Bits is the big.Float mantissa size; zero means 256.
+	Bits uint
+	// Trace records every iterate in Result.Trace.
+	Trace bool
+	// MaxIterations limits iterative methods; zero means 10000.
+	MaxIterations int
+	// Stop selects the stopping criterion of iterative methods.
+	Stop StopCriterion
+	// DivergenceSteps, when positive, stops an iterative method once
+	// ‖xₖ - xₖ₋₁‖∞ has grown for that many consecutive iterations.
+	// Non-finite iterates are always reported as divergence.
+	DivergenceSteps int
+	// Progress, when set, is called after every iteration of an iterative
+	// method with the iteration number and ‖xₖ - xₖ₋₁‖∞.
+	Progress func(iteration int, maxError float64)
+
+	ctx context.Context
+}
+
+// checkpoint reports an iteration to Progress and fails once the context
+// passed to SolveContext is done.
+func (o Options) checkpoint(iteration int, maxError float64) error {
+	if o.Progress != nil {
+		o.Progress(iteration, maxError)
+	}
+	if o.ctx == nil {
+		return nil
+	}
+	if err := o.ctx.Err(); err != nil {
+		return fmt.Errorf("solve cancelled after %d iterations: %w", iteration, err)
+	}
+	return nil
+}
+
+// cancelled returns the error of ctx, wrapped, once it is done; a nil ctx
+// is never done. Loops with no iteration count to report, such as the
+// elimination steps of direct methods and the power iterations of the
+// analyses, call it once per step.
+func cancelled(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("cancelled: %w", err)
+	}
+	return nil
+}
+
+// TraceStep is one recorded iteration of an iterative method.
+type TraceStep struct {
+	Iteration int
+	X         []float64
+	// Difference is ‖xₖ - xₖ₋₁‖∞.
+	Difference float64
+	// Residual is ‖Axₖ - b‖∞.
+	Residual float64
+}
+
 type Result struct {
 	Solution   []float64
 	Iterations int
 	Errors     []float64
+	// MatrixNorm is ‖C‖∞ of the iteration matrix C = I - D⁻¹A for Jacobi,
+	// Gauss-Seidel and SOR, and ‖A‖∞ for direct and Krylov methods.
 	MatrixNorm float64
+	Method     Method
+	Omega      float64
+	// Determinant is det(A); it is only computed by direct methods.
+	Determinant float64
+	// Residuals is b - Ax for the returned solution.
+	Residuals []float64
+	// Convergence is the analysis of the iteration matrix; it is nil for
+	// direct and Krylov methods.
+	Convergence *Convergence
+	// Q is the contraction factor used by the error bounds: the norm bound
+	// Convergence.SweepNorm of the iteration operator when it is below one,
+	// the estimated spectral radius otherwise.
+	Q float64
+	// BoundsEstimated reports that Q is a spectral radius rather than a
+	// norm, so APrioriIterations and APosterioriBound hold only
+	// asymptotically and are estimates, not bounds.
+	BoundsEstimated bool
+	// APrioriIterations is ln(ε(1-q)/‖x₁-x₀‖)/ln q rounded up, the number of
+	// iterations predicted before the solve starts.
+	APrioriIterations int
+	// APosterioriBound is q/(1-q)·‖xₖ-xₖ₋₁‖ for the final iterate.
+	APosterioriBound float64
+	// Trace holds every iterate when Options.Trace is set.
+	Trace []TraceStep
+	// Backend is the number type the solution was computed with.
+	Backend Backend
+	// ExactSolution holds the solution at the full precision of a big.Float
+	// or big.Rat backend; it is nil for float64.
+	ExactSolution []string
 }
 
+// SolveSystem solves Ax = b with Jacobi (simple) iteration.
 func SolveSystem(A [][]float64, b []float64, precision float64) (*Result, error) {
+	return Solve(A, b, precision, Options{Method: Jacobi})
+}
+
+// SolveContext is Solve with cancellation: iterative methods check ctx
+// after every iteration, and direct methods and the convergence analysis
+// after every elimination or power-iteration step, and return its error,
+// wrapped, once it is done.
+func SolveContext(ctx context.Context, A [][]float64, b []float64, precision float64, opts Options) (*Result, error) {
+	opts.ctx = ctx
+	return Solve(A, b, precision, opts)
+}
+
+// Solve solves Ax = b with the method selected in opts. Direct methods
+// ignore precision.
+func Solve(A [][]float64, b []float64, precision float64, opts Options) (*Result, error) {
+	if opts.Backend != Float64 {
+		return solveBig(A, b, precision, opts)
+	}
+	if opts.Method.IsDirect() {
+		return solveDirect(opts.ctx, A, b, opts.Method)
+	}
+	if opts.Method.IsKrylov() {
+		return solveKrylov(DenseToCSR(A), b, precision, opts)
+	}
+
 	n := len(A)
 	if n == 0 || len(b) != n {
 		return nil, fmt.Errorf("invalid matrix or vector dimensions")
 	}
 
-	dominantA, dominantB, ok := enforceDiagonalDominance(A, b)
-	if !ok {
-		return nil, fmt.Errorf("unable to achieve diagonal dominance")
+	if opts.Method == SOR && opts.Omega != 0 && (opts.Omega <= 0 || opts.Omega >= 2) {
+		return nil, fmt.Errorf("relaxation factor must be in (0, 2), got %g", opts.Omega)
 	}
 
+	conv, omega, err := AnalyzeConvergence(A, opts)
+	if err != nil {
+		return nil, err
+	}
+	orderedA := permuteRows(A, conv.Permutation)
+	orderedB := make([]float64, n)
+	for i, r := range conv.Permutation {
+		orderedB[i] = b[r]
+	}
+
+	res, err := iterate(denseRows(orderedA), orderedB, precision, opts, conv, omega)
+	if err != nil {
+		return nil, err
+	}
+	res.Residuals = residual(denseRows(A), res.Solution, b)
+	return res, nil
+}
+
+// iterate runs the Jacobi, Gauss-Seidel or SOR sweep selected in opts on a
+// system whose rows are already in their final order.
+func iterate(a rows, b []float64, precision float64, opts Options, conv *Convergence, omega float64) (*Result, error) {
+	n := a.size()
 	x := make([]float64, n)      // Current solution
 	xPrev := make([]float64, n)  // Previous iteration
 	errors := make([]float64, n) // Error vector
 
+	// Jacobi reads only the previous iterate; Gauss-Seidel and SOR reuse
+	// components already updated in this sweep.
+	src := xPrev
+	if opts.Method != Jacobi {
+		src = x
+	}
+
 	iterations := 0
-	maxIter := 10000
+	stop := newStopper(opts, precision)
+	firstStep := 0.0
+	var trace []TraceStep
 
 	for {
 		iterations++
 
 		// Perform iteration
 		for i := 0; i < n; i++ {
-			sum := 0.0
-			for j := 0; j < n; j++ {
-				if j != i {
-					sum += dominantA[i][j] * xPrev[j]
-				}
+			sum, diag := a.offDiagonalDot(i, src)
+			next := (b[i] - sum) / diag
+			if opts.Method == SOR {
+				next = (1-omega)*xPrev[i] + omega*next
 			}
-			x[i] = (dominantB[i] - sum) / dominantA[i][i]
+			x[i] = next
 		}
 
 		// Calculate error and check convergence
@@ -53,65 +303,92 @@ func SolveSystem(A [][]float64, b []float64, precision float64) (*Result, error)
 			}
 		}
 
-		if maxError < precision {
-			break
+		if iterations == 1 {
+			firstStep = maxError
+		}
+		if opts.Trace {
+			trace = append(trace, TraceStep{
+				Iteration:  iterations,
+				X:          append([]float64(nil), x...),
+				Difference: maxError,
+				Residual:   maxNorm(residual(a, x, b)),
+			})
 		}
 
-		if iterations >= maxIter {
-			return nil, fmt.Errorf("solution did not converge within %d iterations", maxIter)
+		converged, err := stop.check(iterations, maxError, maxNorm(x), func() float64 {
+			return maxNorm(residual(a, x, b))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if converged {
+			break
 		}
 
 		copy(xPrev, x)
 	}
 
+	q, estimated := conv.contraction()
+	bound := math.Inf(1)
+	if q < 1 {
+		bound = q / (1 - q) * maxNorm(errors)
+	}
+
 	return &Result{
-		Solution:   x,
-		Iterations: iterations,
-		Errors:     errors,
-		MatrixNorm: calculateNorm(dominantA),
+		Solution:          x,
+		Iterations:        iterations,
+		Errors:            errors,
+		MatrixNorm:        conv.NormInf,
+		Method:            opts.Method,
+		Omega:             omega,
+		Convergence:       conv,
+		Q:                 q,
+		BoundsEstimated:   estimated,
+		APrioriIterations: aprioriIterations(q, precision, firstStep),
+		APosterioriBound:  bound,
+		Trace:             trace,
 	}, nil
 }
 
-func enforceDiagonalDominance(A [][]float64, b []float64) ([][]float64, []float64, bool) {
-	n := len(A)
-	used := make([]bool, n)
-	newA := make([][]float64, n)
-	newB := make([]float64, n)
-
-	for i := 0; i < n; i++ {
-		maxDiagonal := -1.0
-		maxRow := -1
-
-		for row := 0; row < n; row++ {
-			if used[row] {
-				continue
-			}
-
-			diagonal := math.Abs(A[row][i])
-			sum := 0.0
-			for col := 0; col < n; col++ {
-				if col != i {
-					sum += math.Abs(A[row][col])
-				}
-			}
+// aprioriIterations returns the smallest k with qᵏ/(1-q)·‖x₁-x₀‖ ≤ ε,
+// i.e. k ≥ ln(ε(1-q)/‖x₁-x₀‖)/ln q.
+// It returns 0 when q ≥ 1 and no estimate exists.
+func aprioriIterations(q, precision, firstStep float64) int {
+	if q >= 1 {
+		return 0
+	}
+	if firstStep == 0 || q == 0 {
+		return 1
+	}
+	k := math.Log(precision*(1-q)/firstStep) / math.Log(q)
+	if k < 1 {
+		return 1
+	}
+	return int(math.Ceil(k))
+}
 
-			if diagonal > sum && diagonal > maxDiagonal {
-				maxDiagonal = diagonal
-				maxRow = row
-			}
-		}
+// maxNorm returns ‖v‖∞.
+func maxNorm(v []float64) float64 {
+	norm := 0.0
+	for _, x := range v {
+		norm = math.Max(norm, math.Abs(x))
+	}
+	return norm
+}
 
-		if maxRow == -1 {
-			return A, b, false
-		}
+// EstimateOmega returns the optimal SOR relaxation factor 2/(1+sqrt(1-ρ²)),
+// where ρ is the spectral radius of the Jacobi iteration matrix of A.
+// It falls back to 1 (plain Gauss-Seidel) when ρ is not below one.
+func EstimateOmega(A [][]float64) float64 {
+	rho, _ := spectralRadius(context.Background(), len(A), sweepOperator(denseRows(A), Jacobi, 1))
+	return optimalOmega(rho)
+}
 
-		newA[i] = make([]float64, n)
-		copy(newA[i], A[maxRow])
-		newB[i] = b[maxRow]
-		used[maxRow] = true
+func optimalOmega(rho float64) float64 {
+	if rho >= 1 || math.IsNaN(rho) {
+		return 1
 	}
-
-	return newA, newB, true
+	return 2 / (1 + math.Sqrt(1-rho*rho))
 }
 
 func calculateNorm(A [][]float64) float64 {
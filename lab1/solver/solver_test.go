@@ -0,0 +1,216 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCancelled(t *testing.T) {
+	A := [][]float64{{4, 1, 0}, {1, 4, 1}, {0, 1, 4}}
+	b := []float64{5, 6, 5}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"jacobi", func() error {
+			_, err := SolveContext(ctx, A, b, 1e-9, Options{Method: Jacobi})
+			return err
+		}},
+		{"sor", func() error {
+			_, err := SolveContext(ctx, A, b, 1e-9, Options{Method: SOR})
+			return err
+		}},
+		{"gauss", func() error {
+			_, err := SolveContext(ctx, A, b, 0, Options{Method: Gauss})
+			return err
+		}},
+		{"lu", func() error {
+			_, err := SolveContext(ctx, A, b, 0, Options{Method: LUDecomposition})
+			return err
+		}},
+		{"cholesky", func() error {
+			_, err := SolveContext(ctx, A, b, 0, Options{Method: CholeskyDecomposition})
+			return err
+		}},
+		{"gauss big.Rat", func() error {
+			_, err := SolveContext(ctx, A, b, 0, Options{Method: Gauss, Backend: Rational})
+			return err
+		}},
+		{"sparse jacobi", func() error {
+			_, err := SolveSparseContext(ctx, DenseToCSR(A), b, 1e-9, Options{Method: Jacobi})
+			return err
+		}},
+		{"analysis", func() error {
+			_, err := AnalyzeMatrixContext(ctx, A)
+			return err
+		}},
+		{"power", func() error {
+			_, err := EigenContext(ctx, A, PowerIteration, 0, 1e-9)
+			return err
+		}},
+		{"inverse", func() error {
+			_, err := EigenContext(ctx, A, InverseIteration, 3, 1e-9)
+			return err
+		}},
+		{"qr", func() error {
+			_, err := EigenContext(ctx, A, QRAlgorithm, 0, 1e-9)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
+
+func TestStopCriterion(t *testing.T) {
+	// ‖xₖ - xₖ₋₁‖∞ shrinks tenfold per step while ‖xₖ‖∞ stays at 10 and
+	// the residual is twenty times the difference.
+	diffs := []float64{1, 0.1, 0.01, 0.001, 0.0001}
+	tests := []struct {
+		stop StopCriterion
+		want int
+	}{
+		{StopAbsolute, 3}, // 0.01 < 0.05
+		{StopRelative, 2}, // 0.1 < 0.05·10
+		{StopResidual, 4}, // 20·0.001 < 0.05
+	}
+	for _, tt := range tests {
+		t.Run(tt.stop.String(), func(t *testing.T) {
+			s := newStopper(Options{Stop: tt.stop}, 0.05)
+			for k, diff := range diffs {
+				converged, err := s.check(k+1, diff, 10, func() float64 { return 20 * diff })
+				if err != nil {
+					t.Fatal(err)
+				}
+				if converged {
+					if k+1 != tt.want {
+						t.Errorf("stopped at iteration %d, want %d", k+1, tt.want)
+					}
+					return
+				}
+			}
+			t.Errorf("did not stop, want iteration %d", tt.want)
+		})
+	}
+}
+
+func TestStopErrors(t *testing.T) {
+	// Jacobi on this matrix has ρ(C) = 2, so the iterates double every
+	// step; iterate is called directly because Solve refuses the system.
+	diverging := [][]float64{{1, 2}, {2, 1}}
+	slow := [][]float64{{1, 0.99}, {0.99, 1}}
+	b := []float64{1, 1}
+
+	tests := []struct {
+		name      string
+		run       func() error
+		nonFinite bool
+		limit     int
+	}{
+		{"growth", func() error {
+			_, err := iterate(denseRows(diverging), b, 1e-9, Options{DivergenceSteps: 3}, &Convergence{Permutation: []int{0, 1}}, 1)
+			return err
+		}, false, 0},
+		{"overflow", func() error {
+			_, err := iterate(denseRows(diverging), b, 1e-9, Options{}, &Convergence{Permutation: []int{0, 1}}, 1)
+			return err
+		}, true, 0},
+		{"iteration limit", func() error {
+			_, err := Solve(slow, b, 1e-12, Options{Method: GaussSeidel, MaxIterations: 5})
+			return err
+		}, false, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			var diverged *DivergenceError
+			var limit *IterationLimitError
+			switch {
+			case tt.limit > 0:
+				if !errors.As(err, &limit) {
+					t.Fatalf("error = %v, want IterationLimitError", err)
+				}
+				if limit.Iterations != tt.limit {
+					t.Errorf("stopped after %d iterations, want %d", limit.Iterations, tt.limit)
+				}
+			case !errors.As(err, &diverged):
+				t.Fatalf("error = %v, want DivergenceError", err)
+			case diverged.NonFinite != tt.nonFinite:
+				t.Errorf("NonFinite = %v, want %v", diverged.NonFinite, tt.nonFinite)
+			}
+		})
+	}
+}
+
+func TestErrorBounds(t *testing.T) {
+	// Diagonally dominant with solution (1, 1, 1): ‖C‖∞ = 0.5 and the
+	// Sassenfeld bound is 0.3125 for Gauss-Seidel and 0.478125 for SOR
+	// with ω = 1.1.
+	A := [][]float64{{4, 1, 0}, {1, 4, 1}, {0, 1, 4}}
+	b := []float64{5, 6, 5}
+
+	tests := []struct {
+		method Method
+		omega  float64
+		q      float64
+	}{
+		{Jacobi, 0, 0.5},
+		{GaussSeidel, 0, 0.3125},
+		{SOR, 1.1, 0.478125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method.String(), func(t *testing.T) {
+			res, err := Solve(A, b, 1e-10, Options{Method: tt.method, Omega: tt.omega, Trace: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.BoundsEstimated || math.Abs(res.Q-tt.q) > 1e-12 {
+				t.Fatalf("q = %g (estimated %v), want the norm bound %g", res.Q, res.BoundsEstimated, tt.q)
+			}
+			if len(res.Trace) != res.Iterations {
+				t.Fatalf("%d trace steps for %d iterations", len(res.Trace), res.Iterations)
+			}
+			if res.Iterations > res.APrioriIterations {
+				t.Errorf("took %d iterations, a priori estimate %d", res.Iterations, res.APrioriIterations)
+			}
+			prev := make([]float64, len(b))
+			for k, step := range res.Trace {
+				diff, errNorm := 0.0, 0.0
+				for i, x := range step.X {
+					diff = math.Max(diff, math.Abs(x-prev[i]))
+					errNorm = math.Max(errNorm, math.Abs(x-1))
+				}
+				prev = step.X
+				if step.Iteration != k+1 || math.Abs(step.Difference-diff) > 1e-15 {
+					t.Errorf("step %d: iteration %d, difference %g, want %g", k+1, step.Iteration, step.Difference, diff)
+				}
+				if bound := res.Q / (1 - res.Q) * step.Difference; errNorm > bound*(1+1e-9)+1e-15 {
+					t.Errorf("step %d: ‖x* - xₖ‖∞ = %g exceeds the a posteriori bound %g", k+1, errNorm, bound)
+				}
+			}
+			if errNorm := math.Abs(maxNorm(res.Solution) - 1); errNorm > res.APosterioriBound+1e-15 {
+				t.Errorf("final error %g exceeds APosterioriBound %g", errNorm, res.APosterioriBound)
+			}
+		})
+	}
+
+	// Gauss-Seidel converges on this symmetric positive definite matrix,
+	// but the Sassenfeld bound of its first row is already 1.8.
+	res, err := Solve([][]float64{{1, 0.9, 0.9}, {0.9, 1, 0.9}, {0.9, 0.9, 1}}, []float64{1, 1, 1}, 1e-8, Options{Method: GaussSeidel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.BoundsEstimated || res.Q != res.Convergence.SpectralRadius {
+		t.Errorf("q = %g, estimated = %v; want the spectral radius %g marked as an estimate",
+			res.Q, res.BoundsEstimated, res.Convergence.SpectralRadius)
+	}
+}
@@ -0,0 +1,503 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// rows is the row access the iterative methods need, implemented by both
+// dense and sparse storage.
+type rows interface {
+	size() int
+	// offDiagonalDot returns Σⱼ≠ᵢ aᵢⱼxⱼ together with aᵢᵢ.
+	offDiagonalDot(i int, x []float64) (sum, diag float64)
+	diagonal(i int) float64
+	eachOffDiagonal(i int, fn func(j int, a float64))
+}
+
+// denseRows adapts a [][]float64 matrix to rows.
+type denseRows [][]float64
+
+func (a denseRows) size() int { return len(a) }
+
+func (a denseRows) offDiagonalDot(i int, x []float64) (float64, float64) {
+	sum := 0.0
+	for j, v := range a[i] {
+		if j != i {
+			sum += v * x[j]
+		}
+	}
+	return sum, a[i][i]
+}
+
+func (a denseRows) diagonal(i int) float64 { return a[i][i] }
+
+func (a denseRows) eachOffDiagonal(i int, fn func(j int, a float64)) {
+	for j, v := range a[i] {
+		if j != i && v != 0 {
+			fn(j, v)
+		}
+	}
+}
+
+// COO is a sparse matrix in coordinate (triplet) form. It is convenient for
+// assembling a matrix entry by entry; convert it to CSR before solving.
+type COO struct {
+	N    int
+	Rows []int
+	Cols []int
+	Vals []float64
+}
+
+// NewCOO returns an empty n×n coordinate matrix.
+func NewCOO(n int) *COO {
+	return &COO{N: n}
+}
+
+// Add appends the entry aᵢⱼ = v (0-based). Duplicate entries are summed
+// when converting to CSR.
+func (c *COO) Add(i, j int, v float64) {
+	c.Rows = append(c.Rows, i)
+	c.Cols = append(c.Cols, j)
+	c.Vals = append(c.Vals, v)
+}
+
+// ToCSR converts c to compressed sparse row form.
+func (c *COO) ToCSR() (*CSR, error) {
+	order := make([]int, len(c.Vals))
+	for k := range order {
+		if c.Rows[k] < 0 || c.Rows[k] >= c.N || c.Cols[k] < 0 || c.Cols[k] >= c.N {
+			return nil, fmt.Errorf("entry (%d, %d) is outside a %dx%d matrix", c.Rows[k]+1, c.Cols[k]+1, c.N, c.N)
+		}
+		order[k] = k
+	}
+	sort.Slice(order, func(a, b int) bool {
+		ka, kb := order[a], order[b]
+		if c.Rows[ka] != c.Rows[kb] {
+			return c.Rows[ka] < c.Rows[kb]
+		}
+		return c.Cols[ka] < c.Cols[kb]
+	})
+
+	m := &CSR{N: c.N, RowPtr: make([]int, c.N+1)}
+	for idx, k := range order {
+		i, j := c.Rows[k], c.Cols[k]
+		if idx > 0 && c.Rows[order[idx-1]] == i && c.Cols[order[idx-1]] == j {
+			m.Vals[len(m.Vals)-1] += c.Vals[k]
+			continue
+		}
+		m.ColIdx = append(m.ColIdx, j)
+		m.Vals = append(m.Vals, c.Vals[k])
+		m.RowPtr[i+1]++
+	}
+	for i := 0; i < c.N; i++ {
+		m.RowPtr[i+1] += m.RowPtr[i]
+	}
+	return m, nil
+}
+
+// CSR is a square sparse matrix in compressed sparse row form: the entries
+// of row i are Vals[RowPtr[i]:RowPtr[i+1]] in the columns given by ColIdx.
+type CSR struct {
+	N      int
+	RowPtr []int
+	ColIdx []int
+	Vals   []float64
+}
+
+// DenseToCSR converts a dense square matrix, dropping zero entries.
+func DenseToCSR(A [][]float64) *CSR {
+	n := len(A)
+	m := &CSR{N: n, RowPtr: make([]int, n+1)}
+	for i := 0; i < n; i++ {
+		for j, v := range A[i] {
+			if v != 0 {
+				m.ColIdx = append(m.ColIdx, j)
+				m.Vals = append(m.Vals, v)
+			}
+		}
+		m.RowPtr[i+1] = len(m.Vals)
+	}
+	return m
+}
+
+// Validate checks the CSR invariants the solvers rely on: RowPtr has N+1
+// non-decreasing offsets from 0 to len(Vals), and the column indices of
+// every row are in range and strictly increasing. A CSR built by ToCSR or
+// DenseToCSR always satisfies them; one decoded from a file may not.
+func (m *CSR) Validate() error {
+	if m.N <= 0 || len(m.RowPtr) != m.N+1 || len(m.ColIdx) != len(m.Vals) {
+		return fmt.Errorf("invalid sparse matrix dimensions")
+	}
+	if m.RowPtr[0] != 0 || m.RowPtr[m.N] != len(m.Vals) {
+		return fmt.Errorf("row offsets must start at 0 and end at %d", len(m.Vals))
+	}
+	for i := 0; i < m.N; i++ {
+		if m.RowPtr[i+1] < m.RowPtr[i] {
+			return fmt.Errorf("row offsets decrease at row %d", i+1)
+		}
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			j := m.ColIdx[k]
+			if j < 0 || j >= m.N {
+				return fmt.Errorf("column %d in row %d is outside a %dx%d matrix", j+1, i+1, m.N, m.N)
+			}
+			if k > m.RowPtr[i] && j <= m.ColIdx[k-1] {
+				return fmt.Errorf("columns in row %d are not sorted or repeat", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// At returns aᵢⱼ.
+func (m *CSR) At(i, j int) float64 {
+	for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+		if m.ColIdx[k] == j {
+			return m.Vals[k]
+		}
+	}
+	return 0
+}
+
+// MulVec stores Ax in dst.
+func (m *CSR) MulVec(dst, x []float64) {
+	for i := 0; i < m.N; i++ {
+		sum := 0.0
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			sum += m.Vals[k] * x[m.ColIdx[k]]
+		}
+		dst[i] = sum
+	}
+}
+
+// Dense expands m into a [][]float64.
+func (m *CSR) Dense() [][]float64 {
+	A := make([][]float64, m.N)
+	for i := range A {
+		A[i] = make([]float64, m.N)
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			A[i][m.ColIdx[k]] = m.Vals[k]
+		}
+	}
+	return A
+}
+
+// Tridiagonal returns the three diagonals of m if it has no entries
+// outside them. lower[0] and upper[n-1] are unused.
+func (m *CSR) Tridiagonal() (lower, diag, upper []float64, ok bool) {
+	lower = make([]float64, m.N)
+	diag = make([]float64, m.N)
+	upper = make([]float64, m.N)
+	for i := 0; i < m.N; i++ {
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			switch m.ColIdx[k] - i {
+			case -1:
+				lower[i] = m.Vals[k]
+			case 0:
+				diag[i] = m.Vals[k]
+			case 1:
+				upper[i] = m.Vals[k]
+			default:
+				return nil, nil, nil, false
+			}
+		}
+	}
+	return lower, diag, upper, true
+}
+
+func (m *CSR) size() int { return m.N }
+
+func (m *CSR) offDiagonalDot(i int, x []float64) (float64, float64) {
+	sum, diag := 0.0, 0.0
+	for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+		if j := m.ColIdx[k]; j == i {
+			diag = m.Vals[k]
+		} else {
+			sum += m.Vals[k] * x[j]
+		}
+	}
+	return sum, diag
+}
+
+func (m *CSR) diagonal(i int) float64 { return m.At(i, i) }
+
+func (m *CSR) eachOffDiagonal(i int, fn func(j int, a float64)) {
+	for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+		if m.ColIdx[k] != i {
+			fn(m.ColIdx[k], m.Vals[k])
+		}
+	}
+}
+
+// SolveTridiagonal solves a tridiagonal system with the Thomas algorithm in
+// O(n) and returns the solution together with the determinant. lower[i] is
+// aᵢ,ᵢ₋₁ and upper[i] is aᵢ,ᵢ₊₁; lower[0] and upper[n-1] are ignored. No
+// pivoting is done, so the matrix should be diagonally dominant or SPD.
+func SolveTridiagonal(lower, diag, upper, rhs []float64) ([]float64, float64, error) {
+	n := len(diag)
+	if n == 0 || len(lower) != n || len(upper) != n || len(rhs) != n {
+		return nil, 0, fmt.Errorf("invalid matrix or vector dimensions")
+	}
+
+	c := make([]float64, n) // modified upper diagonal
+	d := make([]float64, n) // modified right-hand side
+	det := 1.0
+	for i := 0; i < n; i++ {
+		pivot := diag[i]
+		if i > 0 {
+			pivot -= lower[i] * c[i-1]
+		}
+		if pivot == 0 || math.IsNaN(pivot) {
+			return nil, 0, fmt.Errorf("zero pivot in row %d, matrix is singular or needs pivoting", i+1)
+		}
+		det *= pivot
+		if i < n-1 {
+			c[i] = upper[i] / pivot
+		}
+		d[i] = rhs[i]
+		if i > 0 {
+			d[i] -= lower[i] * d[i-1]
+		}
+		d[i] /= pivot
+	}
+
+	x := make([]float64, n)
+	x[n-1] = d[n-1]
+	for i := n - 2; i >= 0; i-- {
+		x[i] = d[i] - c[i]*x[i+1]
+	}
+	return x, det, nil
+}
+
+// thomasStable reports whether the Thomas algorithm can be used without
+// pivoting: the matrix is diagonally dominant by rows, or it is symmetric
+// and every elimination pivot is positive, which for a symmetric tridiagonal
+// matrix means it is positive definite.
+func thomasStable(lower, diag, upper []float64) bool {
+	n := len(diag)
+	dominant, symmetric := true, true
+	for i := 0; i < n; i++ {
+		off := 0.0
+		if i > 0 {
+			off += math.Abs(lower[i])
+			if lower[i] != upper[i-1] {
+				symmetric = false
+			}
+		}
+		if i < n-1 {
+			off += math.Abs(upper[i])
+		}
+		if diag[i] == 0 || math.Abs(diag[i]) < off {
+			dominant = false
+		}
+	}
+	return dominant || symmetric && positiveDefinite(lower, diag, upper)
+}
+
+// positiveDefinite reports whether the symmetric tridiagonal matrix with the
+// given diagonals is positive definite, i.e. all pivots of its LDLᵀ
+// factorization are positive.
+func positiveDefinite(lower, diag, upper []float64) bool {
+	pivot := 0.0
+	for i := range diag {
+		if i == 0 {
+			pivot = diag[0]
+		} else {
+			pivot = diag[i] - lower[i]*upper[i-1]/pivot
+		}
+		if !(pivot > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// solveTridiagonalPivoting solves a tridiagonal system by Gaussian
+// elimination with partial pivoting in O(n), for matrices the Thomas
+// algorithm cannot handle safely. Row swaps create one extra diagonal above
+// the upper one; the diagonals are given as in SolveTridiagonal.
+func solveTridiagonalPivoting(lower, diag, upper, rhs []float64) ([]float64, float64, error) {
+	n := len(diag)
+	if n == 0 || len(lower) != n || len(upper) != n || len(rhs) != n {
+		return nil, 0, fmt.Errorf("invalid matrix or vector dimensions")
+	}
+
+	norm := 0.0
+	for i := 0; i < n; i++ {
+		norm = math.Max(norm, math.Abs(lower[i])+math.Abs(diag[i])+math.Abs(upper[i]))
+	}
+	tol := singularEps * norm
+
+	// Row i of the eliminated system holds d[i], u1[i], u2[i] in columns
+	// i, i+1, i+2; sub[i] is the entry below d[i] still to be eliminated.
+	d := append([]float64(nil), diag...)
+	u1 := append([]float64(nil), upper...)
+	u2 := make([]float64, n)
+	sub := make([]float64, n)
+	b := append([]float64(nil), rhs...)
+	for i := 0; i < n-1; i++ {
+		sub[i] = lower[i+1]
+	}
+	u1[n-1] = 0
+
+	det := 1.0
+	for i := 0; i < n-1; i++ {
+		if math.Abs(sub[i]) > math.Abs(d[i]) {
+			// Swap rows i and i+1; the new row i+1 starts in column i+1
+			d[i], sub[i] = sub[i], d[i]
+			u1[i], d[i+1] = d[i+1], u1[i]
+			if i+1 < n-1 {
+				u2[i], u1[i+1] = u1[i+1], 0
+			}
+			b[i], b[i+1] = b[i+1], b[i]
+			det = -det
+		}
+		if math.Abs(d[i]) <= tol {
+			return nil, 0, ErrSingular
+		}
+		factor := sub[i] / d[i]
+		d[i+1] -= factor * u1[i]
+		if i+1 < n-1 {
+			u1[i+1] -= factor * u2[i]
+		}
+		b[i+1] -= factor * b[i]
+		det *= d[i]
+	}
+	if math.Abs(d[n-1]) <= tol {
+		return nil, 0, ErrSingular
+	}
+	det *= d[n-1]
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := b[i]
+		if i+1 < n {
+			sum -= u1[i] * x[i+1]
+		}
+		if i+2 < n {
+			sum -= u2[i] * x[i+2]
+		}
+		x[i] = sum / d[i]
+	}
+	return x, det, nil
+}
+
+// denseDirectLimit is the largest sparse system a general direct method will
+// expand to dense storage.
+const denseDirectLimit = 2000
+
+// SolveSparseContext is SolveSparse with cancellation, as in SolveContext.
+func SolveSparseContext(ctx context.Context, A *CSR, b []float64, precision float64, opts Options) (*Result, error) {
+	opts.ctx = ctx
+	return SolveSparse(A, b, precision, opts)
+}
+
+// SolveSparse solves Ax = b for a sparse A. Tridiagonal systems solved with
+// a direct method take an O(n) path: the Thomas algorithm when the matrix is
+// diagonally dominant or positive definite, elimination with partial
+// pivoting otherwise. Jacobi, Gauss-Seidel and SOR reorder the CSR rows as
+// AnalyzeConvergence does for dense matrices.
+func SolveSparse(A *CSR, b []float64, precision float64, opts Options) (*Result, error) {
+	n := A.N
+	if n == 0 || len(b) != n || len(A.RowPtr) != n+1 {
+		return nil, fmt.Errorf("invalid matrix or vector dimensions")
+	}
+
+	if opts.Backend != Float64 {
+		if n > denseDirectLimit {
+			return nil, fmt.Errorf("the %s backend needs dense storage, which is limited to %d unknowns", opts.Backend, denseDirectLimit)
+		}
+		return solveBig(A.Dense(), b, precision, opts)
+	}
+	if opts.Method.IsDirect() {
+		if lower, diag, upper, ok := A.Tridiagonal(); ok && tridiagonalPath(opts.Method, lower, diag, upper) {
+			var x []float64
+			var det float64
+			var err error
+			if thomasStable(lower, diag, upper) {
+				x, det, err = SolveTridiagonal(lower, diag, upper, b)
+			} else {
+				x, det, err = solveTridiagonalPivoting(lower, diag, upper, b)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &Result{
+				Solution:    x,
+				Errors:      make([]float64, n),
+				MatrixNorm:  sparseNorm(A),
+				Method:      opts.Method,
+				Determinant: det,
+				Residuals:   residual(A, x, b),
+			}, nil
+		}
+		if n > denseDirectLimit {
+			return nil, fmt.Errorf("%s needs dense storage, which is limited to %d unknowns; use an iterative method", opts.Method, denseDirectLimit)
+		}
+		return solveDirect(opts.ctx, A.Dense(), b, opts.Method)
+	}
+	if opts.Method.IsKrylov() {
+		return solveKrylov(A, b, precision, opts)
+	}
+
+	if opts.Method == SOR && opts.Omega != 0 && (opts.Omega <= 0 || opts.Omega >= 2) {
+		return nil, fmt.Errorf("relaxation factor must be in (0, 2), got %g", opts.Omega)
+	}
+
+	conv, omega, err := analyzeConvergence(A, func(perm []int) rows { return A.permuteRows(perm) }, opts)
+	if err != nil {
+		return nil, err
+	}
+	orderedB := make([]float64, n)
+	for i, r := range conv.Permutation {
+		orderedB[i] = b[r]
+	}
+
+	res, err := iterate(A.permuteRows(conv.Permutation), orderedB, precision, opts, conv, omega)
+	if err != nil {
+		return nil, err
+	}
+	res.Residuals = residual(A, res.Solution, b)
+	return res, nil
+}
+
+// tridiagonalPath reports whether a tridiagonal system is solved by the O(n)
+// path. Cholesky takes it only for positive definite matrices, so that other
+// input gets the error of the dense factorization.
+func tridiagonalPath(method Method, lower, diag, upper []float64) bool {
+	if method != CholeskyDecomposition {
+		return true
+	}
+	for i := 1; i < len(diag); i++ {
+		if lower[i] != upper[i-1] {
+			return false
+		}
+	}
+	return positiveDefinite(lower, diag, upper)
+}
+
+// permuteRows returns m with its rows taken in the order perm.
+func (m *CSR) permuteRows(perm []int) *CSR {
+	p := &CSR{N: m.N, RowPtr: make([]int, m.N+1)}
+	for i, r := range perm {
+		p.ColIdx = append(p.ColIdx, m.ColIdx[m.RowPtr[r]:m.RowPtr[r+1]]...)
+		p.Vals = append(p.Vals, m.Vals[m.RowPtr[r]:m.RowPtr[r+1]]...)
+		p.RowPtr[i+1] = len(p.Vals)
+	}
+	return p
+}
+
+// sparseNorm returns ‖A‖∞.
+func sparseNorm(A *CSR) float64 {
+	norm := 0.0
+	for i := 0; i < A.N; i++ {
+		sum := 0.0
+		for k := A.RowPtr[i]; k < A.RowPtr[i+1]; k++ {
+			sum += math.Abs(A.Vals[k])
+		}
+		norm = math.Max(norm, sum)
+	}
+	return norm
+}
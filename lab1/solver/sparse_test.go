@@ -0,0 +1,161 @@
+package solver
+
+import (
+	"math"
+	"testing"
+)
+
+// tridiagonal builds the n×n matrix with the given diagonals as CSR and the
+// right-hand side for the solution xᵢ = i+1.
+func tridiagonal(lower, diag, upper []float64) (*CSR, []float64) {
+	n := len(diag)
+	coo := NewCOO(n)
+	for i := 0; i < n; i++ {
+		if i > 0 && lower[i] != 0 {
+			coo.Add(i, i-1, lower[i])
+		}
+		if diag[i] != 0 {
+			coo.Add(i, i, diag[i])
+		}
+		if i < n-1 && upper[i] != 0 {
+			coo.Add(i, i+1, upper[i])
+		}
+	}
+	A, err := coo.ToCSR()
+	if err != nil {
+		panic(err)
+	}
+	b := make([]float64, n)
+	A.MulVec(b, exactSolution(n))
+	return A, b
+}
+
+func exactSolution(n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = float64(i + 1)
+	}
+	return x
+}
+
+// diagonals returns n-element diagonals filled with l, d, u.
+func diagonals(n int, l, d, u float64) (lower, diag, upper []float64) {
+	lower, diag, upper = make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		lower[i], diag[i], upper[i] = l, d, u
+	}
+	return lower, diag, upper
+}
+
+func maxDiff(a, b []float64) float64 {
+	d := 0.0
+	for i := range a {
+		d = math.Max(d, math.Abs(a[i]-b[i]))
+	}
+	return d
+}
+
+func TestSolveSparseTridiagonal(t *testing.T) {
+	const n = 201
+
+	// The first block [[0 1] [1 0]] needs a row swap: Thomas would stop on
+	// the zero pivot in row 1
+	swapLower, swapDiag, swapUpper := diagonals(n, 1, 4, 1)
+	swapDiag[0], swapDiag[1] = 0, 0
+	swapUpper[1], swapLower[2] = 0, 0
+
+	spdLower, spdDiag, spdUpper := diagonals(n, -1, 2, -1)
+	nonsymLower, nonsymDiag, nonsymUpper := diagonals(n, 1, 4, 2)
+	singLower, singDiag, singUpper := diagonals(n, 1, 2, 1)
+	singDiag[n-1], singLower[n-1] = 0, 0
+	singUpper[n-2] = 0
+
+	tests := []struct {
+		name               string
+		lower, diag, upper []float64
+		method             Method
+		wantErr            bool
+	}{
+		{"dominant gauss", nonsymLower, nonsymDiag, nonsymUpper, Gauss, false},
+		{"zero diagonal gauss", swapLower, swapDiag, swapUpper, Gauss, false},
+		{"zero diagonal lu", swapLower, swapDiag, swapUpper, LUDecomposition, false},
+		{"spd cholesky", spdLower, spdDiag, spdUpper, CholeskyDecomposition, false},
+		{"nonsymmetric cholesky", nonsymLower, nonsymDiag, nonsymUpper, CholeskyDecomposition, true},
+		{"zero diagonal cholesky", swapLower, swapDiag, swapUpper, CholeskyDecomposition, true},
+		{"singular gauss", singLower, singDiag, singUpper, Gauss, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			A, b := tridiagonal(tt.lower, tt.diag, tt.upper)
+			res, err := SolveSparse(A, b, 1e-10, Options{Method: tt.method})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got solution %v", res.Solution[:3])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := maxDiff(res.Solution, exactSolution(n)); d > 1e-8 {
+				t.Errorf("solution differs from the exact one by %g", d)
+			}
+		})
+	}
+}
+
+func TestSolveTridiagonalPivotingDeterminant(t *testing.T) {
+	// [[0 1 0] [1 0 1] [0 1 2]]: det = -2
+	x, det, err := solveTridiagonalPivoting(
+		[]float64{0, 1, 1}, []float64{0, 0, 2}, []float64{1, 1, 0}, []float64{2, 4, 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(det+2) > 1e-12 {
+		t.Errorf("det = %g, want -2", det)
+	}
+	if d := maxDiff(x, []float64{1, 2, 3}); d > 1e-12 {
+		t.Errorf("x = %v, want [1 2 3]", x)
+	}
+}
+
+func TestSolveSparseReordersRows(t *testing.T) {
+	// A diagonally dominant system with its first two rows swapped: it only
+	// converges after the rows are put back
+	for _, n := range []int{3, 201} {
+		lower, diag, upper := diagonals(n, 1, 5, 1)
+		dense := make([][]float64, n)
+		for i := range dense {
+			dense[i] = make([]float64, n)
+			dense[i][i] = diag[i]
+			if i > 0 {
+				dense[i][i-1] = lower[i]
+			}
+			if i < n-1 {
+				dense[i][i+1] = upper[i]
+			}
+		}
+		b := make([]float64, n)
+		DenseToCSR(dense).MulVec(b, exactSolution(n))
+		dense[0], dense[1] = dense[1], dense[0]
+		b[0], b[1] = b[1], b[0]
+
+		for _, method := range []Method{Jacobi, GaussSeidel, SOR} {
+			sparse, err := SolveSparse(DenseToCSR(dense), b, 1e-10, Options{Method: method})
+			if err != nil {
+				t.Fatalf("n = %d, %s: %v", n, method, err)
+			}
+			if d := maxDiff(sparse.Solution, exactSolution(n)); d > 1e-8 {
+				t.Errorf("n = %d, %s: solution differs from the exact one by %g", n, method, d)
+			}
+			full, err := Solve(dense, b, 1e-10, Options{Method: method})
+			if err != nil {
+				t.Fatalf("n = %d, %s dense: %v", n, method, err)
+			}
+			if full.Iterations != sparse.Iterations || maxDiff(full.Solution, sparse.Solution) > 1e-12 {
+				t.Errorf("n = %d, %s: sparse and dense solves differ (%d vs %d iterations)",
+					n, method, sparse.Iterations, full.Iterations)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// StopCriterion selects the quantity an iterative method compares with
+// the precision.
+type StopCriterion int
+
+const (
+	// StopAbsolute stops once ‖xₖ - xₖ₋₁‖∞ < ε.
+	StopAbsolute StopCriterion = iota
+	// StopRelative stops once ‖xₖ - xₖ₋₁‖∞ < ε‖xₖ‖∞.
+	StopRelative
+	// StopResidual stops once ‖b - Axₖ‖∞ < ε.
+	StopResidual
+)
+
+func (c StopCriterion) String() string {
+	switch c {
+	case StopAbsolute:
+		return "absolute"
+	case StopRelative:
+		return "relative"
+	case StopResidual:
+		return "residual"
+	default:
+		return fmt.Sprintf("StopCriterion(%d)", int(c))
+	}
+}
+
+// ParseStopCriterion maps "abs", "rel" or "residual" (or the full names) to
+// a StopCriterion.
+func ParseStopCriterion(name string) (StopCriterion, error) {
+	switch strings.ToLower(name) {
+	case "abs", "absolute":
+		return StopAbsolute, nil
+	case "rel", "relative":
+		return StopRelative, nil
+	case "res", "residual":
+		return StopResidual, nil
+	}
+	return 0, fmt.Errorf("unknown stopping criterion %q (want abs, rel or residual)", name)
+}
+
+// defaultMaxIterations is used when Options.MaxIterations is zero.
+const defaultMaxIterations = 10000
+
+// IterationLimitError is returned when an iterative method reaches its
+// iteration limit without meeting the stopping criterion.
+type IterationLimitError struct {
+	Method     Method
+	Iterations int
+	// Difference is ‖xₖ - xₖ₋₁‖∞ of the last iterate.
+	Difference float64
+}
+
+func (e *IterationLimitError) Error() string {
+	return fmt.Sprintf("solution did not converge within %d iterations (last ‖xₖ - xₖ₋₁‖∞ = %.3e)", e.Iterations, e.Difference)
+}
+
+// DivergenceError is returned when the iterates become non-finite or their
+// differences grow for Options.DivergenceSteps consecutive iterations.
+type DivergenceError struct {
+	Method    Method
+	Iteration int
+	// NonFinite is set when an iterate contains Inf or NaN.
+	NonFinite bool
+	// Growth is the number of consecutive steps the difference grew.
+	Growth     int
+	Difference float64
+}
+
+func (e *DivergenceError) Error() string {
+	if e.NonFinite {
+		return fmt.Sprintf("%s diverged: non-finite values at iteration %d", e.Method, e.Iteration)
+	}
+	return fmt.Sprintf("%s diverged: ‖xₖ - xₖ₋₁‖∞ grew for %d consecutive iterations, reaching %.3e at iteration %d",
+		e.Method, e.Growth, e.Difference, e.Iteration)
+}
+
+// stopper applies the stopping criterion, the iteration limit and the
+// divergence checks of Options to the iterates of one solve.
+type stopper struct {
+	opts      Options
+	precision float64
+	maxIter   int
+	growth    int
+	prevDiff  float64
+}
+
+func newStopper(opts Options, precision float64) *stopper {
+	maxIter := opts.MaxIterations
+	if maxIter <= 0 {
+		maxIter = defaultMaxIterations
+	}
+	return &stopper{opts: opts, precision: precision, maxIter: maxIter}
+}
+
+// check reports whether iteration k has converged, given ‖xₖ - xₖ₋₁‖∞,
+// ‖xₖ‖∞ and a function computing ‖b - Axₖ‖∞, which is only called for
+// residual-based stopping. It fails on divergence, cancellation or once
+// the iteration limit is reached.
+func (s *stopper) check(k int, diff, xNorm float64, residual func() float64) (bool, error) {
+	if math.IsNaN(diff) || math.IsInf(diff, 0) || math.IsNaN(xNorm) || math.IsInf(xNorm, 0) {
+		return false, &DivergenceError{Method: s.opts.Method, Iteration: k, NonFinite: true, Difference: diff}
+	}
+	if err := s.opts.checkpoint(k, diff); err != nil {
+		return false, err
+	}
+
+	var converged bool
+	switch s.opts.Stop {
+	case StopRelative:
+		converged = diff < s.precision*xNorm || diff == 0
+	case StopResidual:
+		converged = residual() < s.precision
+	default:
+		converged = diff < s.precision
+	}
+	if converged {
+		return true, nil
+	}
+
+	if k > 1 && diff > s.prevDiff {
+		s.growth++
+	} else {
+		s.growth = 0
+	}
+	s.prevDiff = diff
+	if s.opts.DivergenceSteps > 0 && s.growth >= s.opts.DivergenceSteps {
+		return false, &DivergenceError{Method: s.opts.Method, Iteration: k, Growth: s.growth, Difference: diff}
+	}
+	if k >= s.maxIter {
+		return false, &IterationLimitError{Method: s.opts.Method, Iterations: k, Difference: diff}
+	}
+	return false, nil
+}
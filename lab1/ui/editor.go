@@ -0,0 +1,427 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"calcMat/format"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The editor shows a window of editorRows × editorCols cells around the
+// cursor; larger systems scroll.
+const (
+	editorRows  = 12
+	editorCols  = 6
+	editorWidth = 10
+)
+
+// editor is a spreadsheet-like editor for the augmented matrix [A | b].
+type editor struct {
+	// cells holds one row per equation; the last entry of each row is bᵢ.
+	cells    [][]float64
+	unknowns int
+	row, col int
+	// editing is set while buffer holds a new value for the current cell.
+	editing bool
+	buffer  string
+	// pasting is set while paste collects the lines of a pasted matrix.
+	pasting  bool
+	paste    []string
+	errorMsg string
+}
+
+func newEditor(A [][]float64, b []float64) editor {
+	cells := make([][]float64, len(A))
+	for i := range A {
+		cells[i] = append(append([]float64(nil), A[i]...), b[i])
+	}
+	return editor{cells: cells, unknowns: len(A)}
+}
+
+func emptyEditor(n int) editor {
+	A := make([][]float64, n)
+	for i := range A {
+		A[i] = make([]float64, n)
+	}
+	return newEditor(A, make([]float64, n))
+}
+
+// system returns A and b, failing while the matrix is not square.
+func (e editor) system() ([][]float64, []float64, error) {
+	if len(e.cells) != e.unknowns {
+		return nil, nil, fmt.Errorf("the system has %d equations and %d unknowns; insert or delete rows or columns to make A square", len(e.cells), e.unknowns)
+	}
+	if len(e.cells) == 0 {
+		return nil, nil, fmt.Errorf("the system is empty")
+	}
+	A := make([][]float64, len(e.cells))
+	b := make([]float64, len(e.cells))
+	for i, row := range e.cells {
+		A[i] = append([]float64(nil), row[:e.unknowns]...)
+		b[i] = row[e.unknowns]
+	}
+	return A, b, nil
+}
+
+// dominance classifies row i: 1 if strictly diagonally dominant, 0 if
+// |aᵢᵢ| equals the sum of the other entries, -1 otherwise.
+func (e editor) dominance(i int) int {
+	if i >= e.unknowns {
+		return -1
+	}
+	off := 0.0
+	for j := 0; j < e.unknowns; j++ {
+		if j != i {
+			off += math.Abs(e.cells[i][j])
+		}
+	}
+	diag := math.Abs(e.cells[i][i])
+	switch {
+	case diag > off:
+		return 1
+	case diag == off:
+		return 0
+	default:
+		return -1
+	}
+}
+
+func (e editor) update(msg tea.KeyMsg) editor {
+	if e.pasting {
+		return e.updatePaste(msg)
+	}
+	if e.editing {
+		switch msg.Type {
+		case tea.KeyEnter, tea.KeyTab:
+			if !e.commit() {
+				return e
+			}
+			e.move(0, 1)
+			return e
+		case tea.KeyUp, tea.KeyDown, tea.KeyLeft, tea.KeyRight:
+			if !e.commit() {
+				return e
+			}
+		case tea.KeyEsc:
+			e.editing = false
+			e.buffer = ""
+			e.errorMsg = ""
+			return e
+		case tea.KeyBackspace:
+			if len(e.buffer) > 0 {
+				e.buffer = e.buffer[:len(e.buffer)-1]
+			}
+			return e
+		default:
+			if s := msg.String(); len(s) == 1 && strings.Contains("0123456789.-+eE", s) {
+				e.buffer += s
+			}
+			return e
+		}
+	}
+
+	switch msg.String() {
+	case "up":
+		e.move(-1, 0)
+	case "down":
+		e.move(1, 0)
+	case "left", "shift+tab":
+		e.move(0, -1)
+	case "right", "tab":
+		e.move(0, 1)
+	case "home":
+		e.col = 0
+	case "end":
+		e.col = e.unknowns
+	case "r":
+		e.insertRow()
+	case "c":
+		e.insertColumn()
+	case "R":
+		e.deleteRow()
+	case "C":
+		e.deleteColumn()
+	case "p":
+		e.pasting = true
+		e.paste = nil
+		e.errorMsg = ""
+	case "backspace", "delete":
+		if e.row < len(e.cells) {
+			e.cells[e.row][e.col] = 0
+		}
+	default:
+		if s := msg.String(); len(s) == 1 && strings.Contains("0123456789.-+", s) && e.row < len(e.cells) {
+			e.editing = true
+			e.buffer = s
+			e.errorMsg = ""
+		}
+	}
+	return e
+}
+
+// commit stores the edited value, keeping the edit open if it is invalid.
+func (e *editor) commit() bool {
+	val, err := strconv.ParseFloat(strings.TrimSpace(e.buffer), 64)
+	if err != nil {
+		e.errorMsg = fmt.Sprintf("Invalid number: %s", e.buffer)
+		return false
+	}
+	e.cells[e.row][e.col] = val
+	e.editing = false
+	e.buffer = ""
+	e.errorMsg = ""
+	return true
+}
+
+func (e *editor) move(dRow, dCol int) {
+	e.row = clamp(e.row+dRow, 0, len(e.cells)-1)
+	e.col = clamp(e.col+dCol, 0, e.unknowns)
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+// insertRow adds an empty equation below the cursor.
+func (e *editor) insertRow() {
+	at := e.row + 1
+	e.cells = append(e.cells, nil)
+	copy(e.cells[at+1:], e.cells[at:])
+	e.cells[at] = make([]float64, e.unknowns+1)
+	e.row = at
+}
+
+// insertColumn adds an unknown to the right of the cursor, or before b
+// when the cursor is on the right-hand side.
+func (e *editor) insertColumn() {
+	at := e.col + 1
+	if at > e.unknowns {
+		at = e.unknowns
+	}
+	for i, row := range e.cells {
+		row = append(row, 0)
+		copy(row[at+1:], row[at:])
+		row[at] = 0
+		e.cells[i] = row
+	}
+	e.unknowns++
+	e.col = at
+}
+
+func (e *editor) deleteRow() {
+	if len(e.cells) <= 1 {
+		return
+	}
+	e.cells = append(e.cells[:e.row], e.cells[e.row+1:]...)
+	e.move(0, 0)
+}
+
+// deleteColumn removes the unknown under the cursor; b cannot be deleted.
+func (e *editor) deleteColumn() {
+	if e.col >= e.unknowns || e.unknowns <= 1 {
+		return
+	}
+	for i, row := range e.cells {
+		e.cells[i] = append(row[:e.col], row[e.col+1:]...)
+	}
+	e.unknowns--
+	e.move(0, 0)
+}
+
+// updatePaste collects pasted lines until an empty line or Ctrl+D.
+func (e editor) updatePaste(msg tea.KeyMsg) editor {
+	if len(e.paste) == 0 {
+		e.paste = []string{""}
+	}
+	last := len(e.paste) - 1
+	switch msg.Type {
+	case tea.KeyEsc:
+		e.pasting = false
+		e.paste = nil
+	case tea.KeyCtrlD:
+		e.applyPaste()
+	case tea.KeyEnter:
+		if strings.TrimSpace(e.paste[last]) == "" && last > 0 {
+			e.applyPaste()
+			return e
+		}
+		e.paste = append(e.paste, "")
+	case tea.KeyBackspace:
+		if s := e.paste[last]; len(s) > 0 {
+			e.paste[last] = s[:len(s)-1]
+		}
+	case tea.KeySpace:
+		e.paste[last] += " "
+	case tea.KeyTab:
+		e.paste[last] += "\t"
+	case tea.KeyRunes:
+		e.paste[last] += string(msg.Runes)
+	}
+	return e
+}
+
+// applyPaste replaces the matrix with the pasted one. The text may be in
+// the lab1 file layout or just the rows of [A | b], with numbers separated
+// by spaces, tabs, commas or semicolons.
+func (e *editor) applyPaste() {
+	text := strings.Join(e.paste, "\n")
+	e.pasting = false
+	e.paste = nil
+
+	if data, err := format.ParseText(text); err == nil && data.Matrix != nil {
+		*e = newEditor(data.Matrix, data.Vector)
+		return
+	}
+
+	var cells [][]float64
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == ';'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		row := make([]float64, len(fields))
+		for j, f := range fields {
+			val, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				e.errorMsg = fmt.Sprintf("Line %d: invalid number %q", i+1, f)
+				return
+			}
+			row[j] = val
+		}
+		if len(cells) > 0 && len(row) != len(cells[0]) {
+			e.errorMsg = fmt.Sprintf("Line %d has %d numbers, expected %d", i+1, len(row), len(cells[0]))
+			return
+		}
+		cells = append(cells, row)
+	}
+	if len(cells) == 0 || len(cells[0]) < 2 {
+		e.errorMsg = "Paste at least one row of [A | b]"
+		return
+	}
+	*e = editor{cells: cells, unknowns: len(cells[0]) - 1}
+}
+
+// window returns the first index of a scrolling window of size size over
+// count items that keeps cursor visible.
+func window(cursor, count, size int) int {
+	start := cursor - size/2
+	if start > count-size {
+		start = count - size
+	}
+	if start < 0 {
+		start = 0
+	}
+	return start
+}
+
+func (e editor) view(blink bool) string {
+	var s strings.Builder
+	s.WriteString("╭──────────────────────────────────────────╮\n")
+	s.WriteString("│              Matrix Editor               │\n")
+	s.WriteString("╰──────────────────────────────────────────╯\n\n")
+
+	if e.pasting {
+		s.WriteString("Paste the rows of [A | b] (or a whole lab1 file).\n")
+		s.WriteString("Finish with an empty line or Ctrl+D, Esc to cancel.\n\n")
+		for i, line := range e.paste {
+			s.WriteString(line)
+			if i == len(e.paste)-1 && blink {
+				s.WriteString("█")
+			}
+			s.WriteString("\n")
+		}
+		if len(e.paste) == 0 && blink {
+			s.WriteString("█\n")
+		}
+		return s.String()
+	}
+
+	s.WriteString(fmt.Sprintf("%d equations, %d unknowns\n\n", len(e.cells), e.unknowns))
+
+	rowStart := window(e.row, len(e.cells), editorRows)
+	colStart := window(e.col, e.unknowns+1, editorCols)
+	rowEnd := rowStart + editorRows
+	if rowEnd > len(e.cells) {
+		rowEnd = len(e.cells)
+	}
+	colEnd := colStart + editorCols
+	if colEnd > e.unknowns+1 {
+		colEnd = e.unknowns + 1
+	}
+
+	s.WriteString(strings.Repeat(" ", 5))
+	for j := colStart; j < colEnd; j++ {
+		if j == e.unknowns {
+			s.WriteString(" │")
+			s.WriteString(fmt.Sprintf(" %*s ", editorWidth, "b"))
+		} else {
+			s.WriteString(fmt.Sprintf(" %*s ", editorWidth, fmt.Sprintf("x%d", j+1)))
+		}
+	}
+	s.WriteString("  dom\n")
+
+	for i := rowStart; i < rowEnd; i++ {
+		s.WriteString(fmt.Sprintf("%4d ", i+1))
+		for j := colStart; j < colEnd; j++ {
+			if j == e.unknowns {
+				s.WriteString(" │")
+			}
+			cell := strconv.FormatFloat(e.cells[i][j], 'g', 6, 64)
+			if i == e.row && j == e.col {
+				if e.editing {
+					cell = e.buffer
+					if blink {
+						cell += "█"
+					}
+				}
+				s.WriteString(fmt.Sprintf("[%*s]", editorWidth, cell))
+			} else {
+				s.WriteString(fmt.Sprintf(" %*s ", editorWidth, cell))
+			}
+		}
+		switch e.dominance(i) {
+		case 1:
+			s.WriteString("  ✓")
+		case 0:
+			s.WriteString("  =")
+		default:
+			s.WriteString("  ✗")
+		}
+		s.WriteString("\n")
+	}
+	if rowStart > 0 || rowEnd < len(e.cells) || colStart > 0 || colEnd < e.unknowns+1 {
+		s.WriteString(fmt.Sprintf("(rows %d-%d, columns %d-%d shown)\n", rowStart+1, rowEnd, colStart+1, colEnd))
+	}
+
+	dominant := len(e.cells) == e.unknowns
+	for i := range e.cells {
+		if e.dominance(i) != 1 {
+			dominant = false
+		}
+	}
+	if dominant {
+		s.WriteString("\nThe matrix is strictly diagonally dominant.\n")
+	} else {
+		s.WriteString("\n✓ strictly dominant row, = equal, ✗ not dominant.\n")
+		s.WriteString("The solver may still reorder the rows to reach dominance.\n")
+	}
+
+	if e.errorMsg != "" {
+		s.WriteString("\nError: " + e.errorMsg + "\n")
+	}
+	s.WriteString("\nArrows/Tab move, type a number to edit, Enter to confirm\n")
+	s.WriteString("r/c insert row/column, R/C delete, p paste, Del clears a cell\n")
+	s.WriteString("Press 's' to continue, Esc to go back")
+	return s.String()
+}
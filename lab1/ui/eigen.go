@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"calcMat/solver"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m model) handleEigenMethodInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "1":
+		m.eigenMethod = solver.PowerIteration
+		m.mode = modeEigen
+		m.state = stateSource
+	case "2":
+		m.eigenMethod = solver.InverseIteration
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = stateShift
+	case "3":
+		m.eigenMethod = solver.QRAlgorithm
+		m.mode = modeEigen
+		m.state = stateSource
+	case "esc":
+		m.state = stateMenu
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) handleShiftInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		val, err := strconv.ParseFloat(strings.TrimSpace(m.inputBuffer), 64)
+		if err != nil {
+			m.errorMsg = "Please enter a valid number"
+			m.inputBuffer = ""
+			return m, nil
+		}
+		m.shift = val
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.mode = modeEigen
+		m.state = stateSource
+
+	case tea.KeyBackspace:
+		if len(m.inputBuffer) > 0 {
+			m.inputBuffer = m.inputBuffer[:len(m.inputBuffer)-1]
+		}
+	case tea.KeyEsc:
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = stateEigenMethod
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	default:
+		if len(msg.String()) == 1 && (strings.Contains("0123456789.-", msg.String()) || msg.String() == "e") {
+			m.inputBuffer += msg.String()
+		}
+	}
+	return m, nil
+}
+
+func (m model) handleEigenResultInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "t":
+		if m.err == nil && len(m.eigen.History) > 0 {
+			m.traceView = !m.traceView
+		}
+	case "right", "n":
+		if m.traceView && (m.tracePage+1)*traceRows < len(m.eigen.History) {
+			m.tracePage++
+		}
+	case "left", "p":
+		if m.traceView && m.tracePage > 0 {
+			m.tracePage--
+		}
+	case "esc", "enter":
+		m.err = nil
+		m.eigen = nil
+		m.traceView = false
+		m.tracePage = 0
+		m.mode = modeSolve
+		m.state = stateMenu
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) writeEigen(s *strings.Builder) {
+	s.WriteString("╭──────────────────────────────────────────╮\n")
+	s.WriteString("│       Eigenvalues and Eigenvectors       │\n")
+	s.WriteString("╰──────────────────────────────────────────╯\n\n")
+	if m.err != nil {
+		s.WriteString("Error: " + m.err.Error() + "\n")
+		return
+	}
+
+	e := m.eigen
+	s.WriteString(fmt.Sprintf("Method: %s", e.Method))
+	if e.Method == solver.InverseIteration {
+		s.WriteString(fmt.Sprintf(" (σ = %g)", m.shift))
+	}
+	s.WriteString(fmt.Sprintf(", %d iterations\n\n", e.Iterations))
+
+	for i, val := range e.Values {
+		if i == maxShownRows {
+			s.WriteString(fmt.Sprintf("… %d more\n", len(e.Values)-maxShownRows))
+			break
+		}
+		if imag(val) == 0 {
+			s.WriteString(fmt.Sprintf("λ%d = %14.8g\n", i+1, real(val)))
+		} else {
+			s.WriteString(fmt.Sprintf("λ%d = %14.8g %+.8gi\n", i+1, real(val), imag(val)))
+		}
+	}
+
+	if n := m.dimension; n <= 8 {
+		s.WriteString("\nEigenvectors (columns, ‖v‖₂ = 1):\n")
+		for i := 0; i < n; i++ {
+			for k, v := range e.Vectors {
+				switch {
+				case v == nil && imag(e.Values[k]) != 0:
+					s.WriteString(fmt.Sprintf(" %11s", "complex"))
+				case v == nil:
+					// inverse iteration did not converge for this λ
+					s.WriteString(fmt.Sprintf(" %11s", "n/a"))
+				default:
+					s.WriteString(fmt.Sprintf(" %11.5g", v[i]))
+				}
+			}
+			s.WriteString("\n")
+		}
+	} else {
+		s.WriteString(fmt.Sprintf("\nEigenvectors have %d components and are too large to display.\n", n))
+	}
+
+	if e.Method == solver.QRAlgorithm {
+		s.WriteString(fmt.Sprintf("\nSpectral radius ρ(A) = %.6g\n", e.SpectralRadius()))
+	}
+	if m.jacobiRadius >= 0 {
+		s.WriteString(fmt.Sprintf("ρ(C) of the Jacobi iteration matrix = %.6g\n", m.jacobiRadius))
+		switch {
+		case m.jacobiRadius == 0:
+			s.WriteString("Simple iteration is exact after at most n steps.\n")
+		case m.jacobiRadius < 1:
+			s.WriteString(fmt.Sprintf("Simple iteration converges, gaining about %.2f digits per step.\n", -math.Log10(m.jacobiRadius)))
+		default:
+			s.WriteString("Simple iteration diverges for this matrix.\n")
+		}
+	}
+}
+
+func (m model) writeEigenHistory(s *strings.Builder) {
+	history := m.eigen.History
+	pages := (len(history) + traceRows - 1) / traceRows
+	s.WriteString("╭──────────────────────────────────────────╮\n")
+	s.WriteString("│            Iteration History             │\n")
+	s.WriteString("╰──────────────────────────────────────────╯\n\n")
+	s.WriteString(fmt.Sprintf("Page %d of %d\n\n", m.tracePage+1, pages))
+
+	diff := "|λₖ-λₖ₋₁|"
+	if m.eigen.Method == solver.QRAlgorithm {
+		diff = "|hₙ,ₙ₋₁|"
+	}
+	s.WriteString(fmt.Sprintf("%5s %18s %13s\n", "k", "λₖ", diff))
+
+	start := m.tracePage * traceRows
+	end := start + traceRows
+	if end > len(history) {
+		end = len(history)
+	}
+	for _, step := range history[start:end] {
+		s.WriteString(fmt.Sprintf("%5d %18.10g %13.6e\n", step.Iteration, step.Value, step.Difference))
+	}
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"calcMat/solver"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sparklineWidth is the number of recent iterations the progress sparkline
+// shows; progressInterval throttles how often the solver reports to the UI.
+const (
+	sparklineWidth   = 48
+	progressInterval = 50 * time.Millisecond
+)
+
+// solveRun connects a solve running in the background to the UI: the
+// solver goroutine sends progressMsg updates and finally one solverMsg (or
+// one analysisMsg or eigenMsg for the tasks started by startTask).
+type solveRun struct {
+	updates chan tea.Msg
+	cancel  context.CancelFunc
+}
+
+type solveStartedMsg struct {
+	run *solveRun
+}
+
+type progressMsg struct {
+	iteration int
+	maxError  float64
+	// recent holds ‖xₖ - xₖ₋₁‖∞ for the last sparklineWidth iterations.
+	recent []float64
+}
+
+// startSolve runs solve in its own goroutine with a cancellable context and
+// a progress callback that reports to the UI at most every progressInterval.
+func startSolve(opts solver.Options, solve func(context.Context, solver.Options) (*solver.Result, error)) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		run := &solveRun{updates: make(chan tea.Msg, 1), cancel: cancel}
+
+		var recent []float64
+		var last time.Time
+		opts.Progress = func(iteration int, maxError float64) {
+			recent = append(recent, maxError)
+			if len(recent) > sparklineWidth {
+				recent = recent[1:]
+			}
+			if time.Since(last) < progressInterval {
+				return
+			}
+			last = time.Now()
+			// Drop the update rather than stall the solver when the UI has
+			// not consumed the previous one yet.
+			select {
+			case run.updates <- progressMsg{iteration, maxError, append([]float64(nil), recent...)}:
+			default:
+			}
+		}
+
+		go func() {
+			defer cancel()
+			result, err := solve(ctx, opts)
+			run.updates <- solverMsg{result: result, err: err}
+		}()
+		return solveStartedMsg{run: run}
+	}
+}
+
+// startTask runs work in its own goroutine with a cancellable context, like
+// startSolve but without progress updates, so that Esc on the processing
+// screen also stops a matrix analysis or an eigenvalue method.
+func startTask(work func(context.Context) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		run := &solveRun{updates: make(chan tea.Msg, 1), cancel: cancel}
+		go func() {
+			defer cancel()
+			run.updates <- work(ctx)
+		}()
+		return solveStartedMsg{run: run}
+	}
+}
+
+// waitForUpdate delivers the next message from a running solve.
+func waitForUpdate(run *solveRun) tea.Cmd {
+	return func() tea.Msg {
+		return <-run.updates
+	}
+}
+
+func (m model) handleProcessingInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.run != nil {
+			m.run.cancel()
+		}
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) writeProgress(s *strings.Builder) {
+	s.WriteString("Processing...\n")
+	if m.run == nil {
+		return
+	}
+	if m.progress.iteration > 0 {
+		s.WriteString(fmt.Sprintf("\nIteration %d, ‖xₖ - xₖ₋₁‖∞ = %.6e\n", m.progress.iteration, m.progress.maxError))
+		s.WriteString(sparkline(m.progress.recent) + "\n")
+	}
+	s.WriteString("\nPress Esc to cancel")
+}
+
+// sparkline draws values on a logarithmic scale, since the error of a
+// converging iteration falls geometrically.
+func sparkline(values []float64) string {
+	const bars = "▁▂▃▄▅▆▇█"
+	levels := []rune(bars)
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	logs := make([]float64, len(values))
+	for i, v := range values {
+		logs[i] = math.Log10(v)
+		if math.IsInf(logs[i], 0) || math.IsNaN(logs[i]) {
+			continue
+		}
+		lo = math.Min(lo, logs[i])
+		hi = math.Max(hi, logs[i])
+	}
+
+	var s strings.Builder
+	for _, l := range logs {
+		level := 0
+		switch {
+		case math.IsNaN(l) || math.IsInf(l, 1):
+			level = len(levels) - 1
+		case math.IsInf(l, -1) || hi == lo:
+			level = 0
+		default:
+			level = int((l - lo) / (hi - lo) * float64(len(levels)-1))
+		}
+		s.WriteRune(levels[level])
+	}
+	return s.String()
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"calcMat/format"
+	"calcMat/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recentSessions is how many saved sessions the menu lists, under the keys
+// recentKeys.
+const (
+	recentSessions = 5
+	recentKeys     = "abcde"
+)
+
+func (m model) loadRecent() model {
+	m.recent, _ = session.Recent(recentSessions)
+	return m
+}
+
+// startSave asks for a session name, returning to from afterwards.
+func (m model) startSave(from state) (tea.Model, tea.Cmd) {
+	if from == stateEditor {
+		A, b, err := m.editor.system()
+		if err != nil {
+			m.editor.errorMsg = err.Error()
+			return m, nil
+		}
+		m.dimension = len(A)
+		m.matrix = A
+		m.sparse = nil
+		m.vector = b
+	}
+	m.saveReturn = from
+	m.inputBuffer = m.sessionName
+	m.errorMsg = ""
+	m.notice = ""
+	m.state = stateSaveSession
+	return m, nil
+}
+
+func (m model) handleSaveSessionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.inputBuffer)
+		if name == "" {
+			m.errorMsg = "Please enter a session name"
+			return m, nil
+		}
+
+		s := &session.Session{
+			Name:      name,
+			Vector:    m.vector,
+			Precision: m.precision,
+			Settings:  session.NewSettings(m.method, m.omega, m.preconditioner, m.backend, m.stop),
+		}
+		if m.sparse != nil {
+			s.Sparse = m.sparse
+		} else {
+			s.Matrix = m.matrix
+		}
+		if m.saveReturn == stateResult && m.err == nil && m.result != nil {
+			s.Result = session.NewResult(m.result)
+		}
+		path, err := session.Save(s)
+		if err != nil {
+			m.errorMsg = fmt.Sprintf("Failed to save session: %v", err)
+			return m, nil
+		}
+
+		m.sessionName = name
+		m.notice = fmt.Sprintf("Session saved to %s", path)
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = m.saveReturn
+		return m.loadRecent(), nil
+
+	case tea.KeyBackspace:
+		if len(m.inputBuffer) > 0 {
+			m.inputBuffer = m.inputBuffer[:len(m.inputBuffer)-1]
+		}
+	case tea.KeyEsc:
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = m.saveReturn
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeySpace:
+		m.inputBuffer += " "
+	default:
+		if len(msg.String()) == 1 {
+			m.inputBuffer += msg.String()
+		}
+	}
+	return m, nil
+}
+
+// openSession restores a saved session, showing its result if it has one
+// and the system in the editor otherwise.
+func (m model) openSession(entry session.Entry) (tea.Model, tea.Cmd) {
+	s, err := session.Load(entry.Path)
+	if err != nil {
+		m.errorMsg = err.Error()
+		return m, nil
+	}
+	opts, err := s.Settings.Options()
+	if err != nil {
+		m.errorMsg = fmt.Sprintf("Invalid session %s: %v", entry.Name, err)
+		return m, nil
+	}
+
+	m.mode = modeSolve
+	m.sessionName = s.Name
+	m.matrix = s.Matrix
+	m.sparse = s.Sparse
+	m.vector = s.Vector
+	m.dimension = len(s.Vector)
+	m.precision = s.Precision
+	m.method = opts.Method
+	m.omega = opts.Omega
+	m.preconditioner = opts.Preconditioner
+	m.backend = opts.Backend
+	m.stop = opts.Stop
+	m.errorMsg = ""
+	m.notice = ""
+
+	if s.Result != nil {
+		if m.result, err = s.Result.Restore(); err == nil {
+			m.err = nil
+			m.traceView = false
+			m.tracePage = 0
+			m.state = stateResult
+			return m, nil
+		}
+	}
+	return m.openEditor()
+}
+
+// openEditor shows the current system in the matrix editor.
+func (m model) openEditor() (tea.Model, tea.Cmd) {
+	A := m.matrix
+	if m.sparse != nil {
+		if m.dimension > format.MaxDenseDimension {
+			m.errorMsg = fmt.Sprintf("The editor is limited to %d unknowns", format.MaxDenseDimension)
+			return m, nil
+		}
+		A = m.sparse.Dense()
+	}
+	m.editor = newEditor(A, m.vector)
+	m.state = stateEditor
+	return m, nil
+}
+
+func (m model) writeRecent(s *strings.Builder) {
+	if len(m.recent) == 0 {
+		return
+	}
+	s.WriteString("Recent sessions:\n")
+	for i, entry := range m.recent {
+		s.WriteString(fmt.Sprintf("%c - %s (%s, %s)\n", recentKeys[i], entry.Name,
+			entry.Saved.Format("2006-01-02 15:04"), filepath.Base(entry.Path)))
+	}
+	s.WriteString("\n")
+}
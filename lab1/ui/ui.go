@@ -1,12 +1,17 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"calcMat/format"
+	"calcMat/session"
 	"calcMat/solver"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -16,35 +21,90 @@ type state int
 const (
 	stateMenu state = iota
 	stateDimension
-	stateMatrixInput
+	stateEditor
 	stateFileInput
 	statePrecision
 	stateProcessing
 	stateResult
+	stateMethod
+	stateOmega
+	statePreconditioner
+	stateExport
+	stateSource
+	stateAnalysisResult
+	stateEigenMethod
+	stateShift
+	stateEigenResult
+	stateSaveSession
+)
+
+// mode is what the entered matrix is used for.
+type mode int
+
+const (
+	modeSolve mode = iota
+	modeAnalysis
+	modeEigen
+)
+
+// Condition numbers above these thresholds trigger warnings on the analysis
+// screen; epsilon is the float64 machine epsilon.
+const (
+	condWarning = 1e3
+	condSevere  = 1e8
+	epsilon     = 0x1p-52
 )
 
 type model struct {
-	state       state
-	inputMethod string
-	dimension   int
-	currentRow  int
-	matrix      [][]float64
-	vector      []float64
-	precision   float64
-	inputBuffer string
-	errorMsg    string
-	result      *solver.Result
-	err         error
-	blink       bool
+	state          state
+	mode           mode
+	inputMethod    string
+	dimension      int
+	matrix         [][]float64
+	sparse         *solver.CSR
+	vector         []float64
+	precision      float64
+	method         solver.Method
+	omega          float64
+	preconditioner solver.Preconditioner
+	backend        solver.Backend
+	stop           solver.StopCriterion
+	editor         editor
+	inputBuffer    string
+	errorMsg       string
+	notice         string
+	result         *solver.Result
+	analysis       *solver.MatrixAnalysis
+	eigenMethod    solver.EigenMethod
+	shift          float64
+	eigen          *solver.EigenResult
+	jacobiRadius   float64
+	err            error
+	blink          bool
+	traceView      bool
+	tracePage      int
+	run            *solveRun
+	progress       progressMsg
+	recent         []session.Entry
+	sessionName    string
+	saveReturn     state
 }
 
+// traceRows is the number of iterations shown per page of the trace view.
+const traceRows = 10
+
+// divergenceSteps is how many consecutive growing steps the TUI tolerates
+// before reporting divergence.
+const divergenceSteps = 25
+
 func NewProgram() *tea.Program {
-	return tea.NewProgram(model{
+	m := model{
 		state:  stateMenu,
 		matrix: make([][]float64, 0),
 		vector: make([]float64, 0),
 		blink:  true,
-	})
+	}
+	return tea.NewProgram(m.loadRecent())
 }
 
 func (m model) Init() tea.Cmd {
@@ -69,29 +129,97 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleMenuInput(msg)
 		case stateDimension:
 			return m.handleDimensionInput(msg)
-		case stateMatrixInput:
-			return m.handleMatrixInput(msg)
+		case stateEditor:
+			return m.handleEditorInput(msg)
 		case stateFileInput:
 			return m.handleFileInput(msg)
 		case statePrecision:
 			return m.handlePrecisionInput(msg)
+		case stateMethod:
+			return m.handleMethodInput(msg)
+		case stateOmega:
+			return m.handleOmegaInput(msg)
+		case statePreconditioner:
+			return m.handlePreconditionerInput(msg)
+		case stateExport:
+			return m.handleExportInput(msg)
+		case stateSource:
+			return m.handleSourceInput(msg)
+		case stateAnalysisResult:
+			return m.handleAnalysisResultInput(msg)
+		case stateEigenMethod:
+			return m.handleEigenMethodInput(msg)
+		case stateShift:
+			return m.handleShiftInput(msg)
+		case stateEigenResult:
+			return m.handleEigenResultInput(msg)
 		case stateResult:
-			if msg.String() == "q" || msg.String() == "ctrl+c" {
-				return m, tea.Quit
-			}
+			return m.handleResultInput(msg)
+		case stateProcessing:
+			return m.handleProcessingInput(msg)
+		case stateSaveSession:
+			return m.handleSaveSessionInput(msg)
 		default:
 			panic("unhandled state")
 		}
 
+	case analysisMsg:
+		if m.finishRun(msg.err) {
+			return m, nil
+		}
+		m.analysis = msg.analysis
+		m.err = msg.err
+		m.state = stateAnalysisResult
+
+	case eigenMsg:
+		if m.finishRun(msg.err) {
+			return m, nil
+		}
+		m.eigen = msg.eigen
+		m.jacobiRadius = msg.jacobiRadius
+		m.err = msg.err
+		m.traceView = false
+		m.tracePage = 0
+		m.state = stateEigenResult
+
+	case solveStartedMsg:
+		m.run = msg.run
+		m.progress = progressMsg{}
+		return m, waitForUpdate(m.run)
+
+	case progressMsg:
+		m.progress = msg
+		return m, waitForUpdate(m.run)
+
 	case solverMsg:
+		if m.finishRun(msg.err) {
+			return m, nil
+		}
 		m.result = msg.result
 		m.err = msg.err
+		m.traceView = false
+		m.tracePage = 0
+		m.notice = ""
+		m.errorMsg = ""
 		m.state = stateResult
 	}
 
 	return m, nil
 }
 
+// finishRun clears the finished background run and, when it was
+// cancelled with Esc, returns to the menu with the cancellation message;
+// it reports whether it did so.
+func (m *model) finishRun(err error) bool {
+	m.run = nil
+	if !errors.Is(err, context.Canceled) {
+		return false
+	}
+	m.errorMsg = err.Error()
+	m.state = stateMenu
+	return true
+}
+
 func (m model) View() string {
 	var s strings.Builder
 
@@ -102,8 +230,112 @@ func (m model) View() string {
 		s.WriteString("╰──────────────────────────────────────────╯\n\n")
 		s.WriteString("Choose input method:\n")
 		s.WriteString("1 - Interactive input\n")
+		s.WriteString("2 - File input\n")
+		s.WriteString("3 - Matrix analysis (det, inverse, condition number)\n")
+		s.WriteString("4 - Eigenvalues and eigenvectors\n\n")
+		m.writeRecent(&s)
+		s.WriteString("Method: " + m.methodLabel() + "\n")
+		if m.backend != solver.Float64 {
+			s.WriteString("Arithmetic: " + m.backend.String() + "\n")
+		}
+		if m.stop != solver.StopAbsolute {
+			s.WriteString("Stopping criterion: " + m.stop.String() + "\n")
+		}
+		s.WriteString("Press 'm' to change method, 'q' to quit")
+		if m.errorMsg != "" {
+			s.WriteString("\n\n" + m.errorMsg)
+		}
+
+	case stateSource:
+		s.WriteString("╭──────────────────────────────────────────╮\n")
+		if m.mode == modeEigen {
+			s.WriteString("│       Eigenvalues and Eigenvectors       │\n")
+		} else {
+			s.WriteString("│             Matrix Analysis              │\n")
+		}
+		s.WriteString("╰──────────────────────────────────────────╯\n\n")
+		s.WriteString("Choose input method:\n")
+		s.WriteString("1 - Interactive input\n")
 		s.WriteString("2 - File input\n\n")
-		s.WriteString("Press 'q' to quit")
+		s.WriteString("The right-hand side b is read but not used.\n")
+		s.WriteString("Press Esc to go back")
+
+	case stateAnalysisResult:
+		m.writeAnalysis(&s)
+		s.WriteString("\nPress Esc or Enter to return to the menu, 'q' to quit")
+
+	case stateEigenMethod:
+		s.WriteString("╭──────────────────────────────────────────╮\n")
+		s.WriteString("│       Eigenvalues and Eigenvectors       │\n")
+		s.WriteString("╰──────────────────────────────────────────╯\n\n")
+		s.WriteString("Choose eigenvalue method:\n")
+		s.WriteString("1 - Power method (dominant eigenvalue)\n")
+		s.WriteString("2 - Inverse iteration (eigenvalue closest to a shift)\n")
+		s.WriteString("3 - QR algorithm (all eigenvalues)\n\n")
+		s.WriteString("Press Esc to go back")
+
+	case stateShift:
+		s.WriteString("Enter shift σ:\n")
+		s.WriteString("Inverse iteration converges to the eigenvalue closest to σ\n\n")
+		s.WriteString(m.inputBuffer)
+		if m.blink {
+			s.WriteString("█")
+		}
+		if m.errorMsg != "" {
+			s.WriteString("\n\nError: " + m.errorMsg)
+		}
+
+	case stateEigenResult:
+		if m.err == nil && m.traceView {
+			m.writeEigenHistory(&s)
+			s.WriteString("\nPress ←/→ to page, 't' to return to the eigenvalues")
+		} else {
+			m.writeEigen(&s)
+			if m.err == nil && len(m.eigen.History) > 0 {
+				s.WriteString("\nPress 't' to view the iteration history")
+			}
+		}
+		s.WriteString("\nPress Esc or Enter to return to the menu, 'q' to quit")
+
+	case stateMethod:
+		s.WriteString("╭──────────────────────────────────────────╮\n")
+		s.WriteString("│            Solution Method               │\n")
+		s.WriteString("╰──────────────────────────────────────────╯\n\n")
+		s.WriteString("Choose solution method:\n")
+		s.WriteString("1 - Jacobi (simple iteration)\n")
+		s.WriteString("2 - Gauss-Seidel\n")
+		s.WriteString("3 - SOR (successive over-relaxation)\n")
+		s.WriteString("4 - Gaussian elimination (direct)\n")
+		s.WriteString("5 - LU decomposition (direct)\n")
+		s.WriteString("6 - Cholesky decomposition (direct, SPD only)\n")
+		s.WriteString("7 - Conjugate gradient (Krylov, SPD only)\n")
+		s.WriteString("8 - GMRES (Krylov)\n")
+		s.WriteString("9 - BiCGSTAB (Krylov)\n\n")
+		s.WriteString("Current: " + m.methodLabel() + "\n")
+		s.WriteString("Arithmetic: " + m.backend.String() + " (press 'b' to switch)\n")
+		s.WriteString("Stopping criterion: " + stopLabel(m.stop) + " (press 's' to switch)\n")
+		s.WriteString("Press Esc to go back")
+
+	case statePreconditioner:
+		s.WriteString("╭──────────────────────────────────────────╮\n")
+		s.WriteString("│            Preconditioner                │\n")
+		s.WriteString("╰──────────────────────────────────────────╯\n\n")
+		s.WriteString(fmt.Sprintf("Choose preconditioner for %s:\n", m.method))
+		s.WriteString("1 - None\n")
+		s.WriteString("2 - Jacobi (diagonal)\n")
+		s.WriteString("3 - ILU(0)\n\n")
+		s.WriteString("Press Esc to go back")
+
+	case stateOmega:
+		s.WriteString("Enter relaxation factor ω (0 < ω < 2):\n")
+		s.WriteString("Leave empty to estimate it automatically\n\n")
+		s.WriteString(m.inputBuffer)
+		if m.blink {
+			s.WriteString("█")
+		}
+		if m.errorMsg != "" {
+			s.WriteString("\n\nError: " + m.errorMsg)
+		}
 
 	case stateDimension:
 		s.WriteString("Enter matrix dimension (1-20):\n")
@@ -116,62 +348,35 @@ func (m model) View() string {
 			s.WriteString("\n\nError: " + m.errorMsg)
 		}
 
-	case stateMatrixInput:
-		s.WriteString(fmt.Sprintf("Enter row %d of %d\n", m.currentRow+1, m.dimension))
-		s.WriteString(fmt.Sprintf("Format: enter %d numbers separated by spaces\n", m.dimension+1))
-		s.WriteString(fmt.Sprintf("Example: for 2x2 matrix, enter: 4 1 1\n\n"))
-
-		// Draw matrix frame
-		s.WriteString("Current matrix:\n")
-		s.WriteString("╭" + strings.Repeat("─", m.dimension*8+1) + "╮ ╭" + strings.Repeat("─", 8) + "╮\n")
-
-		for i := 0; i < m.dimension; i++ {
-			s.WriteString("│")
-			if i < m.currentRow {
-				// Show completed rows
-				for j := 0; j < m.dimension; j++ {
-					s.WriteString(fmt.Sprintf(" %6.2f", m.matrix[i][j]))
-				}
-				s.WriteString(" │ │")
-				s.WriteString(fmt.Sprintf(" %6.2f", m.vector[i]))
-				s.WriteString(" │\n")
-			} else if i == m.currentRow {
-				// Current input row
-				nums := strings.Fields(m.inputBuffer)
-				for j := 0; j < m.dimension; j++ {
-					if j < len(nums) {
-						s.WriteString(fmt.Sprintf(" %6s", nums[j]))
-					} else {
-						s.WriteString("      ·")
-					}
-				}
-				s.WriteString(" │ │")
-				if len(nums) > m.dimension {
-					s.WriteString(fmt.Sprintf(" %6s", nums[m.dimension]))
-				} else {
-					s.WriteString("      ·")
-				}
-				s.WriteString(" │")
-				if m.blink {
-					s.WriteString("█")
-				}
-				s.WriteString("\n")
-			} else {
-				// Future rows
-				s.WriteString(strings.Repeat("      ·", m.dimension))
-				s.WriteString(" │ │      ·")
-				s.WriteString(" │\n")
-			}
+	case stateEditor:
+		s.WriteString(m.editor.view(m.blink))
+		s.WriteString("\nPress Ctrl+S to save the session")
+		if m.notice != "" {
+			s.WriteString("\n\n" + m.notice)
 		}
-		s.WriteString("╰" + strings.Repeat("─", m.dimension*8+1) + "╯ ╰" + strings.Repeat("─", 8) + "╯\n")
 
+	case stateSaveSession:
+		s.WriteString("╭──────────────────────────────────────────╮\n")
+		s.WriteString("│              Save Session                │\n")
+		s.WriteString("╰──────────────────────────────────────────╯\n\n")
+		s.WriteString("The system, settings and last result are saved\n")
+		s.WriteString("and listed on the menu under recent sessions.\n\n")
+		s.WriteString("Session name (press Enter to save): ")
+		s.WriteString(m.inputBuffer)
+		if m.blink {
+			s.WriteString("█")
+		}
 		if m.errorMsg != "" {
-			s.WriteString("\nError: " + m.errorMsg)
+			s.WriteString("\n\nError: " + m.errorMsg)
 		}
 
 	case statePrecision:
 		s.WriteString("Enter precision:\n")
-		s.WriteString("Example: 0.0001 or 1e-4\n\n")
+		if m.precision > 0 {
+			s.WriteString(fmt.Sprintf("Press Enter to keep %g\n\n", m.precision))
+		} else {
+			s.WriteString("Example: 0.0001 or 1e-4\n\n")
+		}
 		s.WriteString(m.inputBuffer)
 		if m.blink {
 			s.WriteString("█")
@@ -181,38 +386,137 @@ func (m model) View() string {
 		}
 
 	case stateProcessing:
-		s.WriteString("Processing...")
+		m.writeProgress(&s)
 
 	case stateResult:
 		if m.err != nil {
 			s.WriteString("Error: " + m.err.Error() + "\n\n")
-			if m.err.Error() == "unable to achieve diagonal dominance" {
+			if errors.Is(m.err, solver.ErrConvergenceNotGuaranteed) {
 				s.WriteString("╭──────────────────────────────────────────╮\n")
-				s.WriteString("│           Diagonal Dominance            │\n")
+				s.WriteString("│          Convergence Analysis            │\n")
 				s.WriteString("╰──────────────────────────────────────────╯\n\n")
-				s.WriteString("Your matrix doesn't satisfy this condition\n")
-				s.WriteString("even after attempting row reordering.\n\n")
+				s.WriteString("No ordering of the equations gives an iteration\n")
+				s.WriteString("matrix with spectral radius below one.\n\n")
+				s.WriteString("Press 'd' to solve directly instead (Gaussian elimination)\n")
+			}
+			var diverged *solver.DivergenceError
+			var limit *solver.IterationLimitError
+			switch {
+			case errors.As(m.err, &diverged) && diverged.NonFinite:
+				s.WriteString("The iterates overflowed; the method diverges for this system.\n")
+				s.WriteString("Press 'd' to solve directly instead (Gaussian elimination)\n")
+			case errors.As(m.err, &diverged):
+				s.WriteString("The change between iterates keeps growing; the method is\n")
+				s.WriteString("unlikely to converge for this system.\n")
+				s.WriteString("Press 'd' to solve directly instead (Gaussian elimination)\n")
+			case errors.As(m.err, &limit):
+				s.WriteString("The method is converging too slowly for the requested precision.\n")
+				s.WriteString("Try a larger precision, another method, or press 'd' to solve directly.\n")
 			}
+		} else if m.traceView {
+			m.writeTrace(&s)
 		} else {
 			s.WriteString("╭──────────────────────────────────────────╮\n")
 			s.WriteString("│              Solution                    │\n")
 			s.WriteString("╰──────────────────────────────────────────╯\n\n")
 			s.WriteString("Solution vector:\n")
-			for i, val := range m.result.Solution {
-				s.WriteString(fmt.Sprintf("x%d = %12.6f\n", i+1, val))
+			if m.result.ExactSolution != nil {
+				for i, val := range m.result.ExactSolution {
+					if i == maxShownRows {
+						s.WriteString(fmt.Sprintf("… %d more\n", len(m.result.ExactSolution)-maxShownRows))
+						break
+					}
+					s.WriteString(fmt.Sprintf("x%d = %s\n", i+1, val))
+				}
+			} else {
+				writeVector(&s, "x%d = %12.6f\n", m.result.Solution)
+			}
+			s.WriteString(fmt.Sprintf("\nMethod: %s", m.result.Method))
+			if m.result.Method == solver.SOR {
+				s.WriteString(fmt.Sprintf(" (ω = %.4f)", m.result.Omega))
+			}
+			if m.result.Method.IsKrylov() {
+				s.WriteString(fmt.Sprintf(", preconditioner: %s", m.preconditioner))
+			}
+			if m.result.Backend != solver.Float64 {
+				s.WriteString(fmt.Sprintf(", arithmetic: %s", m.result.Backend))
+			}
+			if m.result.Method.IsDirect() {
+				s.WriteString(fmt.Sprintf("\nDeterminant: %.6g\n", m.result.Determinant))
+			} else {
+				s.WriteString(fmt.Sprintf("\nConverged in %d iterations\n", m.result.Iterations))
+				s.WriteString("\nFinal errors:\n")
+				writeVector(&s, "e%d = %12.6e\n", m.result.Errors)
 			}
-			s.WriteString(fmt.Sprintf("\nConverged in %d iterations\n", m.result.Iterations))
-			s.WriteString("\nFinal errors:\n")
-			for i, err := range m.result.Errors {
-				s.WriteString(fmt.Sprintf("e%d = %12.6e\n", i+1, err))
+			if m.result.Convergence != nil {
+				if m.result.BoundsEstimated {
+					s.WriteString(fmt.Sprintf("\nError estimates (q = ρ ≈ %.6f, no norm of the iteration operator is below 1):\n", m.result.Q))
+				} else {
+					s.WriteString(fmt.Sprintf("\nError bounds (q = %.6f):\n", m.result.Q))
+				}
+				if m.result.APrioriIterations > 0 {
+					s.WriteString(fmt.Sprintf("A priori estimate:  %d iterations\n", m.result.APrioriIterations))
+				}
+				if m.result.BoundsEstimated {
+					s.WriteString(fmt.Sprintf("A posteriori estimate: ‖x* - xₖ‖ ≈ %.6e\n", m.result.APosterioriBound))
+				} else {
+					s.WriteString(fmt.Sprintf("A posteriori bound: ‖x* - xₖ‖ ≤ %.6e\n", m.result.APosterioriBound))
+				}
 			}
-			s.WriteString(fmt.Sprintf("\nMatrix norm: %.6f\n", m.result.MatrixNorm))
+			s.WriteString("\nResiduals (b - Ax):\n")
+			writeVector(&s, "r%d = %12.6e\n", m.result.Residuals)
+			if c := m.result.Convergence; c != nil {
+				s.WriteString("\nIteration matrix C = I - D⁻¹A:\n")
+				s.WriteString(fmt.Sprintf("‖C‖∞ = %.6f  ‖C‖₁ = %.6f  ‖C‖F = %.6f\n", c.NormInf, c.NormOne, c.NormFrobenius))
+				s.WriteString(fmt.Sprintf("ρ(C) ≈ %.6f", c.JacobiRadius))
+				if m.result.Method != solver.Jacobi {
+					s.WriteString(fmt.Sprintf("  ρ(%s) ≈ %.6f", m.result.Method, c.SpectralRadius))
+				}
+				s.WriteString("\n")
+				if !c.Dominant {
+					s.WriteString("Not strictly diagonally dominant; convergence follows from the analysis above\n")
+				}
+			} else {
+				s.WriteString(fmt.Sprintf("\nMatrix norm: %.6f\n", m.result.MatrixNorm))
+			}
+		}
+		if m.notice != "" {
+			s.WriteString("\n" + m.notice + "\n")
 		}
+		if m.errorMsg != "" {
+			s.WriteString("\nError: " + m.errorMsg + "\n")
+		}
+		if m.err == nil && len(m.result.Trace) > 0 {
+			if m.traceView {
+				s.WriteString("\nPress ←/→ to page, 't' to return to the solution")
+			} else {
+				s.WriteString("\nPress 't' to view the iteration trace")
+			}
+		}
+		if m.err == nil {
+			s.WriteString("\nPress 'e' to export the solution")
+		}
+		s.WriteString("\nPress Ctrl+S to save the session, Ctrl+E to edit the system")
 		s.WriteString("\nPress 'q' to quit")
+
+	case stateExport:
+		s.WriteString("╭──────────────────────────────────────────╮\n")
+		s.WriteString("│            Export Solution               │\n")
+		s.WriteString("╰──────────────────────────────────────────╯\n\n")
+		s.WriteString("The format follows the extension: .mtx, .csv, .json or text\n\n")
+		s.WriteString("Enter file path (press Enter to save): ")
+		s.WriteString(m.inputBuffer)
+		if m.blink {
+			s.WriteString("█")
+		}
+		if m.errorMsg != "" {
+			s.WriteString("\n\nError: " + m.errorMsg)
+		}
 	case stateFileInput:
 		s.WriteString("╭──────────────────────────────────────────╮\n")
 		s.WriteString("│              File Input                  │\n")
 		s.WriteString("╰──────────────────────────────────────────╯\n\n")
+		s.WriteString("Press Ctrl+E to open the file in the matrix editor instead\n\n")
 		s.WriteString("Enter file path (press Enter to load): ")
 		s.WriteString(m.inputBuffer)
 		if m.blink {
@@ -226,168 +530,503 @@ func (m model) View() string {
 	return s.String()
 }
 
-type inputData struct {
-	matrix    [][]float64
-	vector    []float64
-	precision float64
-}
-
-func parseInput(input string) (*inputData, error) {
-	lines := strings.Split(strings.TrimSpace(input), "\n")
+// maxShownRows limits how many vector components the result screen lists.
+const maxShownRows = 20
 
-	if len(lines) < 2 {
-		return nil, fmt.Errorf("insufficient input data")
-	}
+type solverMsg struct {
+	result *solver.Result
+	err    error
+}
 
-	n, err := strconv.Atoi(strings.TrimSpace(lines[0]))
-	if err != nil {
-		return nil, fmt.Errorf("invalid dimension: %v", err)
-	}
+type analysisMsg struct {
+	analysis *solver.MatrixAnalysis
+	err      error
+}
 
-	if n > 20 || n <= 0 {
-		return nil, fmt.Errorf("dimension must be between 1 and 20")
-	}
+type eigenMsg struct {
+	eigen        *solver.EigenResult
+	jacobiRadius float64
+	err          error
+}
 
-	if len(lines) < n+2 {
-		return nil, fmt.Errorf("insufficient matrix data")
+// process starts the computation selected from the menu.
+func process(m model) tea.Cmd {
+	switch m.mode {
+	case modeAnalysis:
+		return processAnalysis(m)
+	case modeEigen:
+		return processEigen(m)
 	}
+	return processSolution(m)
+}
 
-	matrix := make([][]float64, n)
-	vector := make([]float64, n)
-
-	for i := 0; i < n; i++ {
-		nums := strings.Fields(lines[i+1])
-		if len(nums) != n+1 {
-			return nil, fmt.Errorf("invalid number of coefficients in row %d", i+1)
+func processAnalysis(m model) tea.Cmd {
+	return startTask(func(ctx context.Context) tea.Msg {
+		A := m.matrix
+		if m.sparse != nil {
+			if m.dimension > format.MaxDenseDimension {
+				return analysisMsg{err: fmt.Errorf("matrix analysis needs dense storage, limited to %d unknowns", format.MaxDenseDimension)}
+			}
+			A = m.sparse.Dense()
 		}
+		analysis, err := solver.AnalyzeMatrixContext(ctx, A)
+		return analysisMsg{analysis: analysis, err: err}
+	})
+}
 
-		row := make([]float64, n)
-		for j := 0; j < n; j++ {
-			val, err := strconv.ParseFloat(nums[j], 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid coefficient at row %d, col %d: %v", i+1, j+1, err)
+// processEigen runs the selected eigenvalue method and, when A has no zero
+// on the diagonal, the QR algorithm on the Jacobi iteration matrix so the
+// result screen can predict whether simple iteration converges.
+func processEigen(m model) tea.Cmd {
+	return startTask(func(ctx context.Context) tea.Msg {
+		A := m.matrix
+		if m.sparse != nil {
+			if m.dimension > format.MaxDenseDimension {
+				return eigenMsg{err: fmt.Errorf("eigenvalue methods need dense storage, limited to %d unknowns", format.MaxDenseDimension)}
 			}
-			row[j] = val
+			A = m.sparse.Dense()
 		}
-
-		bVal, err := strconv.ParseFloat(nums[n], 64)
+		eigen, err := solver.EigenContext(ctx, A, m.eigenMethod, m.shift, m.precision)
 		if err != nil {
-			return nil, fmt.Errorf("invalid right-hand side value at row %d: %v", i+1, err)
+			return eigenMsg{err: err}
 		}
 
-		matrix[i] = row
-		vector[i] = bVal
-	}
-
-	precision, err := strconv.ParseFloat(strings.TrimSpace(lines[n+1]), 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid precision value: %v", err)
-	}
-
-	return &inputData{
-		matrix:    matrix,
-		vector:    vector,
-		precision: precision,
-	}, nil
-}
-
-type solverMsg struct {
-	result *solver.Result
-	err    error
+		rho := -1.0
+		if C, err := solver.IterationMatrix(A); err == nil {
+			jacobi, err := solver.EigenContext(ctx, C, solver.QRAlgorithm, 0, m.precision)
+			if err == nil {
+				rho = jacobi.SpectralRadius()
+			} else if errors.Is(err, context.Canceled) {
+				return eigenMsg{err: err}
+			}
+		}
+		return eigenMsg{eigen: eigen, jacobiRadius: rho}
+	})
 }
 
 func processSolution(m model) tea.Cmd {
-	return func() tea.Msg {
-		result, err := solver.SolveSystem(m.matrix, m.vector, m.precision)
-		return solverMsg{result: result, err: err}
+	opts := solver.Options{
+		Method:          m.method,
+		Omega:           m.omega,
+		Preconditioner:  m.preconditioner,
+		Backend:         m.backend,
+		Trace:           m.sparse == nil,
+		Stop:            m.stop,
+		DivergenceSteps: divergenceSteps,
 	}
+	return startSolve(opts, func(ctx context.Context, opts solver.Options) (*solver.Result, error) {
+		if m.sparse != nil {
+			return solver.SolveSparseContext(ctx, m.sparse, m.vector, m.precision, opts)
+		}
+		return solver.SolveContext(ctx, m.matrix, m.vector, m.precision, opts)
+	})
 }
 
 func (m model) handleMenuInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "1":
+		m.mode = modeSolve
 		m.inputMethod = "keyboard"
 		m.state = stateDimension
 		m.errorMsg = ""
 	case "2":
+		m.mode = modeSolve
 		m.inputMethod = "file"
 		m.state = stateFileInput
 		m.errorMsg = ""
+	case "3":
+		m.mode = modeAnalysis
+		m.state = stateSource
+		m.errorMsg = ""
+	case "4":
+		m.state = stateEigenMethod
+		m.errorMsg = ""
+	case "m":
+		m.state = stateMethod
+		m.errorMsg = ""
 	case "q", "ctrl+c":
 		return m, tea.Quit
+	default:
+		if i := strings.Index(recentKeys, msg.String()); len(msg.String()) == 1 && i >= 0 && i < len(m.recent) {
+			return m.openSession(m.recent[i])
+		}
 	}
 	return m, nil
 }
 
-func (m model) handleDimensionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// stopLabel describes a stopping criterion as a formula.
+func stopLabel(c solver.StopCriterion) string {
+	switch c {
+	case solver.StopRelative:
+		return "relative, ‖xₖ - xₖ₋₁‖∞ < ε‖xₖ‖∞"
+	case solver.StopResidual:
+		return "residual, ‖b - Axₖ‖∞ < ε"
+	default:
+		return "absolute, ‖xₖ - xₖ₋₁‖∞ < ε"
+	}
+}
+
+// directFallback reports whether the result screen offers to solve the
+// system directly after err.
+func directFallback(err error) bool {
+	var diverged *solver.DivergenceError
+	var limit *solver.IterationLimitError
+	return errors.Is(err, solver.ErrConvergenceNotGuaranteed) || errors.As(err, &diverged) || errors.As(err, &limit)
+}
+
+func (m model) methodLabel() string {
+	if m.method.IsKrylov() {
+		return fmt.Sprintf("%s (preconditioner: %s)", m.method, m.preconditioner)
+	}
+	if m.method != solver.SOR {
+		return m.method.String()
+	}
+	if m.omega == 0 {
+		return "SOR (ω auto)"
+	}
+	return fmt.Sprintf("SOR (ω = %g)", m.omega)
+}
+
+func (m model) handleMethodInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "1":
+		m.method = solver.Jacobi
+		m.state = stateMenu
+	case "2":
+		m.method = solver.GaussSeidel
+		m.state = stateMenu
+	case "3":
+		m.method = solver.SOR
+		m.inputBuffer = ""
+		m.state = stateOmega
+	case "4":
+		m.method = solver.Gauss
+		m.state = stateMenu
+	case "5":
+		m.method = solver.LUDecomposition
+		m.state = stateMenu
+	case "6":
+		m.method = solver.CholeskyDecomposition
+		m.state = stateMenu
+	case "7":
+		m.method = solver.ConjugateGradient
+		m.state = statePreconditioner
+	case "8":
+		m.method = solver.GMRES
+		m.state = statePreconditioner
+	case "9":
+		m.method = solver.BiCGSTAB
+		m.state = statePreconditioner
+	case "b":
+		m.backend = (m.backend + 1) % (solver.Rational + 1)
+	case "s":
+		m.stop = (m.stop + 1) % (solver.StopResidual + 1)
+	case "esc":
+		m.state = stateMenu
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) handlePreconditionerInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "1":
+		m.preconditioner = solver.NoPreconditioner
+		m.state = stateMenu
+	case "2":
+		m.preconditioner = solver.JacobiPreconditioner
+		m.state = stateMenu
+	case "3":
+		m.preconditioner = solver.ILU0
+		m.state = stateMenu
+	case "esc":
+		m.state = stateMethod
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) handleOmegaInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEnter:
-		n, err := strconv.Atoi(strings.TrimSpace(m.inputBuffer))
-		if err != nil {
-			m.errorMsg = "Please enter a valid number"
-		} else if n <= 0 || n > 20 {
-			m.errorMsg = "Dimension must be between 1 and 20"
+		input := strings.TrimSpace(m.inputBuffer)
+		if input == "" {
+			m.omega = 0
 		} else {
-			m.dimension = n
-			m.matrix = make([][]float64, n)
-			m.vector = make([]float64, n)
-			m.currentRow = 0
-			m.state = stateMatrixInput
-			m.errorMsg = ""
+			val, err := strconv.ParseFloat(input, 64)
+			if err != nil {
+				m.errorMsg = "Please enter a valid number"
+				m.inputBuffer = ""
+				return m, nil
+			}
+			if val <= 0 || val >= 2 {
+				m.errorMsg = "Relaxation factor must be between 0 and 2"
+				m.inputBuffer = ""
+				return m, nil
+			}
+			m.omega = val
 		}
 		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = stateMenu
+
 	case tea.KeyBackspace:
 		if len(m.inputBuffer) > 0 {
 			m.inputBuffer = m.inputBuffer[:len(m.inputBuffer)-1]
 		}
+	case tea.KeyEsc:
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = stateMethod
 	case tea.KeyCtrlC:
 		return m, tea.Quit
 	default:
-		if len(msg.String()) == 1 && strings.Contains("0123456789", msg.String()) {
+		if len(msg.String()) == 1 && strings.Contains("0123456789.", msg.String()) {
 			m.inputBuffer += msg.String()
 		}
 	}
 	return m, nil
 }
 
-func (m model) handleMatrixInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// writeVector lists the components of v with the given format, eliding
+// everything after the first maxShownRows.
+func writeVector(s *strings.Builder, format string, v []float64) {
+	for i, val := range v {
+		if i == maxShownRows {
+			s.WriteString(fmt.Sprintf("… %d more\n", len(v)-maxShownRows))
+			break
+		}
+		s.WriteString(fmt.Sprintf(format, i+1, val))
+	}
+}
+
+func (m model) writeTrace(s *strings.Builder) {
+	trace := m.result.Trace
+	pages := (len(trace) + traceRows - 1) / traceRows
+	s.WriteString("╭──────────────────────────────────────────╮\n")
+	s.WriteString("│             Iteration Trace              │\n")
+	s.WriteString("╰──────────────────────────────────────────╯\n\n")
+	s.WriteString(fmt.Sprintf("Page %d of %d\n\n", m.tracePage+1, pages))
+
+	shown := len(m.result.Solution)
+	if shown > 4 {
+		shown = 4
+	}
+	s.WriteString(fmt.Sprintf("%5s %13s %13s", "k", "‖xₖ-xₖ₋₁‖", "‖Axₖ-b‖"))
+	for i := 0; i < shown; i++ {
+		s.WriteString(fmt.Sprintf(" %12s", fmt.Sprintf("x%d", i+1)))
+	}
+	s.WriteString("\n")
+
+	start := m.tracePage * traceRows
+	end := start + traceRows
+	if end > len(trace) {
+		end = len(trace)
+	}
+	for _, step := range trace[start:end] {
+		s.WriteString(fmt.Sprintf("%5d %13.6e %13.6e", step.Iteration, step.Difference, step.Residual))
+		for i := 0; i < shown; i++ {
+			s.WriteString(fmt.Sprintf(" %12.6f", step.X[i]))
+		}
+		if shown < len(step.X) {
+			s.WriteString(" …")
+		}
+		s.WriteString("\n")
+	}
+}
+
+func (m model) handleSourceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "1":
+		m.inputMethod = "keyboard"
+		m.state = stateDimension
+		m.errorMsg = ""
+	case "2":
+		m.inputMethod = "file"
+		m.state = stateFileInput
+		m.errorMsg = ""
+	case "esc":
+		if m.mode == modeEigen {
+			m.state = stateEigenMethod
+		} else {
+			m.state = stateMenu
+		}
+		m.mode = modeSolve
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) handleAnalysisResultInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "enter":
+		m.err = nil
+		m.analysis = nil
+		m.mode = modeSolve
+		m.state = stateMenu
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) writeAnalysis(s *strings.Builder) {
+	s.WriteString("╭──────────────────────────────────────────╮\n")
+	s.WriteString("│             Matrix Analysis              │\n")
+	s.WriteString("╰──────────────────────────────────────────╯\n\n")
+	if m.err != nil {
+		s.WriteString("Error: " + m.err.Error() + "\n")
+		return
+	}
+
+	a := m.analysis
+	s.WriteString(fmt.Sprintf("det(A) = %.6g\n\n", a.Determinant))
+	s.WriteString(fmt.Sprintf("%-10s %14s %14s\n", "Norm", "‖A‖", "cond(A)"))
+	s.WriteString(fmt.Sprintf("%-10s %14.6g %14.6g\n", "1", a.NormOne, a.CondOne))
+	s.WriteString(fmt.Sprintf("%-10s %14.6g %14.6g\n", "∞", a.NormInf, a.CondInf))
+	s.WriteString(fmt.Sprintf("%-10s %14.6g %14.6g\n", "2", a.Norm2, a.Cond2))
+	s.WriteString(fmt.Sprintf("%-10s %14.6g %14.6g\n", "Frobenius", a.NormFrobenius, a.CondFrobenius))
+
+	cond := a.CondInf
+	switch {
+	case a.Singular:
+		s.WriteString("\nWarning: the matrix is singular; Ax = b has no unique solution.\n")
+	case cond > 1/epsilon:
+		s.WriteString("\nWarning: the matrix is numerically singular in float64;\n")
+		s.WriteString("no digits of the solution can be trusted.\n")
+	case cond > condSevere:
+		s.WriteString("\nWarning: the matrix is severely ill-conditioned;\n")
+		s.WriteString(fmt.Sprintf("expect to lose about %.0f significant digits.\n", math.Log10(cond)))
+	case cond > condWarning:
+		s.WriteString("\nWarning: the matrix is ill-conditioned; iterative methods\n")
+		s.WriteString(fmt.Sprintf("may converge slowly and lose about %.0f digits.\n", math.Log10(cond)))
+	}
+
+	if a.Inverse == nil {
+		return
+	}
+	if len(a.Inverse) > 8 {
+		s.WriteString(fmt.Sprintf("\nA⁻¹ is %dx%d and too large to display.\n", len(a.Inverse), len(a.Inverse)))
+		return
+	}
+	s.WriteString("\nA⁻¹:\n")
+	for _, row := range a.Inverse {
+		for _, v := range row {
+			s.WriteString(fmt.Sprintf(" %11.5g", v))
+		}
+		s.WriteString("\n")
+	}
+}
+
+func (m model) handleResultInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "e":
+		if m.err == nil {
+			m.inputBuffer = ""
+			m.errorMsg = ""
+			m.state = stateExport
+		}
+	case "ctrl+s":
+		return m.startSave(stateResult)
+	case "ctrl+e":
+		m.notice = ""
+		m.errorMsg = ""
+		return m.openEditor()
+	case "t":
+		if m.err == nil && len(m.result.Trace) > 0 {
+			m.traceView = !m.traceView
+		}
+	case "right", "n":
+		if m.traceView && (m.tracePage+1)*traceRows < len(m.result.Trace) {
+			m.tracePage++
+		}
+	case "left", "p":
+		if m.traceView && m.tracePage > 0 {
+			m.tracePage--
+		}
+	case "d":
+		if directFallback(m.err) {
+			m.method = solver.Gauss
+			m.err = nil
+			m.state = stateProcessing
+			return m, processSolution(m)
+		}
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) handleExportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEnter:
-		numbers := strings.Fields(m.inputBuffer)
-		if len(numbers) != m.dimension+1 {
-			m.errorMsg = fmt.Sprintf("Please enter %d numbers (matrix row + vector element)", m.dimension+1)
-			m.inputBuffer = ""
+		filePath := strings.TrimSpace(m.inputBuffer)
+		if filePath == "" {
+			m.errorMsg = "Please enter a file path"
 			return m, nil
 		}
 
-		row := make([]float64, m.dimension)
-		for i, num := range numbers[:m.dimension] {
-			val, err := strconv.ParseFloat(num, 64)
-			if err != nil {
-				m.errorMsg = fmt.Sprintf("Invalid number: %s", num)
-				m.inputBuffer = ""
-				return m, nil
-			}
-			row[i] = val
+		file, err := os.Create(filePath)
+		if err != nil {
+			m.errorMsg = fmt.Sprintf("Failed to create file: %v", err)
+			return m, nil
+		}
+		f := format.ByName(filePath)
+		err = format.WriteSolution(file, f, m.result)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
 		}
-
-		bVal, err := strconv.ParseFloat(numbers[m.dimension], 64)
 		if err != nil {
-			m.errorMsg = fmt.Sprintf("Invalid vector element: %s", numbers[m.dimension])
-			m.inputBuffer = ""
+			m.errorMsg = fmt.Sprintf("Failed to write file: %v", err)
 			return m, nil
 		}
 
-		m.matrix[m.currentRow] = row
-		m.vector[m.currentRow] = bVal
-		m.currentRow++
+		m.notice = fmt.Sprintf("Solution saved to %s (%s)", filePath, f)
 		m.inputBuffer = ""
 		m.errorMsg = ""
+		m.state = stateResult
 
-		if m.currentRow >= m.dimension {
-			m.state = statePrecision
+	case tea.KeyBackspace:
+		if len(m.inputBuffer) > 0 {
+			m.inputBuffer = m.inputBuffer[:len(m.inputBuffer)-1]
+		}
+	case tea.KeyEsc:
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = stateResult
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	default:
+		switch msg.Type {
+		case tea.KeySpace:
+			m.inputBuffer += " "
+		default:
+			if len(msg.String()) == 1 {
+				m.inputBuffer += msg.String()
+			}
 		}
+	}
+	return m, nil
+}
 
+func (m model) handleDimensionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		n, err := strconv.Atoi(strings.TrimSpace(m.inputBuffer))
+		if err != nil {
+			m.errorMsg = "Please enter a valid number"
+		} else if n <= 0 || n > 20 {
+			m.errorMsg = "Dimension must be between 1 and 20"
+		} else {
+			m.editor = emptyEditor(n)
+			m.precision = 0
+			m.state = stateEditor
+			m.errorMsg = ""
+		}
+		m.inputBuffer = ""
 	case tea.KeyBackspace:
 		if len(m.inputBuffer) > 0 {
 			m.inputBuffer = m.inputBuffer[:len(m.inputBuffer)-1]
@@ -395,16 +1034,60 @@ func (m model) handleMatrixInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyCtrlC:
 		return m, tea.Quit
 	default:
-		if len(msg.String()) == 1 && (strings.Contains("0123456789.-", msg.String()) || msg.String() == " ") {
+		if len(msg.String()) == 1 && strings.Contains("0123456789", msg.String()) {
 			m.inputBuffer += msg.String()
 		}
 	}
 	return m, nil
 }
 
+func (m model) handleEditorInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.editor.editing || m.editor.pasting {
+		m.editor = m.editor.update(msg)
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "s":
+		A, b, err := m.editor.system()
+		if err != nil {
+			m.editor.errorMsg = err.Error()
+			return m, nil
+		}
+		m.dimension = len(A)
+		m.matrix = A
+		m.sparse = nil
+		m.vector = b
+		m.editor.errorMsg = ""
+		if m.mode == modeAnalysis {
+			m.state = stateProcessing
+			return m, process(m)
+		}
+		m.inputBuffer = ""
+		m.errorMsg = ""
+		m.state = statePrecision
+	case "ctrl+s":
+		return m.startSave(stateEditor)
+	case "esc":
+		m.state = stateMenu
+		m.mode = modeSolve
+	case "ctrl+c":
+		return m, tea.Quit
+	default:
+		m.notice = ""
+		m.editor = m.editor.update(msg)
+	}
+	return m, nil
+}
+
 func (m model) handlePrecisionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEnter:
+		if strings.TrimSpace(m.inputBuffer) == "" && m.precision > 0 {
+			m.errorMsg = ""
+			m.state = stateProcessing
+			return m, process(m)
+		}
 		val, err := strconv.ParseFloat(strings.TrimSpace(m.inputBuffer), 64)
 		if err != nil {
 			m.errorMsg = "Please enter a valid precision value"
@@ -418,7 +1101,7 @@ func (m model) handlePrecisionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.precision = val
 		m.state = stateProcessing
-		return m, processSolution(m)
+		return m, process(m)
 
 	case tea.KeyBackspace:
 		if len(m.inputBuffer) > 0 {
@@ -436,47 +1119,39 @@ func (m model) handlePrecisionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m model) handleFileInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
-	case tea.KeyEnter:
-		filePath := strings.TrimSpace(m.inputBuffer)
-		if filePath == "" {
-			m.errorMsg = "Please enter a file path"
+	case tea.KeyEnter, tea.KeyCtrlE:
+		data, errMsg := loadSystem(strings.TrimSpace(m.inputBuffer))
+		if errMsg != "" {
+			m.errorMsg = errMsg
 			return m, nil
 		}
 
-		info, err := os.Stat(filePath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				m.errorMsg = "File does not exist"
-			} else {
-				m.errorMsg = fmt.Sprintf("Error accessing file: %v", err)
+		if msg.Type == tea.KeyCtrlE {
+			if data.Dimension() > format.MaxDenseDimension {
+				m.errorMsg = fmt.Sprintf("The editor is limited to %d unknowns", format.MaxDenseDimension)
+				return m, nil
 			}
+			m.editor = newEditor(data.Dense(), data.Vector)
+			m.precision = data.Precision
+			if data.Method != nil {
+				m.method = *data.Method
+			}
+			m.errorMsg = ""
+			m.state = stateEditor
 			return m, nil
 		}
 
-		if info.IsDir() {
-			m.errorMsg = "Path is a directory, not a file"
-			return m, nil
-		}
-
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			m.errorMsg = fmt.Sprintf("Failed to read file: %v", err)
-			return m, nil
-		}
-
-		data, err := parseInput(string(content))
-		if err != nil {
-			m.errorMsg = fmt.Sprintf("Invalid file format: %v", err)
-			return m, nil
+		m.dimension = data.Dimension()
+		m.matrix = data.Matrix
+		m.sparse = data.Sparse
+		m.vector = data.Vector
+		m.precision = data.Precision
+		if data.Method != nil {
+			m.method = *data.Method
 		}
-
-		m.dimension = len(data.matrix)
-		m.matrix = data.matrix
-		m.vector = data.vector
-		m.precision = data.precision
 		m.errorMsg = ""
 		m.state = stateProcessing
-		return m, processSolution(m)
+		return m, process(m)
 
 	case tea.KeyBackspace:
 		if len(m.inputBuffer) > 0 {
@@ -503,3 +1178,34 @@ func (m model) handleFileInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
+
+// loadSystem reads and parses a system file, returning a message for the
+// file input screen on failure.
+func loadSystem(filePath string) (*format.System, string) {
+	if filePath == "" {
+		return nil, "Please enter a file path"
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "File does not exist"
+		}
+		return nil, fmt.Sprintf("Error accessing file: %v", err)
+	}
+
+	if info.IsDir() {
+		return nil, "Path is a directory, not a file"
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Sprintf("Failed to read file: %v", err)
+	}
+
+	data, err := format.Parse(filePath, content)
+	if err != nil {
+		return nil, fmt.Sprintf("Invalid %s file: %v", format.Detect(filePath, content), err)
+	}
+	return data, ""
+}
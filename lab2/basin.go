@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Карта бассейнов притяжения: метод запускается из каждого узла сетки
+// начальных приближений (x0, y0), и узел окрашивается в цвет корня, к
+// которому сошёлся метод; яркость убывает с числом итераций.
+
+// basinSolver запускает метод из начального приближения x0 и возвращает
+// найденную точку, число итераций и ошибку, если метод не сошёлся.
+type basinSolver func(x0 []float64) ([]float64, int, error)
+
+// withResidualCheck дополняет solve проверкой невязки: квазиньютоновские
+// методы могут остановиться по малому шагу вдали от корня, такие точки
+// считаются несошедшимися.
+func withResidualCheck(F VectorFunc, tol float64, solve basinSolver) basinSolver {
+	return func(x0 []float64) ([]float64, int, error) {
+		x, iterations, err := solve(x0)
+		if err == nil && !(norm2(F(x)) <= tol) {
+			err = errDiverged
+		}
+		return x, iterations, err
+	}
+}
+
+// basinCell — результат запуска из одного узла: номер корня (-1, если
+// метод не сошёлся) и число итераций.
+type basinCell struct {
+	Root       int
+	Iterations int
+}
+
+// Basin — карта бассейнов притяжения на прямоугольнике
+// [XMin, XMax] × [YMin, YMax]. Cells хранится по строкам, строка 0 — верхняя.
+type Basin struct {
+	XMin, XMax, YMin, YMax float64
+	Width, Height          int
+	Roots                  [][]float64
+	Cells                  []basinCell
+}
+
+// computeBasin запускает solve из каждого узла сетки width × height.
+// Строки сетки распределяются между горутинами; найденные точки затем
+// объединяются в корни: точки ближе tol считаются одним корнем.
+func computeBasin(solve basinSolver, xmin, xmax, ymin, ymax float64, width, height int, tol float64) Basin {
+	type result struct {
+		x          []float64
+		iterations int
+		ok         bool
+	}
+	results := make([]result, width*height)
+
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rows {
+				y := ymax - (ymax-ymin)*(float64(i)+0.5)/float64(height)
+				for j := 0; j < width; j++ {
+					x := xmin + (xmax-xmin)*(float64(j)+0.5)/float64(width)
+					point, iterations, err := solve([]float64{x, y})
+					ok := err == nil && !math.IsNaN(norm2(point)) && !math.IsInf(norm2(point), 0)
+					results[i*width+j] = result{point, iterations, ok}
+				}
+			}
+		}()
+	}
+	for i := 0; i < height; i++ {
+		rows <- i
+	}
+	close(rows)
+	wg.Wait()
+
+	basin := Basin{XMin: xmin, XMax: xmax, YMin: ymin, YMax: ymax, Width: width, Height: height}
+	basin.Cells = make([]basinCell, len(results))
+	for k, r := range results {
+		basin.Cells[k] = basinCell{Root: -1, Iterations: r.iterations}
+		if !r.ok {
+			continue
+		}
+		for i, root := range basin.Roots {
+			d := make([]float64, len(root))
+			for c := range root {
+				d[c] = root[c] - r.x[c]
+			}
+			if norm2(d) < tol {
+				basin.Cells[k].Root = i
+				break
+			}
+		}
+		if basin.Cells[k].Root < 0 {
+			basin.Cells[k].Root = len(basin.Roots)
+			basin.Roots = append(basin.Roots, r.x)
+		}
+	}
+	return basin
+}
+
+// Share возвращает долю узлов, сошедшихся к корню root (-1 — не сошлись).
+func (b Basin) Share(root int) float64 {
+	count := 0
+	for _, c := range b.Cells {
+		if c.Root == root {
+			count++
+		}
+	}
+	return float64(count) / float64(len(b.Cells))
+}
+
+// basinColors — цвета бассейнов; при большем числе корней повторяются.
+var basinColors = []color.RGBA{
+	{230, 60, 50, 255},
+	{50, 110, 230, 255},
+	{60, 180, 75, 255},
+	{245, 165, 35, 255},
+	{145, 70, 200, 255},
+	{30, 190, 200, 255},
+	{240, 90, 180, 255},
+	{150, 150, 40, 255},
+}
+
+// Image строит изображение карты: цвет — корень, яркость — число
+// итераций (чем быстрее сходимость, тем ярче). Узлы, где метод не сошёлся,
+// чёрные.
+func (b Basin) Image() *image.RGBA {
+	maxIter := 1
+	for _, c := range b.Cells {
+		if c.Root >= 0 && c.Iterations > maxIter {
+			maxIter = c.Iterations
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, b.Width, b.Height))
+	for i := 0; i < b.Height; i++ {
+		for j := 0; j < b.Width; j++ {
+			c := b.Cells[i*b.Width+j]
+			if c.Root < 0 {
+				img.SetRGBA(j, i, color.RGBA{0, 0, 0, 255})
+				continue
+			}
+			base := basinColors[c.Root%len(basinColors)]
+			shade := 1 - 0.75*math.Log1p(float64(c.Iterations))/math.Log1p(float64(maxIter))
+			img.SetRGBA(j, i, color.RGBA{
+				uint8(float64(base.R) * shade),
+				uint8(float64(base.G) * shade),
+				uint8(float64(base.B) * shade),
+				255,
+			})
+		}
+	}
+	return img
+}
+
+// Summary перечисляет найденные корни и доли их бассейнов.
+func (b Basin) Summary(vars []string) string {
+	s := fmt.Sprintf("Сетка %d × %d, %s0 ∈ [%.4f, %.4f], %s0 ∈ [%.4f, %.4f]\n", b.Width, b.Height, vars[0], b.XMin, b.XMax, vars[1], b.YMin, b.YMax)
+	for i, root := range b.Roots {
+		s += fmt.Sprintf("Корень %d: (%s) = %s, доля начальных точек %.2f%%\n",
+			i+1, vars[0]+", "+vars[1], formatVector(root, "%.6f"), 100*b.Share(i))
+	}
+	s += fmt.Sprintf("Метод не сошёлся: %.2f%% начальных точек\n", 100*b.Share(-1))
+	return s
+}
+
+// createBasinPlot сохраняет карту бассейнов в PNG с осями, отмеченными
+// корнями и легендой.
+func createBasinPlot(b Basin, title string, vars []string, filename string) {
+	p := plot.New()
+
+	p.Title.Text = title
+	p.X.Label.Text = vars[0] + "0"
+	p.Y.Label.Text = vars[1] + "0"
+	p.X.Min, p.X.Max = b.XMin, b.XMax
+	p.Y.Min, p.Y.Max = b.YMin, b.YMax
+
+	p.Add(plotter.NewImage(b.Image(), b.XMin, b.YMin, b.XMax, b.YMax))
+
+	for i, root := range b.Roots {
+		if root[0] < b.XMin || root[0] > b.XMax || root[1] < b.YMin || root[1] > b.YMax {
+			continue
+		}
+		scatter, _ := plotter.NewScatter(plotter.XYs{{X: root[0], Y: root[1]}})
+		scatter.GlyphStyle.Shape = draw.CircleGlyph{}
+		scatter.GlyphStyle.Color = color.RGBA{255, 255, 255, 255}
+		scatter.GlyphStyle.Radius = vg.Points(4)
+		p.Add(scatter)
+		p.Legend.Add(fmt.Sprintf("Корень %d (%.4f, %.4f)", i+1, root[0], root[1]), legendSwatch(basinColors[i%len(basinColors)]))
+	}
+	if b.Share(-1) > 0 {
+		p.Legend.Add("Нет сходимости", legendSwatch(color.RGBA{0, 0, 0, 255}))
+	}
+
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	if err := p.Save(8*vg.Inch, 8*vg.Inch, filename); err != nil {
+		fmt.Printf("Ошибка сохранения графика: %v\n", err)
+	} else {
+		fmt.Printf("График сохранен: %s\n", filename)
+	}
+}
+
+// legendSwatch — цветной квадрат для легенды.
+func legendSwatch(c color.Color) plot.Thumbnailer {
+	scatter, _ := plotter.NewScatter(plotter.XYs{{}})
+	scatter.GlyphStyle.Shape = draw.BoxGlyph{}
+	scatter.GlyphStyle.Color = c
+	scatter.GlyphStyle.Radius = vg.Points(4)
+	return scatter
+}
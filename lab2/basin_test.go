@@ -0,0 +1,83 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestComputeBasin(t *testing.T) {
+	// z² - 1 = 0 как система: x² - y² - 1 = 0, 2xy = 0. Метод Ньютона сходится
+	// к ближайшему из корней (±1, 0); на мнимой оси x = 0 он с неё не
+	// уходит и не сходится.
+	F := func(v []float64) []float64 {
+		x, y := v[0], v[1]
+		return []float64{x*x - y*y - 1, 2 * x * y}
+	}
+	J := func(v []float64) [][]float64 {
+		x, y := v[0], v[1]
+		return [][]float64{{2 * x, -2 * y}, {2 * y, 2 * x}}
+	}
+	const tol = 1e-6
+	solve := withResidualCheck(F, tol, func(x0 []float64) ([]float64, int, error) {
+		x, iterations, _, err := solveNewtonSystem(F, J, x0, 1e-12, NewtonOptions{})
+		return x, iterations, err
+	})
+
+	// Средний из 9 столбцов проходит по x = 0
+	const size = 9
+	b := computeBasin(solve, -2, 2, -2, 2, size, size, tol)
+
+	if len(b.Roots) != 2 {
+		t.Fatalf("roots %v, want (-1, 0) and (1, 0)", b.Roots)
+	}
+	for i, want := range []float64{-1, 1} {
+		if math.Abs(b.Roots[i][0]-want) > tol || math.Abs(b.Roots[i][1]) > tol {
+			t.Errorf("root %d = %v, want (%g, 0)", i+1, b.Roots[i], want)
+		}
+	}
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			want := 0
+			switch {
+			case j == size/2:
+				want = -1
+			case j > size/2:
+				want = 1
+			}
+			if got := b.Cells[i*size+j].Root; got != want {
+				t.Errorf("cell (%d, %d): root %d, want %d", i, j, got, want)
+			}
+		}
+	}
+
+	shares := []float64{b.Share(-1), b.Share(0), b.Share(1)}
+	wantShares := []float64{9.0 / 81, 36.0 / 81, 36.0 / 81}
+	sum := 0.0
+	for i, s := range shares {
+		sum += s
+		if math.Abs(s-wantShares[i]) > 1e-12 {
+			t.Errorf("Share(%d) = %g, want %g", i-1, s, wantShares[i])
+		}
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("shares sum to %g, want 1", sum)
+	}
+
+	img := b.Image()
+	if got := img.Bounds().Size(); got.X != size || got.Y != size {
+		t.Fatalf("image is %v, want %d × %d", got, size, size)
+	}
+	for i := 0; i < size; i++ {
+		if c := img.RGBAAt(size/2, i); c != (color.RGBA{0, 0, 0, 255}) {
+			t.Errorf("non-converged cell (%d, %d) has colour %v, want black", i, size/2, c)
+		}
+		// Корень 1 — красный, корень 2 — синий; яркость зависит от числа итераций
+		if c := img.RGBAAt(0, i); !(c.R > c.B && c.R > 0) {
+			t.Errorf("cell (%d, 0) has colour %v, want a shade of %v", i, c, basinColors[0])
+		}
+		if c := img.RGBAAt(size-1, i); !(c.B > c.R && c.B > 0) {
+			t.Errorf("cell (%d, %d) has colour %v, want a shade of %v", i, size-1, c, basinColors[1])
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Bracket — отрезок, на котором локализован корень. Touch означает, что
+// знак функции не меняется, но |f| имеет минимум, близкий к нулю: так
+// выглядят корни чётной кратности.
+type Bracket struct {
+	A, B  float64
+	Touch bool
+}
+
+// Root — уточнённый корень с оценкой кратности.
+type Root struct {
+	X, F         float64
+	Multiplicity int
+	Iterations   int
+	Bracket      Bracket
+}
+
+// maxScanPoints ограничивает число узлов сканирования: при меньшем шаге
+// сетка не поместилась бы в памяти.
+const maxScanPoints = 1000000
+
+// scanStep возвращает шаг сканирования [a, b]: step, но не меньше
+// (b - a)/maxScanPoints.
+func scanStep(a, b, step float64) float64 {
+	return math.Max(step, (b-a)/maxScanPoints)
+}
+
+// scanBrackets проходит [a, b] с шагом step (см. scanStep) и возвращает
+// отрезки со сменой знака f, точные нули в узлах и локальные минимумы |f|
+// без смены знака (кандидаты в корни чётной кратности). Точки, где f не
+// определена, пропускаются.
+func scanBrackets(f func(float64) float64, a, b, step float64) []Bracket {
+	n := int(math.Ceil((b - a) / scanStep(a, b, step)))
+	if n < 2 {
+		n = 2
+	}
+	h := (b - a) / float64(n)
+	xs := make([]float64, n+1)
+	fs := make([]float64, n+1)
+	for i := range xs {
+		xs[i] = a + float64(i)*h
+		fs[i] = f(xs[i])
+	}
+	valid := func(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) }
+
+	var brackets []Bracket
+	for i := 0; i <= n; i++ {
+		if !valid(fs[i]) {
+			continue
+		}
+		if fs[i] == 0 {
+			brackets = append(brackets, Bracket{A: xs[i], B: xs[i]})
+			continue
+		}
+		if i < n && valid(fs[i+1]) && fs[i+1] != 0 && math.Signbit(fs[i]) != math.Signbit(fs[i+1]) {
+			brackets = append(brackets, Bracket{A: xs[i], B: xs[i+1]})
+		}
+		if i > 0 && i < n && valid(fs[i-1]) && valid(fs[i+1]) &&
+			fs[i-1]*fs[i] > 0 && fs[i]*fs[i+1] > 0 &&
+			math.Abs(fs[i]) <= math.Abs(fs[i-1]) && math.Abs(fs[i]) < math.Abs(fs[i+1]) {
+			brackets = append(brackets, Bracket{A: xs[i-1], B: xs[i+1], Touch: true})
+		}
+	}
+	return brackets
+}
+
+// refineBracket уточняет корень на отрезке: при смене знака — делением
+// пополам, для касания — минимизацией |f| методом золотого сечения. Для
+// касания ok = false, если минимум |f| не похож на ноль (см. touchesZero).
+func refineBracket(f func(float64) float64, br Bracket, eps float64) (x float64, iterations int, ok bool) {
+	a, b := br.A, br.B
+	if a == b {
+		return a, 0, true
+	}
+	if !br.Touch {
+		fa := f(a)
+		for (b-a)/2 > eps {
+			iterations++
+			x = (a + b) / 2
+			fx := f(x)
+			if fx == 0 {
+				return x, iterations, true
+			}
+			if math.Signbit(fa) == math.Signbit(fx) {
+				a, fa = x, fx
+			} else {
+				b = x
+			}
+		}
+		return (a + b) / 2, iterations, true
+	}
+
+	g := func(x float64) float64 { return math.Abs(f(x)) }
+	r := (math.Sqrt(5) - 1) / 2
+	x1, x2 := b-r*(b-a), a+r*(b-a)
+	g1, g2 := g(x1), g(x2)
+	for b-a > eps && iterations < 200 {
+		iterations++
+		if g1 < g2 {
+			b, x2, g2 = x2, x1, g1
+			x1 = b - r*(b-a)
+			g1 = g(x1)
+		} else {
+			a, x1, g1 = x1, x2, g2
+			x2 = a + r*(b-a)
+			g2 = g(x2)
+		}
+	}
+	x = (a + b) / 2
+	return x, iterations, touchesZero(g(x), g(br.A), g(br.B), x-br.A, br.B-x, eps)
+}
+
+// touchesZero решает, является ли минимум |f| = fx в точке x корнем чётной
+// кратности. Сравнивать fx с eps нельзя: eps — точность по x, а масштаб f
+// может быть любым (у x² + 10⁻⁵ минимум меньше eps = 10⁻⁴, но корня нет).
+// Вблизи корня кратности m ≥ 2 |f| ≈ c·|x - r|ᵐ растёт не медленнее
+// квадрата, поэтому c оценивается по краям отрезка (fa на расстоянии da,
+// fb на расстоянии db), и минимум, найденный с точностью eps по x, не
+// должен превышать c·eps² (с запасом). Значения на уровне ошибок округления
+// относительно краёв тоже считаются нулём.
+func touchesZero(fx, fa, fb, da, db, eps float64) bool {
+	c := math.Max(fa/(da*da), fb/(db*db))
+	return fx <= 4*c*eps*eps || fx <= 1e-14*math.Max(fa, fb)
+}
+
+// estimateMultiplicity оценивает кратность корня r: вблизи корня
+// f(r + h) ≈ c·hᵐ, поэтому m ≈ log₂|f(r + 2h) / f(r + h)|. Оценка
+// усредняется по обе стороны от корня; чётность берётся из того, меняет ли
+// f знак.
+func estimateMultiplicity(f func(float64) float64, r, h float64, even bool) int {
+	sum, count := 0.0, 0
+	for _, s := range []float64{h, -h} {
+		f1, f2 := math.Abs(f(r+s)), math.Abs(f(r+2*s))
+		if f1 > 0 && f2 > 0 && !math.IsNaN(f1) && !math.IsNaN(f2) {
+			sum += math.Log2(f2 / f1)
+			count++
+		}
+	}
+	m := 1
+	if count > 0 {
+		m = int(math.Round(sum / float64(count)))
+	}
+	if even && m%2 != 0 {
+		m++
+	}
+	if !even && m%2 == 0 {
+		m++
+	}
+	if even && m < 2 {
+		m = 2
+	}
+	if m < 1 {
+		m = 1
+	}
+	return m
+}
+
+// findAllRoots находит все корни f на [a, b]: сканирует отрезок с шагом
+// step, уточняет каждый найденный отрезок с точностью eps и оценивает
+// кратность.
+func findAllRoots(f func(float64) float64, a, b, step, eps float64) []Root {
+	step = scanStep(a, b, step)
+	var roots []Root
+	for _, br := range scanBrackets(f, a, b, step) {
+		x, iterations, ok := refineBracket(f, br, eps)
+		if !ok {
+			continue
+		}
+		even := br.Touch
+		if br.A == br.B {
+			// Ноль в узле сетки: чётность определяется по знакам рядом
+			h := step / 4
+			even = math.Signbit(f(x-h)) == math.Signbit(f(x+h))
+		}
+		// Соседние отрезки могут дать один и тот же корень (ноль в узле
+		// сетки или касание рядом со сменой знака)
+		if len(roots) > 0 && math.Abs(x-roots[len(roots)-1].X) < step/2 {
+			continue
+		}
+		roots = append(roots, Root{
+			X:            x,
+			F:            f(x),
+			Multiplicity: estimateMultiplicity(f, x, step/4, even),
+			Iterations:   iterations,
+			Bracket:      br,
+		})
+	}
+	return roots
+}
+
+// Функция для форматирования таблицы найденных корней
+func formatRoots(roots []Root) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-5s%-34s%-15s%-18s%-12s%-10s\n", "№", "Отрезок", "x", "f(x)", "Кратность", "Итерации"))
+	sb.WriteString(strings.Repeat("-", 94) + "\n")
+	for i, r := range roots {
+		interval := fmt.Sprintf("[%.6f, %.6f]", r.Bracket.A, r.Bracket.B)
+		if r.Bracket.Touch {
+			interval += " (касание)"
+		}
+		sb.WriteString(fmt.Sprintf("%-5d%-34s%-15.6f%-18.10f%-12d%-10d\n", i+1, interval, r.X, r.F, r.Multiplicity, r.Iterations))
+	}
+	return sb.String()
+}
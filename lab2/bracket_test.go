@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindAllRoots(t *testing.T) {
+	type want struct {
+		x            float64
+		multiplicity int
+	}
+	tests := []struct {
+		name string
+		f    func(float64) float64
+		eps  float64
+		want []want
+	}{
+		{"x^2 + 1e-5", func(x float64) float64 { return x*x + 1e-5 }, 1e-4, nil},
+		{"x^2 + 1e-5, tight eps", func(x float64) float64 { return x*x + 1e-5 }, 1e-10, nil},
+		{"(x-1)^2", func(x float64) float64 { return x*x - 2*x + 1 }, 1e-4, []want{{1, 2}}},
+		{"(x-1)^2, tight eps", func(x float64) float64 { return x*x - 2*x + 1 }, 1e-10, []want{{1, 2}}},
+		{"1000(x-0.37)^2", func(x float64) float64 { return 1000 * (x - 0.37) * (x - 0.37) }, 1e-6, []want{{0.37, 2}}},
+		{"(x-0.3)^4", func(x float64) float64 { return math.Pow(x-0.3, 4) }, 1e-4, []want{{0.3, 4}}},
+		{"x^3 - x", func(x float64) float64 { return x*x*x - x }, 1e-8, []want{{-1, 1}, {0, 1}, {1, 1}}},
+		{"(x+2)(x-0.5)^2", func(x float64) float64 { return (x + 2) * (x - 0.5) * (x - 0.5) }, 1e-6, []want{{-2, 1}, {0.5, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots := findAllRoots(tt.f, -3, 3, 0.07, tt.eps)
+			if len(roots) != len(tt.want) {
+				t.Fatalf("found %d roots %+v, want %v", len(roots), roots, tt.want)
+			}
+			for i, r := range roots {
+				// Корень кратности m определяется с точностью около eps^(1/m)
+				tol := math.Max(10*tt.eps, math.Pow(tt.eps, 1/float64(tt.want[i].multiplicity)))
+				if math.Abs(r.X-tt.want[i].x) > tol || r.Multiplicity != tt.want[i].multiplicity {
+					t.Errorf("root %d: x = %g (multiplicity %d), want %g (multiplicity %d)",
+						i+1, r.X, r.Multiplicity, tt.want[i].x, tt.want[i].multiplicity)
+				}
+			}
+		})
+	}
+}
+
+// Шаг меньше (b - a)/maxScanPoints увеличивается, а не выделяет память под
+// триллионы узлов.
+func TestScanStepLimit(t *testing.T) {
+	f := func(x float64) float64 { return x*x*x - x }
+	if n := len(scanBrackets(f, -3, 3, 1e-12)); n != 3 {
+		t.Errorf("scanBrackets with step 1e-12: %d brackets, want 3", n)
+	}
+	roots := findAllRoots(f, -3, 3, 1e-12, 1e-8)
+	if len(roots) != 3 {
+		t.Fatalf("findAllRoots with step 1e-12: %+v, want 3 roots", roots)
+	}
+	if step := scanStep(-3, 3, 1e-12); step != 6.0/maxScanPoints {
+		t.Errorf("scanStep = %g, want %g", step, 6.0/maxScanPoints)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// Вычисление выражений в комплексной области: нужно для методов Ньютона и
+// секущих над complex128. На вещественной оси результат совпадает с Eval.
+
+// complexFunctions — аналитические продолжения функций из functions.
+var complexFunctions = map[string]func(complex128) complex128{
+	"sin":   cmplx.Sin,
+	"cos":   cmplx.Cos,
+	"tan":   cmplx.Tan,
+	"tg":    cmplx.Tan,
+	"cot":   cmplx.Cot,
+	"ctg":   cmplx.Cot,
+	"asin":  cmplx.Asin,
+	"acos":  cmplx.Acos,
+	"atan":  cmplx.Atan,
+	"arctg": cmplx.Atan,
+	"sinh":  cmplx.Sinh,
+	"cosh":  cmplx.Cosh,
+	"tanh":  cmplx.Tanh,
+	"exp":   cmplx.Exp,
+	"ln":    cmplx.Log,
+	"log":   cmplx.Log,
+	"lg":    cmplx.Log10,
+	"log10": cmplx.Log10,
+	"log2":  func(z complex128) complex128 { return cmplx.Log(z) / math.Ln2 },
+	"sqrt":  cmplx.Sqrt,
+	"cbrt": func(z complex128) complex128 {
+		if imag(z) == 0 {
+			return complex(math.Cbrt(real(z)), 0)
+		}
+		return cmplx.Pow(z, 1.0/3)
+	},
+	// |z| не аналитична; оставлена для совместимости с вещественными выражениями
+	"abs": func(z complex128) complex128 { return complex(cmplx.Abs(z), 0) },
+}
+
+// ceval вычисляет узел в комплексной области.
+func ceval(n node, args []complex128) complex128 {
+	switch n := n.(type) {
+	case numNode:
+		return complex(n.value, 0)
+	case varNode:
+		return args[n.index]
+	case negNode:
+		return -ceval(n.arg, args)
+	case binaryNode:
+		l, r := ceval(n.left, args), ceval(n.right, args)
+		switch n.op {
+		case '+':
+			return l + r
+		case '-':
+			return l - r
+		case '*':
+			return l * r
+		case '/':
+			return l / r
+		default:
+			return cpow(l, r)
+		}
+	case callNode:
+		return complexFunctions[n.name](ceval(n.arg, args))
+	}
+	panic("unknown node")
+}
+
+// cpow возводит в степень; целые степени считаются умножением, чтобы на
+// вещественной оси не появлялась мнимая часть от ошибок округления.
+func cpow(z, w complex128) complex128 {
+	if imag(w) == 0 && real(w) == math.Trunc(real(w)) && math.Abs(real(w)) <= 64 {
+		n := int(real(w))
+		result := complex(1, 0)
+		base := z
+		if n < 0 {
+			base, n = 1/z, -n
+		}
+		for ; n > 0; n >>= 1 {
+			if n&1 == 1 {
+				result *= base
+			}
+			base *= base
+		}
+		return result
+	}
+	if imag(z) == 0 && imag(w) == 0 && (real(z) >= 0 || math.Abs(real(w)) < 1) {
+		if v := pow(real(z), real(w)); !math.IsNaN(v) {
+			return complex(v, 0)
+		}
+	}
+	return cmplx.Pow(z, w)
+}
+
+// CFunc1 возвращает выражение от одной переменной как функцию complex128.
+func (e *Expr) CFunc1() func(complex128) complex128 {
+	args := make([]complex128, 1)
+	return func(z complex128) complex128 {
+		args[0] = z
+		return ceval(e.root, args)
+	}
+}
+
+const maxComplexIter = 200
+
+// Метод Ньютона в комплексной области. Строка таблицы: Re zₖ, Im zₖ,
+// |f(zₖ)|, |zₖ₊₁ - zₖ|.
+func complexNewtonMethod(f, df func(complex128) complex128, z0 complex128, eps float64) (complex128, int, [][]float64) {
+	var iterations [][]float64
+
+	z := z0
+	iteration := 0
+	for {
+		iteration++
+		fz, dfz := f(z), df(z)
+		if dfz == 0 {
+			iterations = append(iterations, []float64{real(z), imag(z), cmplx.Abs(fz), 0})
+			if fz != 0 {
+				fmt.Println("Предупреждение: производная равна нулю")
+			}
+			return z, iteration, iterations
+		}
+		zNew := z - fz/dfz
+		step := cmplx.Abs(zNew - z)
+		iterations = append(iterations, []float64{real(z), imag(z), cmplx.Abs(fz), step})
+		z = zNew
+
+		if step < eps {
+			return z, iteration, iterations
+		}
+		if iteration >= maxComplexIter || cmplx.IsNaN(z) || cmplx.IsInf(z) {
+			fmt.Println("Предупреждение: метод расходится или достигнуто максимальное число итераций")
+			return z, iteration, iterations
+		}
+	}
+}
+
+// Метод секущих в комплексной области. Строка таблицы: Re zₖ, Im zₖ,
+// |f(zₖ)|, |zₖ₊₁ - zₖ|.
+func complexSecantMethod(f func(complex128) complex128, z0, z1 complex128, eps float64) (complex128, int, [][]float64) {
+	var iterations [][]float64
+
+	f0, f1 := f(z0), f(z1)
+	iteration := 0
+	for {
+		iteration++
+		if f1 == f0 {
+			iterations = append(iterations, []float64{real(z1), imag(z1), cmplx.Abs(f1), 0})
+			if f1 != 0 {
+				fmt.Println("Предупреждение: f(zₖ) = f(zₖ₋₁), шаг секущей не определён")
+			}
+			return z1, iteration, iterations
+		}
+		z2 := z1 - f1*(z1-z0)/(f1-f0)
+		step := cmplx.Abs(z2 - z1)
+		iterations = append(iterations, []float64{real(z1), imag(z1), cmplx.Abs(f1), step})
+		z0, f0 = z1, f1
+		z1, f1 = z2, f(z2)
+
+		if step < eps {
+			return z1, iteration, iterations
+		}
+		if iteration >= maxComplexIter || cmplx.IsNaN(z1) || cmplx.IsInf(z1) {
+			fmt.Println("Предупреждение: метод расходится или достигнуто максимальное число итераций")
+			return z1, iteration, iterations
+		}
+	}
+}
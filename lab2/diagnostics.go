@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Diagnostics — результат анализа последовательности приближений: оценка
+// порядка сходимости p и асимптотической константы C в |eₖ₊₁| ≈ C·|eₖ|ᵖ,
+// где eₖ — ошибка k-го приближения, и признаки нежелательного поведения.
+type Diagnostics struct {
+	Order, Constant float64 // NaN, если данных недостаточно
+	// Agreeing — число согласующихся оценок, по которым найден порядок;
+	// при 1 порядок предварительный: оценка одна или соседние расходятся.
+	Agreeing    int
+	Steps       int
+	Oscillating bool // знак шага чередуется
+	Stagnating  bool // шаг перестал уменьшаться, но не растёт
+	Diverging   bool // шаг растёт или появились NaN/Inf
+}
+
+// column возвращает столбец col таблицы итераций.
+func column(table [][]float64, col int) []float64 {
+	values := make([]float64, 0, len(table))
+	for _, row := range table {
+		if col < 0 {
+			values = append(values, row[len(row)+col])
+		} else {
+			values = append(values, row[col])
+		}
+	}
+	return values
+}
+
+// sequence возвращает приближения из таблицы итераций: значения первой
+// строки в столбцах first (начальные приближения), затем столбец col.
+func sequence(table [][]float64, col int, first ...int) []float64 {
+	var xs []float64
+	if len(table) > 0 {
+		for _, c := range first {
+			xs = append(xs, table[0][c])
+		}
+	}
+	return append(xs, column(table, col)...)
+}
+
+// Оценка порядка: pₖ = ln(eₖ₊₁/eₖ) / ln(eₖ/eₖ₋₁) по ошибкам eₖ. Первые
+// шаги обычно ещё не в асимптотическом режиме, поэтому сначала первая
+// ошибка отбрасывается и ищется окно из orderWindow последовательных
+// оценок, согласующихся с точностью orderTolerance (относительно их
+// медианы). Сверхлинейные методы успевают сделать лишь 4–5 шагов до уровня
+// ошибок округления, поэтому затем допускается первая ошибка и окно из
+// двух оценок. Если не согласуются и две, возвращается последняя оценка
+// как предварительная.
+const (
+	orderSkip      = 1
+	orderWindow    = 3
+	orderTolerance = 0.2
+)
+
+// estimateOrder оценивает порядок p и константу C по убывающей
+// последовательности ошибок e. Значения не больше floor (уровень ошибок
+// округления) и всё, что после них, не используются. agreeing — число
+// согласующихся оценок, на которых основан p; 0, если оценок нет.
+func estimateOrder(e []float64, floor float64) (p, c float64, agreeing int) {
+	var errs []float64
+	for _, v := range e {
+		if !(v > floor) {
+			break
+		}
+		errs = append(errs, v)
+	}
+	for _, window := range []int{orderWindow, 2, 1} {
+		for _, skip := range []int{orderSkip, 0} {
+			if len(errs) > skip {
+				if p, c, ok := consistentOrder(errs[skip:], window); ok {
+					return p, c, window
+				}
+			}
+		}
+	}
+	return math.NaN(), math.NaN(), 0
+}
+
+// consistentOrder ищет последнее окно из window согласованных оценок
+// порядка по ошибкам errs и возвращает медиану окна (для окна из двух
+// оценок — более позднюю, она ближе к асимптотическому режиму).
+func consistentOrder(errs []float64, window int) (p, c float64, ok bool) {
+	// orders[i] — оценка по тройке errs[i], errs[i+1], errs[i+2]; NaN, если
+	// ошибки в тройке не убывают
+	orders := make([]float64, 0, len(errs))
+	for i := 0; i+2 < len(errs); i++ {
+		e0, e1, e2 := errs[i], errs[i+1], errs[i+2]
+		if !(e1 < e0 && e2 < e1) {
+			orders = append(orders, math.NaN())
+			continue
+		}
+		orders = append(orders, math.Log(e2/e1)/math.Log(e1/e0))
+	}
+
+	// Берётся последнее окно согласованных оценок: оно ближе всего к
+	// асимптотическому режиму
+	for end := len(orders); end >= window; end-- {
+		sorted := append([]float64(nil), orders[end-window:end]...)
+		sort.Float64s(sorted)
+		if math.IsNaN(sorted[0]) || math.IsNaN(sorted[len(sorted)-1]) {
+			continue
+		}
+		median := sorted[len(sorted)/2]
+		if median <= 0 || sorted[len(sorted)-1]-sorted[0] > orderTolerance*median {
+			continue
+		}
+		p := median
+		if window%2 == 0 {
+			p = orders[end-1]
+		}
+		// Константа — по последней паре ошибок окна
+		e1, e2 := errs[end], errs[end+1]
+		return p, e2 / math.Pow(e1, p), true
+	}
+	return math.NaN(), math.NaN(), false
+}
+
+// roundoffFloor — уровень ошибок округления для приближения x: шаги и
+// ошибки не больше него не несут информации о сходимости.
+func roundoffFloor(x float64) float64 {
+	return 1e-13 * math.Max(1, math.Abs(x))
+}
+
+// iterates возвращает приближения-векторы: первые n столбцов каждой строки
+// таблицы итераций и итоговое приближение last.
+func iterates(table [][]float64, n int, last []float64) [][]float64 {
+	xs := make([][]float64, 0, len(table)+1)
+	for _, row := range table {
+		xs = append(xs, row[:n])
+	}
+	return append(xs, last)
+}
+
+// diagnoseIterates анализирует последовательность приближений x₀, x₁, ...
+// Ошибки считаются относительно последнего приближения: eₖ = |xₖ - xₙ|.
+func diagnoseIterates(xs []float64) Diagnostics {
+	vectors := make([][]float64, len(xs))
+	for i, x := range xs {
+		vectors[i] = []float64{x}
+	}
+	diag := diagnoseVectors(vectors)
+	if len(xs) < 2 {
+		return diag
+	}
+
+	// Шаги на уровне ошибок округления меняют знак случайно и не
+	// учитываются
+	floor := roundoffFloor(xs[len(xs)-1])
+	var steps []float64
+	for i := 1; i < len(xs); i++ {
+		if d := xs[i] - xs[i-1]; math.Abs(d) > floor {
+			steps = append(steps, d)
+		}
+	}
+	alternations := 0
+	for i := 1; i < len(steps); i++ {
+		if math.Signbit(steps[i]) != math.Signbit(steps[i-1]) {
+			alternations++
+		}
+	}
+	// Колебания: знак шага меняется на большинстве шагов, а сами шаги
+	// убывают не быстрее линейного. Перелёт через корень при сверхлинейной
+	// сходимости колебаниями не считается.
+	signed := len(steps) - 1
+	diag.Oscillating = signed >= 3 && 4*alternations >= 3*signed &&
+		!(diag.Order >= 1.2) && !shrinkingSuperlinearly(steps)
+	return diag
+}
+
+// shrinkingSuperlinearly сообщает, что каждое из двух последних отношений
+// длин шагов меньше предыдущего более чем вдвое, т. е. шаги убывают быстрее
+// любой геометрической прогрессии.
+func shrinkingSuperlinearly(steps []float64) bool {
+	n := len(steps)
+	if n < 4 {
+		return false
+	}
+	ratio := func(k int) float64 { return math.Abs(steps[k] / steps[k-1]) }
+	return ratio(n-1) < ratio(n-2)/2 && ratio(n-2) < ratio(n-3)/2
+}
+
+// diagnoseVectors анализирует последовательность векторов-приближений
+// (для систем): eₖ = ‖xₖ - xₙ‖, шаги — ‖xₖ₊₁ - xₖ‖.
+func diagnoseVectors(xs [][]float64) Diagnostics {
+	if len(xs) < 2 {
+		return diagnoseBehaviour(Diagnostics{Order: math.NaN(), Constant: math.NaN()}, nil)
+	}
+	distance := func(a, b []float64) float64 {
+		d := make([]float64, len(a))
+		for i := range a {
+			d[i] = a[i] - b[i]
+		}
+		return norm2(d)
+	}
+	last := xs[len(xs)-1]
+	steps := make([]float64, len(xs)-1)
+	errs := make([]float64, len(xs)-1)
+	for i := range steps {
+		steps[i] = distance(xs[i+1], xs[i])
+		errs[i] = distance(xs[i], last)
+	}
+
+	diag := Diagnostics{Order: math.NaN(), Constant: math.NaN(), Steps: len(steps)}
+	// Ошибка самого xₙ оценивается по последнему шагу s и отношению шагов q
+	// как s·q/(1-q); ошибки, сравнимые с ней, искажены, поэтому берутся
+	// только eₖ больше 100 таких оценок. При быстрой сходимости q мало, и
+	// eₙ₋₁ = s остаётся в оценке.
+	s := steps[len(steps)-1]
+	tail := 100 * s
+	if len(steps) >= 2 {
+		if q := s / steps[len(steps)-2]; q < 1 {
+			tail = 100 * s * q / (1 - q)
+		}
+	}
+	roundoff := roundoffFloor(norm2(last))
+	floor := math.Max(tail, roundoff)
+	if !math.IsNaN(floor) {
+		diag.Order, diag.Constant, diag.Agreeing = estimateOrder(errs, floor)
+		if diag.Agreeing < 2 {
+			// Длины шагов убывают с тем же порядком, что и ошибки, и не
+			// зависят от точности последнего приближения
+			if p, c, k := estimateOrder(steps, roundoff); k > diag.Agreeing {
+				diag.Order, diag.Constant, diag.Agreeing = p, c, k
+			}
+		}
+	}
+	return diagnoseBehaviour(diag, steps)
+}
+
+// diagnoseSteps анализирует длины шагов |xₖ₊₁ - xₖ| (или длины отрезков,
+// содержащих корень), когда самих приближений нет: для сходящейся
+// последовательности они убывают с тем же порядком, что и ошибки.
+func diagnoseSteps(steps []float64) Diagnostics {
+	diag := Diagnostics{Order: math.NaN(), Constant: math.NaN(), Steps: len(steps)}
+	diag.Order, diag.Constant, diag.Agreeing = estimateOrder(steps, roundoffFloor(0))
+	return diagnoseBehaviour(diag, steps)
+}
+
+// diagnoseBehaviour отмечает расходимость и стагнацию по длинам шагов.
+func diagnoseBehaviour(diag Diagnostics, steps []float64) Diagnostics {
+	diag.Steps = len(steps)
+	for _, s := range steps {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			diag.Diverging = true
+			diag.Order, diag.Constant, diag.Agreeing = math.NaN(), math.NaN(), 0
+			return diag
+		}
+	}
+
+	// Рост и стагнация — по последним трём отношениям шагов
+	if len(steps) >= 4 {
+		growing, flat := 0, 0
+		for k := len(steps) - 3; k < len(steps); k++ {
+			r := steps[k] / steps[k-1]
+			switch {
+			case r > 1.1:
+				growing++
+			case r > 0.9:
+				flat++
+			}
+		}
+		diag.Diverging = growing == 3 || steps[len(steps)-1] > 10*steps[0]
+		diag.Stagnating = !diag.Diverging && growing+flat == 3
+	}
+	if diag.Diverging {
+		diag.Order, diag.Constant, diag.Agreeing = math.NaN(), math.NaN(), 0
+	}
+	return diag
+}
+
+// orderName называет порядок сходимости.
+func orderName(p float64) string {
+	switch {
+	case p < 0.8:
+		return "сублинейная"
+	case p < 1.2:
+		return "линейная"
+	case p < 1.5:
+		return "сверхлинейная"
+	case p < 1.8:
+		return "сверхлинейная, как у метода секущих (≈1.618)"
+	case p < 2.4:
+		return "квадратичная"
+	case p < 3.4:
+		return "кубическая"
+	}
+	return "выше кубической"
+}
+
+// String возвращает отчёт о сходимости для вывода после таблицы итераций.
+func (d Diagnostics) String() string {
+	var sb strings.Builder
+	sb.WriteString("Диагностика сходимости:\n")
+	if math.IsNaN(d.Order) {
+		sb.WriteString("  Порядок сходимости: недостаточно данных (нужно хотя бы три ошибки выше уровня округления)\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("  Порядок сходимости: p ≈ %.4f (%s)\n", d.Order, orderName(d.Order)))
+		if d.Agreeing < 2 {
+			sb.WriteString("  Оценка предварительная: она одна или не согласуется с предыдущей\n")
+		}
+		sb.WriteString(fmt.Sprintf("  Асимптотическая константа: C ≈ %.6g (|eₖ₊₁| ≈ C·|eₖ|^p)\n", d.Constant))
+		if d.Order >= 0.8 && d.Order < 1.2 && d.Constant > 0.9 && d.Constant < 1 {
+			sb.WriteString("  Линейная сходимость с C близким к 1 — очень медленная\n")
+		}
+	}
+	behaviour := "монотонная сходимость"
+	switch {
+	case d.Diverging:
+		behaviour = "расходимость: шаг растёт или значения не определены"
+	case d.Stagnating:
+		behaviour = "стагнация: шаг перестал уменьшаться"
+	case d.Oscillating:
+		behaviour = "колебания: приближения попеременно по разные стороны от корня"
+	}
+	sb.WriteString("  Поведение: " + behaviour + "\n")
+	return sb.String()
+}
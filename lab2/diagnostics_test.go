@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateOrder(t *testing.T) {
+	// errors строит eₖ₊₁ = next(eₖ, eₖ₋₁) из e₀, e₁, пока ошибка больше 1e-300
+	errors := func(e0, e1 float64, next func(e, prev float64) float64) []float64 {
+		e := []float64{e0, e1}
+		for {
+			v := next(e[len(e)-1], e[len(e)-2])
+			if v < 1e-300 {
+				return e
+			}
+			e = append(e, v)
+		}
+	}
+	golden := (1 + math.Sqrt(5)) / 2
+
+	tests := []struct {
+		name     string
+		e        []float64
+		floor    float64
+		p, c     float64
+		agreeing int
+	}{
+		{"linear", errors(1, 0.5, func(e, _ float64) float64 { return e / 2 }), 1e-12, 1, 0.5, 3},
+		{"quadratic", errors(0.5, 0.25, func(e, _ float64) float64 { return 3 * e * e }), 1e-300, 2, 3, 3},
+		{"secant", errors(0.1, 0.05, func(e, prev float64) float64 { return e * prev }), 1e-300, golden, 1, 3},
+		// Метод Ньютона успевает сделать лишь несколько шагов до уровня
+		// округления: хватает двух согласованных оценок
+		{"quadratic, four errors", []float64{0.1, 0.02, 0.0008, 1.28e-6, 1e-13}, 1e-12, 2, 2, 2},
+		// Оценки 26.8 и 2.31 не согласуются: берётся последняя
+		{"one estimate", []float64{0.5, 0.4, 1e-3, 1e-9}, 1e-12, math.Log(1e-6) / math.Log(0.0025), 0, 1},
+		{"too few errors", []float64{0.5, 0.1, 1e-14}, 1e-12, math.NaN(), math.NaN(), 0},
+		{"not decreasing", []float64{0.5, 0.6, 0.7, 0.8, 0.9}, 1e-12, math.NaN(), math.NaN(), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, c, agreeing := estimateOrder(tt.e, tt.floor)
+			if agreeing != tt.agreeing {
+				t.Fatalf("p = %g from %d estimates, want %g from %d", p, agreeing, tt.p, tt.agreeing)
+			}
+			if agreeing == 0 {
+				if !math.IsNaN(p) || !math.IsNaN(c) {
+					t.Errorf("p = %g, C = %g, want NaN", p, c)
+				}
+				return
+			}
+			if math.Abs(p-tt.p) > 0.02*tt.p {
+				t.Errorf("p = %g, want %g", p, tt.p)
+			}
+			if tt.agreeing > 1 && math.Abs(math.Log(c/tt.c)) > 0.5 {
+				t.Errorf("C = %g, want about %g", c, tt.c)
+			}
+		})
+	}
+}
+
+func TestDiagnoseIterates(t *testing.T) {
+	// Приближения xₖ = r + eₖ по ошибкам eₖ
+	around := func(r float64, e ...float64) []float64 {
+		xs := make([]float64, len(e))
+		for i, v := range e {
+			xs[i] = r + v
+		}
+		return xs
+	}
+	tests := []struct {
+		name                            string
+		xs                              []float64
+		oscillating, stagnating, diverg bool
+		order                           float64 // NaN — порядок не проверяется
+	}{
+		{"alternating linear", around(1, 1, -0.5, 0.25, -0.125, 0.0625, -0.03125, 0.015625, -0.0078125, 0), true, false, false, 1},
+		{"monotone linear", around(1, 1, 0.5, 0.25, 0.125, 0.0625, 0.03125, 0.015625, 0.0078125, 0), false, false, false, 1},
+		// Метод Ньютона на x³ - 1.89x² - 2x + 1.76 из x₀ = 0: один перелёт через
+		// корень, дальше шаги сверхлинейно убывают до уровня округления
+		{"newton overshoot", []float64{0, 0.88, 0.6195631326584976, 0.629971249049731, 0.6299705393800653, 0.6299705393800654}, false, false, false, 3},
+		// Последние шаги на уровне округления меняют знак
+		{"round-off flips", around(2, 0.1, 1e-3, 1e-7, 4e-16, -4e-16, 4e-16, -4e-16, 4e-16, -4e-16, 4e-16, 0), false, false, false, math.NaN()},
+		{"stagnating", []float64{0, 1, 1.5, 2, 2.5, 3, 3.5}, false, true, false, math.NaN()},
+		{"growing", []float64{0, 1, 3, 7, 15, 31}, false, false, true, math.NaN()},
+		{"not a number", []float64{0, 1, math.NaN()}, false, false, true, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := diagnoseIterates(tt.xs)
+			if d.Oscillating != tt.oscillating || d.Stagnating != tt.stagnating || d.Diverging != tt.diverg {
+				t.Errorf("oscillating %v, stagnating %v, diverging %v; want %v, %v, %v",
+					d.Oscillating, d.Stagnating, d.Diverging, tt.oscillating, tt.stagnating, tt.diverg)
+			}
+			if tt.diverg && !math.IsNaN(d.Order) {
+				t.Errorf("order %g reported for a diverging sequence", d.Order)
+			}
+			if !math.IsNaN(tt.order) && math.Abs(d.Order-tt.order) > 0.1*tt.order {
+				t.Errorf("order %g, want about %g", d.Order, tt.order)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"math"
+	"reflect"
+)
+
+// Diff возвращает производную выражения по переменной с индексом v,
+// упрощённую: константы свёрнуты, нулевые слагаемые и единичные множители
+// убраны.
+func (e *Expr) Diff(v int) *Expr {
+	d := &Expr{Vars: e.Vars, root: derive(e.root, v)}
+	d.Source = d.String()
+	return d
+}
+
+// Derivative возвращает производную выражения от одной переменной.
+func (e *Expr) Derivative() *Expr {
+	return e.Diff(0)
+}
+
+// Jacobian возвращает матрицу Якоби системы: J[i][j] = ∂fᵢ/∂xⱼ.
+func Jacobian(system []*Expr) [][]*Expr {
+	jac := make([][]*Expr, len(system))
+	for i, f := range system {
+		jac[i] = make([]*Expr, len(f.Vars))
+		for j := range f.Vars {
+			jac[i][j] = f.Diff(j)
+		}
+	}
+	return jac
+}
+
+// EvalJacobian вычисляет матрицу Якоби, построенную Jacobian, в точке args.
+func EvalJacobian(jac [][]*Expr, args ...float64) [][]float64 {
+	values := make([][]float64, len(jac))
+	for i, row := range jac {
+		values[i] = make([]float64, len(row))
+		for j, d := range row {
+			values[i][j] = d.Eval(args...)
+		}
+	}
+	return values
+}
+
+// derive дифференцирует n по переменной v.
+func derive(n node, v int) node {
+	switch n := n.(type) {
+	case numNode:
+		return numNode{0}
+	case varNode:
+		if n.index == v {
+			return numNode{1}
+		}
+		return numNode{0}
+	case negNode:
+		return neg(derive(n.arg, v))
+	case binaryNode:
+		l, r := n.left, n.right
+		dl, dr := derive(l, v), derive(r, v)
+		switch n.op {
+		case '+':
+			return add(dl, dr)
+		case '-':
+			return sub(dl, dr)
+		case '*':
+			return add(mul(dl, r), mul(l, dr))
+		case '/':
+			return div(sub(mul(dl, r), mul(l, dr)), pw(r, numNode{2}))
+		default:
+			if isZero(dr) {
+				// (uⁿ)' = n·uⁿ⁻¹·u'
+				return mul(mul(r, pw(l, sub(r, numNode{1}))), dl)
+			}
+			if isZero(dl) {
+				// (aᵘ)' = aᵘ·ln(a)·u'
+				return mul(mul(n, call("ln", l)), dr)
+			}
+			// (uᵛ)' = uᵛ·(v'·ln(u) + v·u'/u)
+			return mul(n, add(mul(dr, call("ln", l)), div(mul(r, dl), l)))
+		}
+	case callNode:
+		return mul(derivePrimitive(n.name, n.arg), derive(n.arg, v))
+	}
+	panic("unknown node")
+}
+
+// derivePrimitive возвращает f'(u) для элементарной функции f.
+func derivePrimitive(name string, u node) node {
+	one := numNode{1}
+	switch name {
+	case "sin":
+		return call("cos", u)
+	case "cos":
+		return neg(call("sin", u))
+	case "tan", "tg":
+		return div(one, pw(call("cos", u), numNode{2}))
+	case "cot", "ctg":
+		return neg(div(one, pw(call("sin", u), numNode{2})))
+	case "asin":
+		return div(one, call("sqrt", sub(one, pw(u, numNode{2}))))
+	case "acos":
+		return neg(div(one, call("sqrt", sub(one, pw(u, numNode{2})))))
+	case "atan", "arctg":
+		return div(one, add(one, pw(u, numNode{2})))
+	case "sinh":
+		return call("cosh", u)
+	case "cosh":
+		return call("sinh", u)
+	case "tanh":
+		return div(one, pw(call("cosh", u), numNode{2}))
+	case "exp":
+		return call("exp", u)
+	case "ln", "log":
+		return div(one, u)
+	case "lg", "log10":
+		return div(one, mul(u, call("ln", numNode{10})))
+	case "log2":
+		return div(one, mul(u, call("ln", numNode{2})))
+	case "sqrt":
+		return div(one, mul(numNode{2}, call("sqrt", u)))
+	case "cbrt":
+		return div(one, mul(numNode{3}, pw(call("cbrt", u), numNode{2})))
+	case "abs":
+		return div(u, call("abs", u))
+	}
+	panic("no derivative for " + name)
+}
+
+// Конструкторы узлов с упрощением. Они применяют только тождества,
+// верные при любых значениях переменных (кроме 0·x = 0 там, где x не
+// определён).
+
+func isZero(n node) bool { c, ok := n.(numNode); return ok && c.value == 0 }
+func isOne(n node) bool  { c, ok := n.(numNode); return ok && c.value == 1 }
+
+func same(a, b node) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func call(name string, arg node) node { return callNode{name: name, arg: arg} }
+
+func neg(a node) node {
+	switch a := a.(type) {
+	case numNode:
+		return numNode{-a.value}
+	case negNode:
+		return a.arg
+	}
+	return negNode{arg: a}
+}
+
+func add(a, b node) node {
+	ca, aNum := a.(numNode)
+	cb, bNum := b.(numNode)
+	switch {
+	case aNum && bNum:
+		return numNode{ca.value + cb.value}
+	case isZero(a):
+		return b
+	case isZero(b):
+		return a
+	case bNum && cb.value < 0:
+		return sub(a, numNode{-cb.value})
+	}
+	if nb, ok := b.(negNode); ok {
+		return sub(a, nb.arg)
+	}
+	if na, ok := a.(negNode); ok {
+		return sub(b, na.arg)
+	}
+	if same(a, b) {
+		return mul(numNode{2}, a)
+	}
+	return binaryNode{op: '+', left: a, right: b}
+}
+
+func sub(a, b node) node {
+	ca, aNum := a.(numNode)
+	cb, bNum := b.(numNode)
+	switch {
+	case aNum && bNum:
+		return numNode{ca.value - cb.value}
+	case isZero(b):
+		return a
+	case isZero(a):
+		return neg(b)
+	case bNum && cb.value < 0:
+		return add(a, numNode{-cb.value})
+	case same(a, b):
+		return numNode{0}
+	}
+	if nb, ok := b.(negNode); ok {
+		return add(a, nb.arg)
+	}
+	return binaryNode{op: '-', left: a, right: b}
+}
+
+func mul(a, b node) node {
+	// Число ставится первым множителем: x·2 = 2·x
+	if _, ok := b.(numNode); ok {
+		if _, ok := a.(numNode); !ok {
+			a, b = b, a
+		}
+	}
+	ca, aNum := a.(numNode)
+	cb, bNum := b.(numNode)
+	switch {
+	case aNum && bNum:
+		return numNode{ca.value * cb.value}
+	case isZero(a) || isZero(b):
+		return numNode{0}
+	case isOne(a):
+		return b
+	case aNum && ca.value == -1:
+		return neg(b)
+	case aNum && ca.value < 0:
+		return neg(mul(numNode{-ca.value}, b))
+	}
+	if na, ok := a.(negNode); ok {
+		return neg(mul(na.arg, b))
+	}
+	if nb, ok := b.(negNode); ok {
+		return neg(mul(a, nb.arg))
+	}
+	// c₁·(c₂·x) = (c₁c₂)·x
+	if bb, ok := b.(binaryNode); ok && aNum && bb.op == '*' {
+		if c2, ok := bb.left.(numNode); ok {
+			return mul(numNode{ca.value * c2.value}, bb.right)
+		}
+	}
+	// u·(c·v) = c·u·v
+	if bb, ok := b.(binaryNode); ok && !aNum && bb.op == '*' {
+		if c, ok := bb.left.(numNode); ok {
+			return mul(mul(c, a), bb.right)
+		}
+	}
+	// 1/u · v = v/u
+	if ba, ok := a.(binaryNode); ok && ba.op == '/' && isOne(ba.left) {
+		return div(b, ba.right)
+	}
+	if bb, ok := b.(binaryNode); ok && bb.op == '/' && isOne(bb.left) {
+		return div(a, bb.right)
+	}
+	if same(a, b) {
+		return pw(a, numNode{2})
+	}
+	return binaryNode{op: '*', left: a, right: b}
+}
+
+func div(a, b node) node {
+	ca, aNum := a.(numNode)
+	cb, bNum := b.(numNode)
+	switch {
+	case aNum && bNum && cb.value != 0:
+		return numNode{ca.value / cb.value}
+	case isZero(a):
+		return numNode{0}
+	case isOne(b):
+		return a
+	case same(a, b):
+		return numNode{1}
+	}
+	if na, ok := a.(negNode); ok {
+		return neg(div(na.arg, b))
+	}
+	return binaryNode{op: '/', left: a, right: b}
+}
+
+func pw(a, b node) node {
+	ca, aNum := a.(numNode)
+	cb, bNum := b.(numNode)
+	switch {
+	case aNum && bNum:
+		return numNode{pow(ca.value, cb.value)}
+	case isZero(b):
+		return numNode{1}
+	case isOne(b):
+		return a
+	}
+	// (u^c₁)^c₂ = u^(c₁c₂) для целых показателей
+	if ba, ok := a.(binaryNode); ok && ba.op == '^' && bNum {
+		if c1, ok := ba.right.(numNode); ok && c1.value == math.Trunc(c1.value) && cb.value == math.Trunc(cb.value) {
+			return pw(ba.left, numNode{c1.value * cb.value})
+		}
+	}
+	return binaryNode{op: '^', left: a, right: b}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDerivative(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{"x^3 - 1.89*x^2 - 2*x + 1.76", "3 * x^2 - 3.78 * x - 2"},
+		{"x^-2", "-(2 * x^(-3))"},
+		{"x^(-2)", "-(2 * x^(-3))"},
+		{"-2*x^3", "-(6 * x^2)"},
+		{"-pi*x", "-pi"},
+		{"sin(x)", "cos(x)"},
+		{"exp(2*x)", "2 * exp(2 * x)"},
+		{"ln(x)", "1 / x"},
+		{"5", "0"},
+		{"x - x", "0"},
+		{"--x", "1"},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if got := e.Derivative().String(); got != tt.want {
+			t.Errorf("d/dx %s = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+// Производная сверяется с центральной разностью в нескольких точках.
+func TestDerivativeNumeric(t *testing.T) {
+	exprs := []string{
+		"x^3 - 1.89*x^2 - 2*x + 1.76",
+		"x^-2 + 1/x",
+		"sin(x)^2 * cos(3*x)",
+		"exp(-x^2/2) / sqrt(x)",
+		"x^x",
+		"2^-x - ln(x^2 + 1)",
+		"tan(x) * atan(x)",
+		"sqrt(1 + x) - cbrt(x)",
+	}
+	points := []float64{0.3, 0.8, 1.7}
+	for _, src := range exprs {
+		e, err := ParseExpr(src)
+		if err != nil {
+			t.Fatalf("%s: %v", src, err)
+		}
+		f, df := e.Func1(), e.Derivative().Func1()
+		for _, x := range points {
+			h := 1e-6
+			want := (f(x+h) - f(x-h)) / (2 * h)
+			if got := df(x); math.Abs(got-want) > 1e-5*math.Max(1, math.Abs(want)) {
+				t.Errorf("d/dx %s at x = %g: %g, central difference %g", src, x, got, want)
+			}
+		}
+	}
+}
+
+func TestPartialDerivatives(t *testing.T) {
+	e, err := ParseExpr("x^2*y + sin(y)", "x", "y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Diff(0).String(); got != "2 * x * y" {
+		t.Errorf("∂/∂x = %s", got)
+	}
+	if got := e.Diff(1).String(); got != "x^2 + cos(y)" {
+		t.Errorf("∂/∂y = %s", got)
+	}
+}
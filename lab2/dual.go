@@ -0,0 +1,98 @@
+package main
+
+import "math"
+
+// Dual — дуальное число a + bε, ε² = 0. Вычисление f(x + ε) даёт
+// f(x) + f'(x)ε, поэтому функция, записанная через операции над Dual,
+// дифференцируется автоматически (прямой режим), без символьных
+// преобразований и без погрешности конечных разностей.
+type Dual struct {
+	Val, Der float64
+}
+
+// Const — константа (производная равна нулю).
+func Const(c float64) Dual { return Dual{c, 0} }
+
+// Variable — независимая переменная (производная равна единице).
+func Variable(x float64) Dual { return Dual{x, 1} }
+
+func (a Dual) Add(b Dual) Dual { return Dual{a.Val + b.Val, a.Der + b.Der} }
+func (a Dual) Sub(b Dual) Dual { return Dual{a.Val - b.Val, a.Der - b.Der} }
+func (a Dual) Neg() Dual       { return Dual{-a.Val, -a.Der} }
+
+func (a Dual) Mul(b Dual) Dual {
+	return Dual{a.Val * b.Val, a.Der*b.Val + a.Val*b.Der}
+}
+
+func (a Dual) Div(b Dual) Dual {
+	return Dual{a.Val / b.Val, (a.Der*b.Val - a.Val*b.Der) / (b.Val * b.Val)}
+}
+
+// Scale умножает на число.
+func (a Dual) Scale(c float64) Dual { return Dual{c * a.Val, c * a.Der} }
+
+// Pow возводит в постоянную степень n.
+func (a Dual) Pow(n float64) Dual {
+	return Dual{math.Pow(a.Val, n), n * math.Pow(a.Val, n-1) * a.Der}
+}
+
+// chain применяет функцию со значением f и производной df к a.
+func chain(a Dual, f, df float64) Dual { return Dual{f, df * a.Der} }
+
+func DSin(a Dual) Dual  { return chain(a, math.Sin(a.Val), math.Cos(a.Val)) }
+func DCos(a Dual) Dual  { return chain(a, math.Cos(a.Val), -math.Sin(a.Val)) }
+func DExp(a Dual) Dual  { e := math.Exp(a.Val); return chain(a, e, e) }
+func DLog(a Dual) Dual  { return chain(a, math.Log(a.Val), 1/a.Val) }
+func DSqrt(a Dual) Dual { s := math.Sqrt(a.Val); return chain(a, s, 0.5/s) }
+func DAtan(a Dual) Dual { return chain(a, math.Atan(a.Val), 1/(1+a.Val*a.Val)) }
+
+func DTan(a Dual) Dual {
+	c := math.Cos(a.Val)
+	return chain(a, math.Tan(a.Val), 1/(c*c))
+}
+
+// ADFunc превращает функцию над Dual в обычную функцию и её производную.
+func ADFunc(f func(Dual) Dual) (func(float64) float64, func(float64) float64) {
+	value := func(x float64) float64 { return f(Const(x)).Val }
+	deriv := func(x float64) float64 { return f(Variable(x)).Der }
+	return value, deriv
+}
+
+// ADVector превращает систему функций над Dual в обычную вектор-функцию.
+func ADVector(fs []func([]Dual) Dual) VectorFunc {
+	return func(x []float64) []float64 {
+		args := make([]Dual, len(x))
+		for i, v := range x {
+			args[i] = Const(v)
+		}
+		values := make([]float64, len(fs))
+		for i, f := range fs {
+			values[i] = f(args).Val
+		}
+		return values
+	}
+}
+
+// ADJacobian вычисляет значения системы fs в точке x и матрицу Якоби,
+// прогоняя систему один раз на каждую переменную.
+func ADJacobian(fs []func([]Dual) Dual, x []float64) ([]float64, [][]float64) {
+	n := len(x)
+	values := make([]float64, len(fs))
+	jac := make([][]float64, len(fs))
+	for i := range jac {
+		jac[i] = make([]float64, n)
+	}
+	args := make([]Dual, n)
+	for j := 0; j < n; j++ {
+		for k := range args {
+			args[k] = Const(x[k])
+		}
+		args[j] = Variable(x[j])
+		for i, f := range fs {
+			d := f(args)
+			values[i] = d.Val
+			jac[i][j] = d.Der
+		}
+	}
+	return values, jac
+}
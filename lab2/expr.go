@@ -0,0 +1,500 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Expr — разобранное выражение от переменных Vars, например
+// "x^3 - 1.89*x^2 + sin(x)". Уравнение вида "левая = правая" разбирается
+// как "левая - (правая)".
+type Expr struct {
+	Source string
+	Vars   []string
+	root   node
+}
+
+// node — узел дерева выражения. Переменные хранятся индексом в Vars.
+type node interface {
+	eval(args []float64) float64
+	// prec — приоритет операции узла, нужен для расстановки скобок в String.
+	prec() int
+	format(vars []string) string
+}
+
+// Приоритеты операций, от слабого к сильному.
+const (
+	precSum = iota + 1
+	precProduct
+	precUnary
+	precPower
+	precAtom
+)
+
+type numNode struct{ value float64 }
+
+type varNode struct{ index int }
+
+type negNode struct{ arg node }
+
+type binaryNode struct {
+	op          byte // '+', '-', '*', '/', '^'
+	left, right node
+}
+
+type callNode struct {
+	name string
+	arg  node
+}
+
+func (n numNode) eval([]float64) float64      { return n.value }
+func (n varNode) eval(args []float64) float64 { return args[n.index] }
+func (n negNode) eval(args []float64) float64 { return -n.arg.eval(args) }
+
+func (n binaryNode) eval(args []float64) float64 {
+	l, r := n.left.eval(args), n.right.eval(args)
+	switch n.op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	case '/':
+		return l / r
+	default:
+		return pow(l, r)
+	}
+}
+
+func (n callNode) eval(args []float64) float64 {
+	return functions[n.name](n.arg.eval(args))
+}
+
+// pow возводит в степень, допуская отрицательное основание при целом
+// показателе и нечётном знаменателе дроби вида 1/3, чтобы x^(1/3) был
+// определён для x < 0, как кубический корень.
+func pow(x, y float64) float64 {
+	if x < 0 && y != math.Trunc(y) {
+		if inv := 1 / y; inv == math.Trunc(inv) && int64(inv)%2 != 0 {
+			return -math.Pow(-x, y)
+		}
+	}
+	return math.Pow(x, y)
+}
+
+func (varNode) prec() int  { return precAtom }
+func (negNode) prec() int  { return precUnary }
+func (callNode) prec() int { return precAtom }
+
+// Отрицательное число записывается со знаком минус, поэтому в степени
+// и после операций оно берётся в скобки, как унарный минус.
+func (n numNode) prec() int {
+	if n.value < 0 {
+		return precUnary
+	}
+	return precAtom
+}
+
+func (n binaryNode) prec() int {
+	switch n.op {
+	case '+', '-':
+		return precSum
+	case '*', '/':
+		return precProduct
+	default:
+		return precPower
+	}
+}
+
+func (n numNode) format([]string) string {
+	switch n.value {
+	case math.Pi:
+		return "pi"
+	case math.E:
+		return "e"
+	case -math.Pi:
+		return "-pi"
+	case -math.E:
+		return "-e"
+	}
+	return strconv.FormatFloat(n.value, 'g', -1, 64)
+}
+
+func (n varNode) format(vars []string) string { return vars[n.index] }
+
+func (n negNode) format(vars []string) string {
+	return "-" + wrap(n.arg, precUnary+1, vars)
+}
+
+func (n binaryNode) format(vars []string) string {
+	p := n.prec()
+	// Левоассоциативные операции: правый операнд того же приоритета
+	// берётся в скобки (a - (b - c)); степень правоассоциативна.
+	if n.op == '^' {
+		return wrap(n.left, p+1, vars) + "^" + wrap(n.right, p, vars)
+	}
+	return wrap(n.left, p, vars) + " " + string(n.op) + " " + wrap(n.right, p+1, vars)
+}
+
+func (n callNode) format(vars []string) string {
+	return n.name + "(" + n.arg.format(vars) + ")"
+}
+
+// wrap форматирует n, заключая его в скобки, если его приоритет ниже min.
+func wrap(n node, min int, vars []string) string {
+	s := n.format(vars)
+	if n.prec() < min {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// functions — элементарные функции, доступные в выражениях.
+var functions = map[string]func(float64) float64{
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+	"tg":    math.Tan,
+	"cot":   func(x float64) float64 { return 1 / math.Tan(x) },
+	"ctg":   func(x float64) float64 { return 1 / math.Tan(x) },
+	"asin":  math.Asin,
+	"acos":  math.Acos,
+	"atan":  math.Atan,
+	"arctg": math.Atan,
+	"sinh":  math.Sinh,
+	"cosh":  math.Cosh,
+	"tanh":  math.Tanh,
+	"exp":   math.Exp,
+	"ln":    math.Log,
+	"log":   math.Log,
+	"lg":    math.Log10,
+	"log10": math.Log10,
+	"log2":  math.Log2,
+	"sqrt":  math.Sqrt,
+	"cbrt":  math.Cbrt,
+	"abs":   math.Abs,
+}
+
+// constants — именованные константы.
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// ParseExpr разбирает выражение от переменных vars (по умолчанию "x").
+// Поддерживаются + - * / ^, скобки, унарный минус, функции из functions,
+// константы pi и e, неявное умножение (2x, 3sin(x), 2(x+1)) и знак "=".
+func ParseExpr(src string, vars ...string) (*Expr, error) {
+	if len(vars) == 0 {
+		vars = []string{"x"}
+	}
+	p := &parser{src: src, vars: vars}
+	p.next()
+
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEquals {
+		p.next()
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if n, ok := right.(numNode); !ok || n.value != 0 {
+			root = binaryNode{op: '-', left: root, right: right}
+		}
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("неожиданный символ %q", p.tok.text)
+	}
+	return &Expr{Source: src, Vars: vars, root: root}, nil
+}
+
+// Eval вычисляет выражение; значения переменных передаются в порядке Vars.
+func (e *Expr) Eval(args ...float64) float64 {
+	return e.root.eval(args)
+}
+
+// Func1 возвращает выражение от одной переменной как функцию.
+func (e *Expr) Func1() func(float64) float64 {
+	args := make([]float64, 1)
+	return func(x float64) float64 {
+		args[0] = x
+		return e.root.eval(args)
+	}
+}
+
+// String возвращает выражение в нормализованной записи.
+func (e *Expr) String() string {
+	return e.root.format(e.Vars)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokEquals
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+type parser struct {
+	src  string
+	pos  int
+	vars []string
+	tok  token
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("позиция %d: %s", p.tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+// next считывает следующую лексему в p.tok.
+func (p *parser) next() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.src) && (isDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		// Показатель степени: 1e-3, но не 2e (2·e) и не 2exp(x)
+		if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+			q := p.pos + 1
+			if q < len(p.src) && (p.src[q] == '+' || p.src[q] == '-') {
+				q++
+			}
+			if q < len(p.src) && isDigit(p.src[q]) {
+				for q < len(p.src) && isDigit(p.src[q]) {
+					q++
+				}
+				p.pos = q
+			}
+		}
+		text := p.src[start:p.pos]
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			p.tok = token{kind: tokOp, text: text, pos: start}
+			return
+		}
+		p.tok = token{kind: tokNumber, text: text, value: value, pos: start}
+	case isLetter(c):
+		for p.pos < len(p.src) && (isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: p.src[start:p.pos], pos: start}
+	case c == '(':
+		p.pos++
+		p.tok = token{kind: tokLParen, text: "(", pos: start}
+	case c == ')':
+		p.pos++
+		p.tok = token{kind: tokRParen, text: ")", pos: start}
+	case c == '=':
+		p.pos++
+		p.tok = token{kind: tokEquals, text: "=", pos: start}
+	case c == '*' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '*':
+		p.pos += 2
+		p.tok = token{kind: tokOp, text: "^", pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	}
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' }
+
+// parseSum: слагаемое {(+|-) слагаемое}
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseProduct: множитель {(*|/|неявное умножение) множитель}
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op byte
+		switch {
+		case p.tok.kind == tokOp && (p.tok.text == "*" || p.tok.text == "/"):
+			op = p.tok.text[0]
+			p.next()
+		case p.tok.kind == tokNumber || p.tok.kind == tokIdent || p.tok.kind == tokLParen:
+			// Неявное умножение: 2x, 2 sin(x), (x+1)(x-1)
+			op = '*'
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+// parseUnary: [+|-] степень
+func (p *parser) parseUnary() (node, error) {
+	if p.tok.kind == tokOp && (p.tok.text == "-" || p.tok.text == "+") {
+		neg := p.tok.text == "-"
+		p.next()
+		arg, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if !neg {
+			return arg, nil
+		}
+		// Минус перед числом — часть числа: тогда x^-2 и x^(-2) дают одно
+		// дерево, и константы в нём сворачиваются при упрощении
+		if c, ok := arg.(numNode); ok {
+			return numNode{-c.value}, nil
+		}
+		return negNode{arg: arg}, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower: атом [^ унарное] — степень правоассоциативна и сильнее
+// унарного минуса: -x^2 = -(x^2), 2^-1 = 0.5.
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokOp && p.tok.text == "^" {
+		p.next()
+		exp, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: '^', left: base, right: exp}, nil
+	}
+	return base, nil
+}
+
+// parseAtom: число | переменная | константа | функция(выражение) | (выражение)
+func (p *parser) parseAtom() (node, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		p.next()
+		return numNode{value: tok.value}, nil
+	case tokLParen:
+		p.next()
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("ожидалась закрывающая скобка")
+		}
+		p.next()
+		return inner, nil
+	case tokIdent:
+		p.next()
+		name := strings.ToLower(tok.text)
+		if _, ok := functions[name]; ok {
+			if p.tok.kind != tokLParen {
+				return nil, fmt.Errorf("позиция %d: после %s ожидалась скобка с аргументом", tok.pos+1, tok.text)
+			}
+			arg, err := p.parseAtom()
+			if err != nil {
+				return nil, err
+			}
+			return callNode{name: name, arg: arg}, nil
+		}
+		if n, ok := p.name(tok.text); ok {
+			return n, nil
+		}
+		// Слитно записанные переменные: xy = x*y
+		if n, ok := p.splitNames(tok.text); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("позиция %d: неизвестное имя %q (переменные: %s)", tok.pos+1, tok.text, strings.Join(p.vars, ", "))
+	case tokEOF:
+		return nil, p.errorf("неожиданный конец выражения")
+	default:
+		return nil, p.errorf("неожиданный символ %q", tok.text)
+	}
+}
+
+// name возвращает переменную или константу с именем ident.
+func (p *parser) name(ident string) (node, bool) {
+	for i, v := range p.vars {
+		if v == ident {
+			return varNode{index: i}, true
+		}
+	}
+	if value, ok := constants[strings.ToLower(ident)]; ok {
+		return numNode{value: value}, true
+	}
+	return nil, false
+}
+
+// splitNames разбирает ident как произведение однобуквенных переменных и
+// констант, например xy или 2pix после числа.
+func (p *parser) splitNames(ident string) (node, bool) {
+	var product node
+	for i := 0; i < len(ident); {
+		var factor node
+		var size int
+		for _, try := range []int{2, 1} {
+			if i+try > len(ident) {
+				continue
+			}
+			if n, ok := p.name(ident[i : i+try]); ok {
+				factor, size = n, try
+				break
+			}
+		}
+		if factor == nil {
+			return nil, false
+		}
+		if product == nil {
+			product = factor
+		} else {
+			product = binaryNode{op: '*', left: product, right: factor}
+		}
+		i += size
+	}
+	return product, product != nil
+}
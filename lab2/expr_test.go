@@ -0,0 +1,74 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		src  string
+		vars []string
+		args []float64
+		want float64
+	}{
+		{"x^3 - 1.89*x^2 - 2*x + 1.76", nil, []float64{2}, 8 - 7.56 - 4 + 1.76},
+		{"2x + 3", nil, []float64{1.5}, 6},
+		{"2(x+1)(x-1)", nil, []float64{3}, 16},
+		{"3sin(x)", nil, []float64{math.Pi / 2}, 3},
+		{"2pix", nil, []float64{1}, 2 * math.Pi},
+		{"-x^2", nil, []float64{3}, -9},
+		{"2^-1", nil, nil, 0.5},
+		{"2^3^2", nil, nil, 512},
+		{"x**2", nil, []float64{4}, 16},
+		{"1e-3*x", nil, []float64{2}, 0.002},
+		{"2e", nil, nil, 2 * math.E},
+		{"2exp(x)", nil, []float64{0}, 2},
+		{"x^2 = 2", nil, []float64{3}, 7},
+		{"x = 0", nil, []float64{5}, 5},
+		{"tg(x) + ctg(x)", nil, []float64{math.Pi / 4}, 2},
+		{"lg(x) + ln(e)", nil, []float64{100}, 3},
+		{"xy - y", []string{"x", "y"}, []float64{3, 2}, 4},
+		{"x1^2 + x2^2 = 4", []string{"x1", "x2"}, []float64{1, 1}, -2},
+		{"SIN(X)", []string{"X"}, []float64{0}, 0},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.src, tt.vars...)
+		if err != nil {
+			t.Errorf("%s: %v", tt.src, err)
+			continue
+		}
+		if got := e.Eval(tt.args...); math.Abs(got-tt.want) > 1e-12*math.Max(1, math.Abs(tt.want)) {
+			t.Errorf("%s at %v = %g, want %g", tt.src, tt.args, got, tt.want)
+		}
+		// Нормализованная запись разбирается в то же выражение
+		again, err := ParseExpr(e.String(), tt.vars...)
+		if err != nil {
+			t.Errorf("%s: %s: %v", tt.src, e.String(), err)
+			continue
+		}
+		if got := again.Eval(tt.args...); math.Abs(got-e.Eval(tt.args...)) > 1e-12*math.Max(1, math.Abs(got)) {
+			t.Errorf("%s: %s at %v = %g, want %g", tt.src, e.String(), tt.args, got, e.Eval(tt.args...))
+		}
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"x +",
+		"(x + 1",
+		"x + 1)",
+		"sin x",
+		"foo(x)",
+		"z + 1",
+		"2 ** * x",
+		"1..2",
+		"x = ",
+	}
+	for _, src := range tests {
+		if e, err := ParseExpr(src); err == nil {
+			t.Errorf("%q parsed as %s", src, e)
+		}
+	}
+}
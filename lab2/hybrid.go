@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Гибридные методы с гарантированной сходимостью. Все они начинают с
+// отрезка [a, b], на концах которого f имеет разные знаки, и сохраняют
+// такой отрезок на каждом шаге. Строка таблицы итераций: a, b, x, f(x)
+// и длина текущего отрезка (для Ньютона-бисекции — длина шага).
+
+const (
+	maxHybridIter = 1000
+	// machineEpsilon — расстояние от 1 до следующего числа float64
+	machineEpsilon = 0x1p-52
+)
+
+// sameSign сообщает, имеют ли a и b одинаковый знак.
+func sameSign(a, b float64) bool {
+	return math.Signbit(a) == math.Signbit(b)
+}
+
+// checkBracket проверяет, что f(a) и f(b) имеют разные знаки.
+func checkBracket(fa, fb float64) bool {
+	if fa*fb > 0 {
+		fmt.Println("Ошибка: f(a) и f(b) должны иметь разные знаки")
+		return false
+	}
+	return true
+}
+
+// Метод Брента: обратная квадратичная интерполяция и секущие с
+// переходом на деление пополам, когда интерполяция сходится медленно.
+func brentMethod(f func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+	var iterations [][]float64
+
+	fa, fb := f(a), f(b)
+	if !checkBracket(fa, fb) {
+		return 0, 0, iterations
+	}
+
+	c, fc := b, fb
+	var d, e float64
+	iteration := 0
+	for {
+		iteration++
+		if sameSign(fb, fc) && fb != 0 {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		// b — лучшее приближение, c — противоположный конец отрезка
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol := 2*machineEpsilon*math.Abs(b) + eps/2
+		xm := (c - b) / 2
+		iterations = append(iterations, []float64{math.Min(b, c), math.Max(b, c), b, fb, math.Abs(c - b)})
+
+		if math.Abs(xm) <= tol || fb == 0 {
+			return b, iteration, iterations
+		}
+
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				// Секущая
+				p = 2 * xm * s
+				q = 1 - s
+			} else {
+				// Обратная квадратичная интерполяция
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*xm*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < math.Min(3*xm*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = xm
+				e = d
+			}
+		} else {
+			d = xm
+			e = d
+		}
+
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, xm)
+		}
+		fb = f(b)
+
+		if iteration > maxHybridIter {
+			fmt.Println("Предупреждение: достигнуто максимальное число итераций")
+			return b, iteration, iterations
+		}
+	}
+}
+
+// Метод Деккера: шаг секущей, если он попадает между текущим приближением
+// и серединой отрезка, иначе деление пополам.
+func dekkerMethod(f func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+	var iterations [][]float64
+
+	fa, fb := f(a), f(b)
+	if !checkBracket(fa, fb) {
+		return 0, 0, iterations
+	}
+	if math.Abs(fa) < math.Abs(fb) {
+		a, b, fa, fb = b, a, fb, fa
+	}
+	prev, fprev := a, fa
+
+	iteration := 0
+	for {
+		iteration++
+		m := (a + b) / 2
+		iterations = append(iterations, []float64{math.Min(a, b), math.Max(a, b), b, fb, math.Abs(b - a)})
+		if math.Abs(b-a)/2 < eps || fb == 0 {
+			return b, iteration, iterations
+		}
+
+		next := m
+		if fb != fprev {
+			s := b - fb*(b-prev)/(fb-fprev)
+			if (s-b)*(s-m) < 0 {
+				next = s
+			}
+		}
+		prev, fprev = b, fb
+		b, fb = next, f(next)
+		if sameSign(fa, fb) {
+			a, fa = prev, fprev
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a, b, fa, fb = b, a, fb, fa
+		}
+
+		if iteration > maxHybridIter {
+			fmt.Println("Предупреждение: достигнуто максимальное число итераций")
+			return b, iteration, iterations
+		}
+	}
+}
+
+// Метод Риддерса: экспоненциальная коррекция середины отрезка,
+// x = m + (m - a)·sign(f(a) - f(b))·f(m) / sqrt(f(m)² - f(a)·f(b)).
+func riddersMethod(f func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+	var iterations [][]float64
+
+	fa, fb := f(a), f(b)
+	if !checkBracket(fa, fb) {
+		return 0, 0, iterations
+	}
+	if fa == 0 {
+		return a, 0, iterations
+	}
+	if fb == 0 {
+		return b, 0, iterations
+	}
+
+	x := math.NaN()
+	iteration := 0
+	for {
+		iteration++
+		m := (a + b) / 2
+		fm := f(m)
+		s := math.Sqrt(fm*fm - fa*fb)
+		if s == 0 {
+			return m, iteration, iterations
+		}
+		xOld := x
+		x = m + (m-a)*math.Copysign(1, fa-fb)*fm/s
+		fx := f(x)
+
+		switch {
+		case !sameSign(fm, fx):
+			a, fa, b, fb = m, fm, x, fx
+		case !sameSign(fa, fx):
+			b, fb = x, fx
+		default:
+			a, fa = x, fx
+		}
+		iterations = append(iterations, []float64{math.Min(a, b), math.Max(a, b), x, fx, math.Abs(b - a)})
+
+		if fx == 0 || math.Abs(x-xOld) < eps || math.Abs(b-a) < eps {
+			return x, iteration, iterations
+		}
+
+		if iteration > maxHybridIter {
+			fmt.Println("Предупреждение: достигнуто максимальное число итераций")
+			return x, iteration, iterations
+		}
+	}
+}
+
+// regulaFalsi — метод хорд, в котором значение функции на неподвижном
+// конце уменьшается множителем scale(fx, fb), чтобы конец не «залипал».
+func regulaFalsi(f func(float64) float64, a, b, eps float64, scale func(fx, fb float64) float64) (float64, int, [][]float64) {
+	var iterations [][]float64
+
+	fa, fb := f(a), f(b)
+	if !checkBracket(fa, fb) {
+		return 0, 0, iterations
+	}
+
+	x := math.NaN()
+	iteration := 0
+	for {
+		iteration++
+		xOld := x
+		x = (a*fb - b*fa) / (fb - fa)
+		fx := f(x)
+
+		if !sameSign(fx, fb) {
+			a, fa = b, fb
+		} else {
+			fa *= scale(fx, fb)
+		}
+		b, fb = x, fx
+		iterations = append(iterations, []float64{math.Min(a, b), math.Max(a, b), x, fx, math.Abs(b - a)})
+
+		if fx == 0 || math.Abs(x-xOld) < eps || math.Abs(b-a) < eps {
+			return x, iteration, iterations
+		}
+
+		if iteration > maxHybridIter {
+			fmt.Println("Предупреждение: достигнуто максимальное число итераций")
+			return x, iteration, iterations
+		}
+	}
+}
+
+// Иллинойсский метод: значение на неподвижном конце делится пополам.
+func illinoisMethod(f func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+	return regulaFalsi(f, a, b, eps, func(fx, fb float64) float64 { return 0.5 })
+}
+
+// Метод Андерсона–Бьорка: множитель 1 - f(x)/f(b). Если он не
+// положителен или очень мал (f(x) почти равно f(b), как на пологом участке),
+// берётся 1/2: иначе f на неподвижном конце обнуляется и следующее
+// приближение попадает точно в этот конец.
+func andersonBjorckMethod(f func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+	return regulaFalsi(f, a, b, eps, func(fx, fb float64) float64 {
+		if m := 1 - fx/fb; m > 0.1 {
+			return m
+		}
+		return 0.5
+	})
+}
+
+// Метод Ньютона с защитой делением пополам: шаг Ньютона принимается,
+// только если он остаётся внутри отрезка и уменьшает шаг хотя бы вдвое,
+// иначе отрезок делится пополам.
+func newtonBisectionMethod(f, df func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+	var iterations [][]float64
+
+	fa, fb := f(a), f(b)
+	if !checkBracket(fa, fb) {
+		return 0, 0, iterations
+	}
+	if fa == 0 {
+		return a, 0, iterations
+	}
+	if fb == 0 {
+		return b, 0, iterations
+	}
+
+	x := (a + b) / 2
+	dxOld := math.Abs(b - a)
+	iteration := 0
+	for {
+		iteration++
+		fx, dfx := f(x), df(x)
+		if fx == 0 {
+			iterations = append(iterations, []float64{a, b, x, fx, 0})
+			return x, iteration, iterations
+		}
+		if sameSign(fx, fa) {
+			a, fa = x, fx
+		} else {
+			b = x
+		}
+
+		next := x - fx/dfx
+		if dfx == 0 || (next-a)*(next-b) >= 0 || math.Abs(2*fx) > math.Abs(dxOld*dfx) {
+			next = (a + b) / 2
+		}
+		dxOld = math.Abs(next - x)
+		iterations = append(iterations, []float64{math.Min(a, b), math.Max(a, b), x, fx, dxOld})
+		x = next
+
+		if dxOld < eps {
+			return x, iteration, iterations
+		}
+
+		if iteration > maxHybridIter {
+			fmt.Println("Предупреждение: достигнуто максимальное число итераций")
+			return x, iteration, iterations
+		}
+	}
+}
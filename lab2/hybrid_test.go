@@ -0,0 +1,80 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRootFinders(t *testing.T) {
+	tests := []struct {
+		name  string
+		f, df string
+		a, b  float64
+		root  float64
+	}{
+		{"cubic", "x^3 - 1.89*x^2 - 2*x + 1.76", "3*x^2 - 3.78*x - 2", 0, 1.5, 0.6299705394},
+		{"cos", "cos(x) - x", "-sin(x) - 1", 0, 1, 0.7390851332},
+		{"exp", "exp(x) - 10", "exp(x)", 0, 5, math.Log(10)},
+		// Пологая функция: у обычного метода хорд один конец «залипает»
+		{"x^10", "x^10 - 1", "10*x^9", 0, 1.3, 1},
+		{"root at end", "x^2 - 4", "2*x", 0, 2, 2},
+	}
+	methods := []struct {
+		name     string
+		solve    func(f, df func(float64) float64, a, b, eps float64) (float64, int, [][]float64)
+		maxIters int
+	}{
+		{"brent", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+			return brentMethod(f, a, b, eps)
+		}, 50},
+		{"dekker", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+			return dekkerMethod(f, a, b, eps)
+		}, 100},
+		{"ridders", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+			return riddersMethod(f, a, b, eps)
+		}, 50},
+		{"illinois", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+			return illinoisMethod(f, a, b, eps)
+		}, 50},
+		{"anderson-bjorck", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+			return andersonBjorckMethod(f, a, b, eps)
+		}, 50},
+		{"newton-bisection", newtonBisectionMethod, 50},
+	}
+	const eps = 1e-10
+	for _, tt := range tests {
+		f, df := mustParse(tt.f).Func1(), mustParse(tt.df).Func1()
+		for _, m := range methods {
+			x, iterations, _ := m.solve(f, df, tt.a, tt.b, eps)
+			if math.Abs(x-tt.root) > 1e-8 {
+				t.Errorf("%s, %s: x = %.12f, want %.12f", tt.name, m.name, x, tt.root)
+			}
+			if iterations > m.maxIters {
+				t.Errorf("%s, %s: %d iterations, want at most %d", tt.name, m.name, iterations, m.maxIters)
+			}
+		}
+	}
+}
+
+func TestOpenRootFinders(t *testing.T) {
+	tests := []struct {
+		name  string
+		f, df string
+		x0    float64
+		root  float64
+	}{
+		{"cubic", "x^3 - 1.89*x^2 - 2*x + 1.76", "3*x^2 - 3.78*x - 2", 0.5, 0.6299705394},
+		{"cos", "cos(x) - x", "-sin(x) - 1", 1, 0.7390851332},
+		{"sqrt2", "x^2 - 2", "2*x", 1, math.Sqrt2},
+	}
+	const eps = 1e-10
+	for _, tt := range tests {
+		f, df := mustParse(tt.f).Func1(), mustParse(tt.df).Func1()
+		if x, _, _ := newtonMethod(f, df, tt.x0, eps); math.Abs(x-tt.root) > 1e-8 {
+			t.Errorf("%s, newton: x = %.12f, want %.12f", tt.name, x, tt.root)
+		}
+		if x, _, _ := secantMethod(f, tt.x0, tt.x0+0.1, eps); math.Abs(x-tt.root) > 1e-8 {
+			t.Errorf("%s, secant: x = %.12f, want %.12f", tt.name, x, tt.root)
+		}
+	}
+}
@@ -2,74 +2,131 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"math"
+	"math/cmplx"
 	"os"
 	"strconv"
 	"strings"
 )
 
-// Функции для уравнения: x^3 - 1.89x^2 - 2x + 1.76 = 0
-func f1(x float64) float64 {
-	return math.Pow(x, 3) - 1.89*math.Pow(x, 2) - 2*x + 1.76
+// Уравнения, предлагаемые на выбор. Производные строятся символьно
+// (см. diff.go), поэтому здесь задаются только сами функции.
+var equations = []string{
+	"x^3 - 1.89x^2 - 2x + 1.76",
+	"sin(x) - 0.5x",
+	"x^2 - ln(x+1)",
 }
 
-func df1(x float64) float64 {
-	return 3*math.Pow(x, 2) - 3.78*x - 2
+// Системы нелинейных уравнений. Переменные — x, y, z по числу уравнений,
+// матрица Якоби строится символьно.
+var systems = [][]string{
+	{"tan(x*y + 0.3) = x^2", "0.9x^2 + 2y^2 = 1"},
+	{"sin(x + y) - 1.2x", "x^2 + y^2 = 1"},
+	{"3x - cos(y*z) - 0.5", "x^2 - 81(y + 0.1)^2 + sin(z) + 1.06", "exp(-x*y) + 20z + (10pi - 3)/3"},
 }
 
-// Для метода простой итерации (с параметром alpha)
-func phi1(x float64, alpha float64) float64 {
-	return x - alpha*f1(x)
+// Преобразования x = φ(x) для метода простой итерации.
+// Они записаны через дуальные числа, поэтому производные φ для проверки
+// условия сходимости вычисляются автоматически.
+var systemPhis = [][]func([]Dual) Dual{
+	{
+		// x = sqrt((1 - 2y²) / 0.9) из второго уравнения
+		func(v []Dual) Dual {
+			return DSqrt(Const(1).Sub(v[1].Mul(v[1]).Scale(2)).Scale(1 / 0.9))
+		},
+		// y = (atan(x²) - 0.3) / x из первого уравнения
+		func(v []Dual) Dual {
+			return DAtan(v[0].Mul(v[0])).Sub(Const(0.3)).Div(v[0])
+		},
+	},
+	{
+		// x = sin(x + y) / 1.2
+		func(v []Dual) Dual { return DSin(v[0].Add(v[1])).Scale(1 / 1.2) },
+		// y = sqrt(1 - x²)
+		func(v []Dual) Dual { return DSqrt(Const(1).Sub(v[0].Mul(v[0]))) },
+	},
+	{
+		// x = cos(yz)/3 + 1/6
+		func(v []Dual) Dual { return DCos(v[1].Mul(v[2])).Scale(1.0 / 3).Add(Const(1.0 / 6)) },
+		// y = sqrt(x² + sin(z) + 1.06)/9 - 0.1
+		func(v []Dual) Dual {
+			return DSqrt(v[0].Mul(v[0]).Add(DSin(v[2])).Add(Const(1.06))).Scale(1.0 / 9).Sub(Const(0.1))
+		},
+		// z = -exp(-xy)/20 - (10π - 3)/60
+		func(v []Dual) Dual {
+			return DExp(v[0].Mul(v[1]).Neg()).Scale(-1.0 / 20).Sub(Const((10*math.Pi - 3) / 60))
+		},
+	},
 }
 
-func dphi1(x float64, alpha float64) float64 {
-	return 1 - alpha*df1(x)
-}
-
-// Дополнительные тестовые функции
-func f2(x float64) float64 {
-	return math.Sin(x) - 0.5*x
-}
-
-func df2(x float64) float64 {
-	return math.Cos(x) - 0.5
-}
-
-func f3(x float64) float64 {
-	return math.Pow(x, 2) - math.Log(x+1)
-}
-
-func df3(x float64) float64 {
-	return 2*x - 1/(x+1)
-}
-
-// Функции для системы нелинейных уравнений:
-// tan(xy+0.3)= x², 0.9x²+2y²=1
-func sys1F1(x, y float64) float64 {
-	return math.Tan(x*y+0.3) - x*x
-}
-
-func sys1F2(x, y float64) float64 {
-	return 0.9*math.Pow(x, 2) + 2*math.Pow(y, 2) - 1
+// systemVars возвращает имена неизвестных системы из n уравнений:
+// x, y, z для n ≤ 3 и x1, ..., xn для больших систем.
+func systemVars(n int) []string {
+	if n <= 3 {
+		return []string{"x", "y", "z"}[:n]
+	}
+	vars := make([]string, n)
+	for i := range vars {
+		vars[i] = fmt.Sprintf("x%d", i+1)
+	}
+	return vars
 }
 
-// Частные производные для системы
-func sys1DF1dx(x, y float64) float64 {
-	// Производная tan(u) равна sec^2(u)*u'
-	return y*(1/(math.Cos(x*y+0.3)*math.Cos(x*y+0.3))) - 2*x
+// formatVector форматирует вектор как (a, b, ...)
+func formatVector(v []float64, format string) string {
+	parts := make([]string, len(v))
+	for i, x := range v {
+		parts[i] = fmt.Sprintf(format, x)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
 }
 
-func sys1DF1dy(x, y float64) float64 {
-	return x * (1 / (math.Cos(x*y+0.3) * math.Cos(x*y+0.3)))
+// Гибридные методы (см. hybrid.go) в порядке пунктов меню 6-11
+var hybridMethods = []struct {
+	name  string
+	solve func(f, df func(float64) float64, a, b, eps float64) (float64, int, [][]float64)
+}{
+	{"Метод Брента", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+		return brentMethod(f, a, b, eps)
+	}},
+	{"Метод Деккера", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+		return dekkerMethod(f, a, b, eps)
+	}},
+	{"Метод Риддерса", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+		return riddersMethod(f, a, b, eps)
+	}},
+	{"Иллинойсский метод", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+		return illinoisMethod(f, a, b, eps)
+	}},
+	{"Метод Андерсона–Бьорка", func(f, _ func(float64) float64, a, b, eps float64) (float64, int, [][]float64) {
+		return andersonBjorckMethod(f, a, b, eps)
+	}},
+	{"Метод Ньютона с делением пополам", newtonBisectionMethod},
 }
 
-func sys1DF2dx(x, y float64) float64 {
-	return 1.8 * x
+// mustParse разбирает встроенное выражение; ошибка здесь — ошибка в коде.
+func mustParse(src string, vars ...string) *Expr {
+	expr, err := ParseExpr(src, vars...)
+	if err != nil {
+		panic(fmt.Sprintf("%s: %v", src, err))
+	}
+	return expr
 }
 
-func sys1DF2dy(x, y float64) float64 {
-	return 4 * y
+// jacobianNorm возвращает ‖J‖∞ — максимальную сумму модулей по строкам.
+// Для преобразования φ значение меньше единицы означает сжатие.
+func jacobianNorm(jac [][]float64) float64 {
+	norm := 0.0
+	for _, row := range jac {
+		sum := 0.0
+		for _, v := range row {
+			sum += math.Abs(v)
+		}
+		norm = math.Max(norm, sum)
+	}
+	return norm
 }
 
 // Метод половинного деления
@@ -221,134 +278,160 @@ func secantMethod(f func(float64) float64, x0, x1, eps float64) (float64, int, [
 	return x1, iterCount, iterations
 }
 
-// Метод простой итерации для одного уравнения
-func simpleIterationMethod(f func(float64) float64, x0, eps, alpha float64) (float64, int, [][]float64, bool) {
-	// Проверка условия сходимости
-	a := x0 - 1
-	b := x0 + 1
-	maxDPhi := 0.0
-	for x := a; x <= b; x += (b - a) / 100 {
-		val := math.Abs(dphi1(x, alpha))
-		if val > maxDPhi {
-			maxDPhi = val
-		}
-	}
-	convergent := maxDPhi < 1.0
-
-	var iterations [][]float64
-	x := x0
-	iterCount := 0
-
-	for {
-		iterCount++
-		xNew := phi1(x, alpha)
-		iterations = append(iterations, []float64{x, xNew, f(xNew), math.Abs(xNew - x)})
+// IterationSetup — преобразование φ(x) = x + λ·f(x) для метода простой
+// итерации и результат проверки достаточных условий сходимости на [a, b]:
+// q = max|φ'(x)| < 1 и φ([a, b]) ⊂ [a, b].
+type IterationSetup struct {
+	A, B           float64
+	Lambda         float64
+	Q              float64
+	PhiMin, PhiMax float64  // образ отрезка [a, b] при отображении φ
+	Problems       []string // причины, по которым сходимость не гарантирована
+}
 
-		if math.Abs(xNew-x) < eps {
-			x = xNew
-			break
-		}
-		x = xNew
+// Convergent сообщает, выполнены ли достаточные условия сходимости.
+func (s IterationSetup) Convergent() bool {
+	return len(s.Problems) == 0
+}
 
-		if iterCount > 100 || math.IsNaN(x) || math.Abs(x) > 1e10 {
-			fmt.Println("Предупреждение: метод расходится или достигнуто максимальное число итераций")
-			break
+func (s IterationSetup) String() string {
+	lines := []string{
+		fmt.Sprintf("φ(x) = x + λ·f(x), λ = %.6f", s.Lambda),
+		fmt.Sprintf("q = max|φ'(x)| на [%.6f, %.6f] = %.6f", s.A, s.B, s.Q),
+		fmt.Sprintf("φ([a, b]) = [%.6f, %.6f]", s.PhiMin, s.PhiMax),
+	}
+	if s.Convergent() {
+		lines = append(lines, "Условия сходимости выполнены: q < 1, φ отображает [a, b] в себя")
+	} else {
+		lines = append(lines, "Внимание: сходимость не гарантирована:")
+		for _, p := range s.Problems {
+			lines = append(lines, "  - "+p)
 		}
 	}
-
-	return x, iterCount, iterations, convergent
+	return strings.Join(lines, "\n")
 }
 
-// Метод Ньютона для системы нелинейных уравнений
-func newtonSystemMethod(
-	f1 func(float64, float64) float64,
-	f2 func(float64, float64) float64,
-	df1dx func(float64, float64) float64,
-	df1dy func(float64, float64) float64,
-	df2dx func(float64, float64) float64,
-	df2dy func(float64, float64) float64,
-	x0, y0, eps float64) (float64, float64, int, [][]float64) {
-
-	var iterations [][]float64
-
-	x, y := x0, y0
-	iterCount := 0
+// iterationGrid — число узлов, по которым проверяются условия сходимости
+const iterationGrid = 1000
 
-	for {
-		iterCount++
-		fVal1 := f1(x, y)
-		fVal2 := f2(x, y)
-
-		j11 := df1dx(x, y)
-		j12 := df1dy(x, y)
-		j21 := df2dx(x, y)
-		j22 := df2dy(x, y)
+// iterationNodes возвращает узлы сетки на [a, b], по которым проверяются
+// условия сходимости.
+func iterationNodes(a, b float64) []float64 {
+	xs := make([]float64, iterationGrid+1)
+	for i := range xs {
+		xs[i] = a + (b-a)*float64(i)/iterationGrid
+	}
+	return xs
+}
 
-		det := j11*j22 - j12*j21
-		if math.Abs(det) < 1e-10 {
-			fmt.Println("Предупреждение: матрица Якоби вырождена, метод может не сходиться")
-			return x, y, iterCount, iterations
+// setupSimpleIteration выбирает λ = -1/max|f'| при f' > 0 и 1/max|f'| при
+// f' < 0 на [a, b]. Тогда φ'(x) = 1 + λ·f'(x) лежит в [0, 1 - min|f'|/max|f'|],
+// и q < 1, если f' не обращается в ноль. Условия проверяются на сетке.
+// Если f' не определена или тождественно равна нулю, λ выбрать нельзя:
+// Lambda = NaN, причина — в Problems.
+func setupSimpleIteration(f, df func(float64) float64, a, b float64) IterationSetup {
+	setup := IterationSetup{A: a, B: b}
+	xs := iterationNodes(a, b)
+
+	maxD, minD := 0.0, math.Inf(1)
+	positive, negative := 0, 0
+	for _, x := range xs {
+		d := df(x)
+		if math.IsNaN(d) || math.IsInf(d, 0) {
+			setup.Problems = append(setup.Problems, fmt.Sprintf("f'(x) не определена в точке x = %.6f", x))
+			setup.Lambda, setup.Q = math.NaN(), math.NaN()
+			setup.PhiMin, setup.PhiMax = math.NaN(), math.NaN()
+			return setup
 		}
-
-		// Вычисляем шаг (dx, dy)
-		dx := (-j22*fVal1 + j12*fVal2) / det
-		dy := (j21*fVal1 - j11*fVal2) / det
-
-		xNew := x + dx
-		yNew := y + dy
-
-		iterations = append(iterations, []float64{x, y, xNew, yNew, math.Abs(dx), math.Abs(dy)})
-
-		if math.Hypot(dx, dy) < eps {
-			x, y = xNew, yNew
-			break
+		maxD = math.Max(maxD, math.Abs(d))
+		minD = math.Min(minD, math.Abs(d))
+		if d > 0 {
+			positive++
+		} else if d < 0 {
+			negative++
 		}
+	}
+	if maxD == 0 {
+		setup.Problems = append(setup.Problems, "f'(x) = 0 на всём отрезке")
+		setup.Lambda, setup.Q = math.NaN(), math.NaN()
+		setup.PhiMin, setup.PhiMax = math.NaN(), math.NaN()
+		return setup
+	}
+	if positive > 0 && negative > 0 {
+		setup.Problems = append(setup.Problems,
+			"f'(x) меняет знак на отрезке: ни при каком λ условие |φ'(x)| < 1 не выполняется везде; сузьте отрезок")
+	} else if minD == 0 {
+		setup.Problems = append(setup.Problems, "f'(x) обращается в ноль на отрезке (кратный корень или экстремум)")
+	}
 
-		x, y = xNew, yNew
-
-		if iterCount > 100 {
-			fmt.Println("Предупреждение: достигнуто максимальное число итераций")
-			break
-		}
+	setup.Lambda = -1 / maxD
+	if negative > positive {
+		setup.Lambda = 1 / maxD
 	}
+	setup.check(f, df, xs)
+	return setup
+}
 
-	return x, y, iterCount, iterations
+// setupWithLambda проверяет условия сходимости для λ, заданного
+// пользователем.
+func setupWithLambda(f, df func(float64) float64, a, b, lambda float64) IterationSetup {
+	setup := IterationSetup{A: a, B: b, Lambda: lambda}
+	setup.check(f, df, iterationNodes(a, b))
+	return setup
 }
 
-// Метод простой итерации для системы нелинейных уравнений
-func simpleIterationSystemMethod(
-	f1 func(float64, float64) float64,
-	f2 func(float64, float64) float64,
-	phi1 func(float64, float64) float64,
-	phi2 func(float64, float64) float64,
-	x0, y0, eps float64) (float64, float64, int, [][]float64) {
+// check вычисляет q и φ([a, b]) по узлам xs и дополняет Problems.
+func (s *IterationSetup) check(f, df func(float64) float64, xs []float64) {
+	s.Q = 0
+	s.PhiMin, s.PhiMax = math.Inf(1), math.Inf(-1)
+	for _, x := range xs {
+		s.Q = math.Max(s.Q, math.Abs(1+s.Lambda*df(x)))
+		phi := x + s.Lambda*f(x)
+		s.PhiMin = math.Min(s.PhiMin, phi)
+		s.PhiMax = math.Max(s.PhiMax, phi)
+	}
+	switch {
+	case math.IsNaN(s.Q):
+		s.Problems = append(s.Problems, "q не определено: f'(x) не определена на отрезке")
+	case s.Q >= 1:
+		s.Problems = append(s.Problems, fmt.Sprintf("q = %.6f ≥ 1: отображение φ не сжимающее", s.Q))
+	}
+	if math.IsNaN(s.PhiMin) || math.IsNaN(s.PhiMax) {
+		s.Problems = append(s.Problems, "φ(x) не определена на части отрезка")
+	} else if s.PhiMin < s.A || s.PhiMax > s.B {
+		s.Problems = append(s.Problems,
+			fmt.Sprintf("φ выводит за пределы отрезка: φ([a, b]) = [%.6f, %.6f]; возможно, на отрезке нет корня", s.PhiMin, s.PhiMax))
+	}
+}
 
-	var iterations [][]float64
+// Метод простой итерации для одного уравнения: x = φ(x) = x + λ·f(x) с λ из
+// setup (см. setupSimpleIteration и setupWithLambda).
+func simpleIterationMethod(f func(float64) float64, setup IterationSetup, x0, eps float64) (float64, int, [][]float64) {
+	lambda := setup.Lambda
+	phi := func(x float64) float64 { return x + lambda*f(x) }
 
-	x, y := x0, y0
+	var iterations [][]float64
+	x := x0
 	iterCount := 0
 
 	for {
 		iterCount++
-		xNew := phi1(x, y)
-		yNew := phi2(x, y)
-
-		iterations = append(iterations, []float64{x, y, xNew, yNew, math.Abs(xNew - x), math.Abs(yNew - y)})
+		xNew := phi(x)
+		iterations = append(iterations, []float64{x, xNew, f(xNew), math.Abs(xNew - x)})
 
-		if math.Hypot(xNew-x, yNew-y) < eps {
-			x, y = xNew, yNew
+		if math.Abs(xNew-x) < eps {
+			x = xNew
 			break
 		}
+		x = xNew
 
-		x, y = xNew, yNew
-
-		if iterCount > 100 || math.IsNaN(x) || math.IsNaN(y) || math.Abs(x) > 1e10 || math.Abs(y) > 1e10 {
+		if iterCount > 100 || math.IsNaN(x) || math.Abs(x) > 1e10 {
 			fmt.Println("Предупреждение: метод расходится или достигнуто максимальное число итераций")
 			break
 		}
 	}
 
-	return x, y, iterCount, iterations
+	return x, iterCount, iterations
 }
 
 // Функция для проверки существования корня на интервале
@@ -356,6 +439,29 @@ func rootExists(f func(float64) float64, a, b float64) bool {
 	return f(a)*f(b) <= 0
 }
 
+// Функция для выбора отрезка со сменой знака внутри [a, b], если на концах
+// знак одинаковый. Возвращает false, если таких отрезков нет.
+func chooseBracket(reader *bufio.Reader, f func(float64) float64, a, b float64) (Bracket, bool) {
+	var found []Bracket
+	for _, br := range scanBrackets(f, a, b, (b-a)/200) {
+		if !br.Touch && br.A != br.B {
+			found = append(found, br)
+		}
+	}
+	if len(found) == 0 {
+		return Bracket{}, false
+	}
+	fmt.Println("Внутри интервала найдены отрезки со сменой знака:")
+	for i, br := range found {
+		fmt.Printf("%d. [%.6f, %.6f]\n", i+1, br.A, br.B)
+	}
+	i := 1
+	if len(found) > 1 {
+		i = readInt(reader, fmt.Sprintf("Выберите отрезок (1-%d)", len(found)), 1, 1, len(found))
+	}
+	return found[i-1], true
+}
+
 // Функция записи результатов в файл
 func writeToFile(filename string, content string) error {
 	file, err := os.Create(filename)
@@ -415,6 +521,23 @@ func readFloat(reader *bufio.Reader, prompt string, defaultValue float64) float6
 	return value
 }
 
+// readLambda запрашивает λ для метода простой итерации. Значения по
+// умолчанию нет: при пустом или некорректном вводе ok = false.
+func readLambda(reader *bufio.Reader) (float64, bool) {
+	fmt.Print("Введите λ для φ(x) = x + λ·f(x) (пустой ввод — отказаться от метода): ")
+	inputStr, _ := reader.ReadString('\n')
+	inputStr = strings.Replace(strings.TrimSpace(inputStr), ",", ".", -1)
+	if inputStr == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(inputStr, 64)
+	if err != nil || value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		fmt.Println("Некорректный ввод: λ должно быть ненулевым числом")
+		return 0, false
+	}
+	return value, true
+}
+
 // Функция для чтения целого числа с поддержкой значения по умолчанию
 func readInt(reader *bufio.Reader, prompt string, defaultValue, minValue, maxValue int) int {
 	fmt.Printf("%s (по умолчанию %d): ", prompt, defaultValue)
@@ -435,6 +558,24 @@ func readInt(reader *bufio.Reader, prompt string, defaultValue, minValue, maxVal
 	return value
 }
 
+// readYesNo задаёт вопрос с ответом y/n; пустой ввод означает ответ по
+// умолчанию.
+func readYesNo(reader *bufio.Reader, prompt string, defaultValue bool) bool {
+	def := "n"
+	if defaultValue {
+		def = "y"
+	}
+	fmt.Printf("%s (y/n, по умолчанию %s): ", prompt, def)
+	inputStr, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(inputStr)) {
+	case "":
+		return defaultValue
+	case "y":
+		return true
+	}
+	return false
+}
+
 // Функция для чтения двух чисел (интервала) с поддержкой значений по умолчанию
 func readInterval(reader *bufio.Reader, prompt string, defaultA, defaultB float64) (float64, float64) {
 	fmt.Printf("%s (по умолчанию [%.6f, %.6f]): ", prompt, defaultA, defaultB)
@@ -466,63 +607,231 @@ func readInterval(reader *bufio.Reader, prompt string, defaultA, defaultB float6
 	return a, b
 }
 
+// Функция для чтения вектора из n чисел через пробел с поддержкой значений
+// по умолчанию
+func readVector(reader *bufio.Reader, prompt string, defaults []float64) []float64 {
+	fmt.Printf("%s (по умолчанию %s): ", prompt, formatVector(defaults, "%g"))
+	inputStr, _ := reader.ReadString('\n')
+	inputStr = strings.TrimSpace(inputStr)
+
+	// Если ввод пустой, используем значения по умолчанию
+	if inputStr == "" {
+		return defaults
+	}
+
+	parts := strings.Fields(strings.Replace(inputStr, ",", ".", -1))
+	if len(parts) != len(defaults) {
+		fmt.Printf("Нужно %d чисел! Используются значения по умолчанию\n", len(defaults))
+		return defaults
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			fmt.Println("Некорректные значения! Используются значения по умолчанию")
+			return defaults
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// Функция для чтения комплексного числа в виде "Re Im" с поддержкой
+// значения по умолчанию
+func readComplex(reader *bufio.Reader, prompt string, defaultValue complex128) complex128 {
+	v := readVector(reader, prompt, []float64{real(defaultValue), imag(defaultValue)})
+	return complex(v[0], v[1])
+}
+
+// Функция для форматирования комплексного числа
+func formatComplex(z complex128) string {
+	if imag(z) < 0 {
+		return fmt.Sprintf("%.10f - %.10fi", real(z), -imag(z))
+	}
+	return fmt.Sprintf("%.10f + %.10fi", real(z), imag(z))
+}
+
+// Функция для чтения многочлена: коэффициентов или выражения от x.
+// Повторяет запрос, пока многочлен не разобран.
+func readPoly(reader *bufio.Reader, prompt string) Poly {
+	for {
+		fmt.Print(prompt + ": ")
+		inputStr, err := reader.ReadString('\n')
+		inputStr = strings.TrimSpace(inputStr)
+		if inputStr == "" && err != nil {
+			fmt.Println("\nВвод завершён")
+			os.Exit(1)
+		}
+		p, err := ParsePoly(inputStr)
+		if err != nil {
+			fmt.Println("Ошибка в многочлене:", err)
+			continue
+		}
+		return p
+	}
+}
+
+// Подсказка по записи выражений
+func printExprHelp() {
+	fmt.Println("Функции: sin cos tan cot asin acos atan sinh cosh tanh exp ln lg log2 sqrt cbrt abs")
+	fmt.Println("Константы: pi, e. Пример: x^3 - 1.89*x^2 + sin(x) = 0")
+}
+
+// Функция для чтения выражения, введённого пользователем. Повторяет запрос,
+// пока выражение не разобрано.
+func readEquation(reader *bufio.Reader, prompt string, vars ...string) *Expr {
+	for {
+		fmt.Print(prompt + ": ")
+		inputStr, err := reader.ReadString('\n')
+		inputStr = strings.TrimSpace(inputStr)
+		if inputStr == "" && err != nil {
+			fmt.Println("\nВвод завершён")
+			os.Exit(1)
+		}
+		expr, err := ParseExpr(inputStr, vars...)
+		if err != nil {
+			fmt.Println("Ошибка в выражении:", err)
+			continue
+		}
+		return expr
+	}
+}
+
 func main() {
+	eqFlag := flag.String("eq", "", "уравнение от x, например \"x^3 - 1.89*x^2 + sin(x)\"")
+	flag.Parse()
+	if *eqFlag == "" && flag.NArg() > 0 {
+		*eqFlag = strings.Join(flag.Args(), " ")
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	fmt.Println("Решение нелинейных уравнений и систем")
 	fmt.Println("=======================================")
-	fmt.Println("1. Решить нелинейное уравнение")
-	fmt.Println("2. Решить систему нелинейных уравнений")
 
-	choice := readInt(reader, "Введите ваш выбор (1 или 2)", 1, 1, 2)
+	var userExpr *Expr
+	if *eqFlag != "" {
+		expr, err := ParseExpr(*eqFlag)
+		if err != nil {
+			fmt.Println("Ошибка в уравнении:", err)
+			os.Exit(2)
+		}
+		userExpr = expr
+	}
+
+	choice := 1
+	if userExpr == nil {
+		fmt.Println("1. Решить нелинейное уравнение")
+		fmt.Println("2. Решить систему нелинейных уравнений")
+		fmt.Println("3. Найти комплексные корни (многочлены и аналитические функции)")
+		choice = readInt(reader, "Введите ваш выбор (1-3)", 1, 1, 3)
+	}
 
 	if choice == 1 {
-		fmt.Println("\nВыберите уравнение:")
-		fmt.Println("1. x^3 - 1.89x^2 - 2x + 1.76 = 0")
-		fmt.Println("2. sin(x) - 0.5x = 0")
-		fmt.Println("3. x^2 - ln(x+1) = 0")
-
-		eqChoice := readInt(reader, "Введите ваш выбор (1-3)", 1, 1, 3)
-
-		var f func(float64) float64
-		var df func(float64) float64
-		var eqStr string
-		switch eqChoice {
-		case 1:
-			f = f1
-			df = df1
-			eqStr = "x^3 - 1.89x^2 - 2x + 1.76 = 0"
-		case 2:
-			f = f2
-			df = df2
-			eqStr = "sin(x) - 0.5x = 0"
-		case 3:
-			f = f3
-			df = df3
-			eqStr = "x^2 - ln(x+1) = 0"
+		eqChoice := 4
+		if userExpr == nil {
+			fmt.Println("\nВыберите уравнение:")
+			for i, src := range equations {
+				fmt.Printf("%d. %s = 0\n", i+1, src)
+			}
+			fmt.Println("4. Ввести своё уравнение")
+
+			eqChoice = readInt(reader, "Введите ваш выбор (1-4)", 1, 1, 4)
 		}
 
+		var expr *Expr
+		if eqChoice == 4 {
+			if userExpr == nil {
+				printExprHelp()
+				userExpr = readEquation(reader, "Введите уравнение от x")
+			}
+			expr = userExpr
+		} else {
+			expr = mustParse(equations[eqChoice-1])
+		}
+		derivative := expr.Derivative()
+		f := expr.Func1()
+		df := derivative.Func1()
+		eqStr := expr.String() + " = 0"
+		derivStr := "f'(x) = " + derivative.String()
+		fmt.Println("\nУравнение:", eqStr)
+		fmt.Println(derivStr)
+
 		fmt.Println("\nВыберите метод решения:")
 		fmt.Println("1. Метод половинного деления")
 		fmt.Println("2. Метод хорд")
 		fmt.Println("3. Метод Ньютона")
 		fmt.Println("4. Метод секущих")
 		fmt.Println("5. Метод простой итерации")
+		for i, m := range hybridMethods {
+			fmt.Printf("%d. %s\n", i+6, m.name)
+		}
+		fmt.Println("12. Найти все корни на интервале")
 
-		methodChoice := readInt(reader, "Введите ваш выбор (1-5)", 1, 1, 5)
+		methodChoice := readInt(reader, "Введите ваш выбор (1-12)", 1, 1, 12)
 
-		if methodChoice != 4 {
+		if methodChoice == 12 {
+			a, b := readInterval(reader, "Введите интервал [a, b] (через пробел)", -3.0, 3.0)
+			step := readFloat(reader, "Введите шаг сканирования", (b-a)/200)
+			if smallest := scanStep(a, b, 0); step < smallest {
+				fmt.Printf("Шаг слишком мал: на [a, b] помещается не более %d узлов, используется шаг %g\n", maxScanPoints, smallest)
+				step = smallest
+			}
+			eps := readFloat(reader, "Введите точность", 0.0001)
+
+			fmt.Println("\nПоиск всех корней...")
+			roots := findAllRoots(f, a, b, step, eps)
+			resultStr := fmt.Sprintf("Уравнение: %s\nИнтервал: [%.6f, %.6f]\nШаг сканирования: %.6f\nНайдено корней: %d",
+				eqStr, a, b, step, len(roots))
+			tableStr := formatRoots(roots)
+			fmt.Println("\nРезультаты:")
+			fmt.Println(resultStr)
+			if len(roots) > 0 {
+				fmt.Println("\nКорни:")
+				fmt.Println(tableStr)
+			}
+
+			var xs []float64
+			for _, r := range roots {
+				xs = append(xs, r.X)
+			}
+			if readYesNo(reader, "\nСохранить график функции в PNG?", true) {
+				createFunctionPlot(f, a, b, xs, "f(x) = "+expr.String(), "nonlinear_function.png")
+			}
+
+			fmt.Print("\nСохранить результаты в файл? (y/n, по умолчанию y): ")
+			saveChoice, _ := reader.ReadString('\n')
+			saveChoice = strings.TrimSpace(saveChoice)
+			if saveChoice == "" || strings.ToLower(saveChoice) == "y" {
+				filename := "nonlinear_equation_results.txt"
+				content := resultStr + "\n\nКорни:\n" + tableStr
+				if err := writeToFile(filename, content); err != nil {
+					fmt.Println("Ошибка сохранения в файл:", err)
+				} else {
+					fmt.Println("Результаты сохранены в", filename)
+				}
+			}
+		} else if methodChoice != 4 {
 			// Для методов, требующих интервал
 			a, b := readInterval(reader, "Введите интервал [a, b] (через пробел)", -1.0, 1.0)
 			eps := readFloat(reader, "Введите точность", 0.0001)
 
 			if methodChoice != 5 && !rootExists(f, a, b) {
-				fmt.Println("На данном интервале нет корня! Значения функции на концах интервала имеют одинаковый знак.")
-				fmt.Println("Попробуйте другой интервал.")
-				a, b = readInterval(reader, "Введите интервал [a, b] (через пробел)", -2.0, 2.0)
-				if !rootExists(f, a, b) {
-					fmt.Println("На данном интервале тоже нет корня. Использую метод простой итерации.")
-					methodChoice = 5 // Переход к методу простой итерации
+				fmt.Println("Значения функции на концах интервала имеют одинаковый знак.")
+				br, ok := chooseBracket(reader, f, a, b)
+				if !ok {
+					fmt.Println("Попробуйте другой интервал.")
+					a, b = readInterval(reader, "Введите интервал [a, b] (через пробел)", -2.0, 2.0)
+					if !rootExists(f, a, b) {
+						br, ok = chooseBracket(reader, f, a, b)
+					}
+				}
+				if ok {
+					a, b = br.A, br.B
+				} else if !rootExists(f, a, b) {
+					fmt.Printf("На отрезке [%.6f, %.6f] корень не найден: отрезков со сменой знака нет.\n", a, b)
+					fmt.Println("Корни чётной кратности можно найти пунктом 12 (все корни на интервале).")
+					return
 				}
 			}
 
@@ -531,11 +840,18 @@ func main() {
 			var iterData [][]float64
 			var resultStr, tableStr string
 			var headers []string
+			var xs []float64 // последовательность приближений для диагностики
+			var setup IterationSetup
+			var diag Diagnostics
 
 			switch methodChoice {
 			case 1:
 				fmt.Println("\nРешение методом половинного деления...")
 				root, iterCount, iterData = bisectionMethod(f, a, b, eps)
+				xs = sequence(iterData, 2)
+				// Середины отрезков приближаются к корню неравномерно; порядок
+				// оценивается по длинам отрезков, ограничивающим ошибку
+				diag = diagnoseSteps(column(iterData, 6))
 				headers = []string{"a", "b", "x", "f(a)", "f(b)", "f(x)", "|b-a|"}
 				resultStr = fmt.Sprintf("Уравнение: %s\nМетод: Метод половинного деления\nИнтервал: [%.6f, %.6f]\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
 					eqStr, a, b, root, f(root), iterCount)
@@ -543,6 +859,7 @@ func main() {
 			case 2:
 				fmt.Println("\nРешение методом хорд...")
 				root, iterCount, iterData = chordMethod(f, a, b, eps)
+				xs = sequence(iterData, 2)
 				headers = []string{"a", "b", "x", "f(a)", "f(b)", "f(x)", "|xₖ₊₁-xₖ|"}
 				resultStr = fmt.Sprintf("Уравнение: %s\nМетод: Метод хорд\nИнтервал: [%.6f, %.6f]\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
 					eqStr, a, b, root, f(root), iterCount)
@@ -554,36 +871,83 @@ func main() {
 					x0 = b
 				}
 				root, iterCount, iterData = newtonMethod(f, df, x0, eps)
+				xs = sequence(iterData, 3, 0)
 				headers = []string{"xₖ", "f(xₖ)", "f'(xₖ)", "xₖ₊₁", "|xₖ₊₁-xₖ|"}
-				resultStr = fmt.Sprintf("Уравнение: %s\nМетод: Метод Ньютона\nНачальное приближение: %.6f\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
-					eqStr, x0, root, f(root), iterCount)
+				resultStr = fmt.Sprintf("Уравнение: %s\n%s\nМетод: Метод Ньютона\nНачальное приближение: %.6f\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
+					eqStr, derivStr, x0, root, f(root), iterCount)
 				tableStr = formatTable(headers, iterData)
 			case 5:
 				fmt.Println("\nРешение методом простой итерации...")
-				alpha := 0.1
+				setup = setupSimpleIteration(f, df, a, b)
+				if math.IsNaN(setup.Lambda) {
+					fmt.Println("По f'(x) нельзя выбрать λ:")
+					for _, p := range setup.Problems {
+						fmt.Println("  - " + p)
+					}
+					lambda, ok := readLambda(reader)
+					if !ok {
+						fmt.Println("Метод простой итерации не применён.")
+						return
+					}
+					setup = setupWithLambda(f, df, a, b, lambda)
+				}
 				x0 := (a + b) / 2
-				root, iterCount, iterData, convergent := simpleIterationMethod(f, x0, eps, alpha)
+				root, iterCount, iterData = simpleIterationMethod(f, setup, x0, eps)
+				xs = sequence(iterData, 1, 0)
 				headers = []string{"xₖ", "xₖ₊₁", "f(xₖ₊₁)", "|xₖ₊₁-xₖ|"}
-				convStr := "Условие сходимости выполнено"
-				if !convergent {
-					convStr = "Внимание: условие сходимости не выполнено"
+				convStr := setup.String()
+				resultStr = fmt.Sprintf("Уравнение: %s\n%s\nМетод: Метод простой итерации\n%s\nНачальное приближение: %.6f\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
+					eqStr, derivStr, convStr, x0, root, f(root), iterCount)
+				tableStr = formatTable(headers, iterData)
+			default:
+				m := hybridMethods[methodChoice-6]
+				fmt.Printf("\nРешение: %s...\n", m.name)
+				root, iterCount, iterData = m.solve(f, df, a, b, eps)
+				xs = sequence(iterData, 2)
+				headers = []string{"a", "b", "x", "f(x)", "|b-a|"}
+				if methodChoice == 11 {
+					headers[4] = "|xₖ₊₁-xₖ|"
 				}
-				resultStr = fmt.Sprintf("Уравнение: %s\nМетод: Метод простой итерации\n%s\nНачальное приближение: %.6f\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
-					eqStr, convStr, x0, root, f(root), iterCount)
+				resultStr = fmt.Sprintf("Уравнение: %s\nМетод: %s\nИнтервал: [%.6f, %.6f]\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
+					eqStr, m.name, a, b, root, f(root), iterCount)
 				tableStr = formatTable(headers, iterData)
 			}
 
+			if methodChoice != 1 {
+				diag = diagnoseIterates(xs)
+			}
+			diagStr := diag.String()
 			fmt.Println("\nРезультаты:")
 			fmt.Println(resultStr)
 			fmt.Println("\nТаблица итераций:")
 			fmt.Println(tableStr)
+			fmt.Println(diagStr)
+
+			// Графики функции и шагов метода
+			if readYesNo(reader, "\nСохранить графики функции и шагов метода в PNG?", true) {
+				pa, pb := plotRange(a, b, root)
+				createFunctionPlot(f, pa, pb, []float64{root}, "f(x) = "+expr.String(), "nonlinear_function.png")
+				switch methodChoice {
+				case 1:
+					createBisectionPlot(f, iterData, a, b, "nonlinear_steps.png")
+				case 2:
+					createChordPlot(f, iterData, a, b, "nonlinear_steps.png")
+				case 3:
+					createNewtonPlot(f, iterData, a, b, "nonlinear_steps.png")
+				case 5:
+					phi := func(x float64) float64 { return x + setup.Lambda*f(x) }
+					createCobwebPlot(phi, iterData, a, b, "nonlinear_steps.png")
+				default:
+					createIteratesPlot(f, xs, a, b, hybridMethods[methodChoice-6].name+": приближения", "nonlinear_steps.png")
+				}
+			}
 
 			fmt.Print("\nСохранить результаты в файл? (y/n, по умолчанию y): ")
 			saveChoice, _ := reader.ReadString('\n')
 			saveChoice = strings.TrimSpace(saveChoice)
 			if saveChoice == "" || strings.ToLower(saveChoice) == "y" {
 				filename := "nonlinear_equation_results.txt"
-				content := resultStr + "\n\nТаблица итераций:\n" + tableStr
+				content := resultStr + "\n\nТаблица итераций:\n" + tableStr + "\n" + diagStr
 				if err := writeToFile(filename, content); err != nil {
 					fmt.Println("Ошибка сохранения в файл:", err)
 				} else {
@@ -600,17 +964,26 @@ func main() {
 			resultStr := fmt.Sprintf("Уравнение: %s\nМетод: Метод секущих\nНачальные приближения: %.6f, %.6f\nКорень: %.6f\nf(корень): %.10f\nЧисло итераций: %d",
 				eqStr, x0, x1, root, f(root), iterCount)
 			tableStr := formatTable(headers, iterData)
+			diagStr := diagnoseIterates(sequence(iterData, 2, 0, 1)).String()
 			fmt.Println("\nРезультаты:")
 			fmt.Println(resultStr)
 			fmt.Println("\nТаблица итераций:")
 			fmt.Println(tableStr)
+			fmt.Println(diagStr)
+
+			// Графики функции и секущих
+			if readYesNo(reader, "\nСохранить графики функции и секущих в PNG?", true) {
+				pa, pb := plotRange(x0, x1, root)
+				createFunctionPlot(f, pa, pb, []float64{root}, "f(x) = "+expr.String(), "nonlinear_function.png")
+				createSecantPlot(f, iterData, x0, x1, "nonlinear_steps.png")
+			}
 
 			fmt.Print("\nСохранить результаты в файл? (y/n, по умолчанию y): ")
 			saveChoice, _ := reader.ReadString('\n')
 			saveChoice = strings.TrimSpace(saveChoice)
 			if saveChoice == "" || strings.ToLower(saveChoice) == "y" {
 				filename := "nonlinear_equation_results.txt"
-				content := resultStr + "\n\nТаблица итераций:\n" + tableStr
+				content := resultStr + "\n\nТаблица итераций:\n" + tableStr + "\n" + diagStr
 				if err := writeToFile(filename, content); err != nil {
 					fmt.Println("Ошибка сохранения в файл:", err)
 				} else {
@@ -618,113 +991,269 @@ func main() {
 				}
 			}
 		}
-	} else {
+	} else if choice == 2 {
 		// Решение системы нелинейных уравнений
 		fmt.Println("\nВыберите систему уравнений:")
 		fmt.Println("1. {tan(xy + 0.3) = x², 0.9x² + 2y² = 1}")
 		fmt.Println("2. {sin(x+y) - 1.2x = 0, x² + y² = 1}")
-
-		sysChoice := readInt(reader, "Введите ваш выбор (1-2)", 1, 1, 2)
+		fmt.Println("3. {3x - cos(yz) = 0.5, x² - 81(y + 0.1)² + sin(z) = -1.06, exp(-xy) + 20z = -(10π - 3)/3}")
+		fmt.Println("4. Ввести свою систему")
+
+		sysChoice := readInt(reader, "Введите ваш выбор (1-4)", 1, 1, 4)
+
+		var system []*Expr
+		if sysChoice == 4 {
+			n := readInt(reader, "Число уравнений (2-6)", 2, 2, 6)
+			vars := systemVars(n)
+			printExprHelp()
+			fmt.Println("Неизвестные:", strings.Join(vars, ", "))
+			for i := 0; i < n; i++ {
+				system = append(system, readEquation(reader, fmt.Sprintf("Уравнение %d", i+1), vars...))
+			}
+		} else {
+			srcs := systems[sysChoice-1]
+			for _, src := range srcs {
+				system = append(system, mustParse(src, systemVars(len(srcs))...))
+			}
+		}
+		n := len(system)
+		vars := system[0].Vars
+		eqStrs := make([]string, n)
+		for i, eq := range system {
+			eqStrs[i] = eq.String() + " = 0"
+		}
+		sysName := strings.Join(eqStrs, ", ")
+		F := exprSystem(system)
 
 		fmt.Println("\nВыберите метод решения:")
 		fmt.Println("1. Метод Ньютона")
-		fmt.Println("2. Метод простой итерации")
-
-		sysMethodChoice := readInt(reader, "Введите ваш выбор (1-2)", 1, 1, 2)
+		fmt.Println("2. Метод Ньютона с дроблением шага")
+		fmt.Println("3. Метод Бройдена")
+		fmt.Println("4. Метод простой итерации")
+
+		sysMethodChoice := readInt(reader, "Введите ваш выбор (1-4)", 1, 1, 4)
+
+		var phi VectorFunc
+		var phiJacobian JacobianFunc
+		if sysMethodChoice == 4 {
+			if sysChoice == 4 {
+				fmt.Printf("Задайте преобразование %s = φ(%s)\n", strings.Join(vars, ", "), strings.Join(vars, ", "))
+				phis := make([]*Expr, n)
+				for i, v := range vars {
+					phis[i] = readEquation(reader, fmt.Sprintf("%s = φ%d", v, i+1), vars...)
+				}
+				phi = exprSystem(phis)
+				_, phiJacobian = exprJacobian(phis)
+			} else {
+				phis := systemPhis[sysChoice-1]
+				phi = ADVector(phis)
+				phiJacobian = func(x []float64) [][]float64 {
+					_, jac := ADJacobian(phis, x)
+					return jac
+				}
+			}
+		}
 
-		x0, y0 := readInterval(reader, "Введите начальные приближения x0 и y0 (через пробел)", 0.5, 0.5)
+		defaults := make([]float64, n)
+		for i := range defaults {
+			defaults[i] = 0.5
+		}
+		x0 := readVector(reader, fmt.Sprintf("Введите начальное приближение (%s) через пробел", strings.Join(vars, ", ")), defaults)
 		eps := readFloat(reader, "Введите точность", 0.0001)
 
-		var resultStr, tableStr string
-		if sysMethodChoice == 1 {
-			fmt.Println("\nРешение системы методом Ньютона...")
-			if sysChoice == 1 {
-				x, y, iterCount, iterData := newtonSystemMethod(
-					sys1F1, sys1F2,
-					sys1DF1dx, sys1DF1dy,
-					sys1DF2dx, sys1DF2dy,
-					x0, y0, eps)
-				headers := []string{"xₖ", "yₖ", "xₖ₊₁", "yₖ₊₁", "|dx|", "|dy|"}
-				sysName := "tan(xy + 0.3) = x², 0.9x² + 2y² = 1"
-				resultStr = fmt.Sprintf("Система: %s\nМетод: Метод Ньютона\nНачальные приближения: (%.6f, %.6f)\nРешение: (%.6f, %.6f)\nНевязки: %.10f, %.10f\nЧисло итераций: %d",
-					sysName, x0, y0, x, y, sys1F1(x, y), sys1F2(x, y), iterCount)
-				tableStr = formatTable(headers, iterData)
-			} else {
-				// Пример второй системы (реализация по аналогии)
-				sinxyF1 := func(x, y float64) float64 { return math.Sin(x+y) - 1.2*x }
-				sinxyF2 := func(x, y float64) float64 { return math.Pow(x, 2) + math.Pow(y, 2) - 1 }
-				sinxyDF1dx := func(x, y float64) float64 { return math.Cos(x+y) - 1.2 }
-				sinxyDF1dy := func(x, y float64) float64 { return math.Cos(x + y) }
-				sinxyDF2dx := func(x, y float64) float64 { return 2 * x }
-				sinxyDF2dy := func(x, y float64) float64 { return 2 * y }
-
-				x, y, iterCount, iterData := newtonSystemMethod(
-					sinxyF1, sinxyF2,
-					sinxyDF1dx, sinxyDF1dy,
-					sinxyDF2dx, sinxyDF2dy,
-					x0, y0, eps)
-				headers := []string{"xₖ", "yₖ", "xₖ₊₁", "yₖ₊₁", "|dx|", "|dy|"}
-				sysName := "sin(x+y) - 1.2x = 0, x² + y² = 1"
-				resultStr = fmt.Sprintf("Система: %s\nМетод: Метод Ньютона\nНачальные приближения: (%.6f, %.6f)\nРешение: (%.6f, %.6f)\nНевязки: %.10f, %.10f\nЧисло итераций: %d",
-					sysName, x0, y0, x, y, sinxyF1(x, y), sinxyF2(x, y), iterCount)
-				tableStr = formatTable(headers, iterData)
+		var x []float64
+		var iterCount int
+		var iterData [][]float64
+		var headers []string
+		var methodStr, infoStr string
+		if sysMethodChoice != 4 {
+			opts := NewtonOptions{LineSearch: sysMethodChoice == 2, Broyden: sysMethodChoice == 3}
+			methodStr = []string{"Метод Ньютона", "Метод Ньютона с дроблением шага", "Метод Бройдена"}[sysMethodChoice-1]
+
+			jac, J := exprJacobian(system)
+			infoStr = "Матрица Якоби:"
+			for i, row := range jac {
+				for j, d := range row {
+					infoStr += fmt.Sprintf("\n  ∂f%d/∂%s = %s", i+1, vars[j], d)
+				}
 			}
+			fmt.Println("\n" + infoStr)
+
+			fmt.Printf("\nРешение системы: %s...\n", methodStr)
+			x, iterCount, iterData = newtonSystemMethod(F, J, x0, eps, opts)
+			for _, v := range vars {
+				headers = append(headers, v+"ₖ")
+			}
+			headers = append(headers, "‖F(xₖ)‖", "λ", "‖xₖ₊₁-xₖ‖")
 		} else {
+			methodStr = "Метод простой итерации"
+			q := jacobianNorm(phiJacobian(x0))
+			infoStr = fmt.Sprintf("‖φ'(x0)‖ = %.6f: условие сходимости выполнено", q)
+			if !(q < 1) {
+				infoStr = fmt.Sprintf("Внимание: ‖φ'(x0)‖ = %.6f ≥ 1, условие сходимости не выполнено", q)
+			}
+			fmt.Println(infoStr)
+
 			fmt.Println("\nРешение системы методом простой итерации...")
-			if sysChoice == 1 {
-				// Преобразования для системы 1 (выбраны по примеру из задания)
-				phi1 := func(x, y float64) float64 {
-					// Вычисляем y по второму уравнению
-					return math.Sqrt((1 - 0.9*x*x) / 2)
-				}
-				phi2 := func(x, y float64) float64 {
-					if math.Abs(x) < 1e-10 {
-						return 0
-					}
-					return (math.Atan(x*x) - 0.3) / x
-				}
-				x, y, iterCount, iterData := simpleIterationSystemMethod(
-					sys1F1, sys1F2,
-					phi1, phi2,
-					x0, y0, eps)
-				headers := []string{"xₖ", "yₖ", "xₖ₊₁", "yₖ₊₁", "|xₖ₊₁-xₖ|", "|yₖ₊₁-yₖ|"}
-				sysName := "tan(xy + 0.3) = x², 0.9x² + 2y² = 1"
-				resultStr = fmt.Sprintf("Система: %s\nМетод: Метод простой итерации\nНачальные приближения: (%.6f, %.6f)\nРешение: (%.6f, %.6f)\nНевязки: %.10f, %.10f\nЧисло итераций: %d",
-					sysName, x0, y0, x, y, sys1F1(x, y), sys1F2(x, y), iterCount)
-				tableStr = formatTable(headers, iterData)
-			} else {
-				phi1 := func(x, y float64) float64 { return math.Sin(x+y) / 1.2 }
-				phi2 := func(x, y float64) float64 { return math.Sqrt(1 - x*x) }
-				sinxyF1 := func(x, y float64) float64 { return math.Sin(x+y) - 1.2*x }
-				sinxyF2 := func(x, y float64) float64 { return math.Pow(x, 2) + math.Pow(y, 2) - 1 }
-				x, y, iterCount, iterData := simpleIterationSystemMethod(
-					sinxyF1, sinxyF2,
-					phi1, phi2,
-					x0, y0, eps)
-				headers := []string{"xₖ", "yₖ", "xₖ₊₁", "yₖ₊₁", "|xₖ₊₁-xₖ|", "|yₖ₊₁-yₖ|"}
-				sysName := "sin(x+y) - 1.2x = 0, x² + y² = 1"
-				resultStr = fmt.Sprintf("Система: %s\nМетод: Метод простой итерации\nНачальные приближения: (%.6f, %.6f)\nРешение: (%.6f, %.6f)\nНевязки: %.10f, %.10f\nЧисло итераций: %d",
-					sysName, x0, y0, x, y, sinxyF1(x, y), sinxyF2(x, y), iterCount)
-				tableStr = formatTable(headers, iterData)
+			x, iterCount, iterData = simpleIterationSystemMethod(phi, x0, eps)
+			for _, v := range vars {
+				headers = append(headers, v+"ₖ")
 			}
+			headers = append(headers, "max|xₖ₊₁-xₖ|")
 		}
+		resultStr := fmt.Sprintf("Система: %s\n%s\nМетод: %s\nНачальное приближение: %s\nРешение: %s\nНевязки: %s\nЧисло итераций: %d",
+			sysName, infoStr, methodStr, formatVector(x0, "%.6f"), formatVector(x, "%.6f"), formatVector(F(x), "%.10f"), iterCount)
+		tableStr := formatTable(headers, iterData)
+		diagStr := diagnoseVectors(iterates(iterData, n, x)).String()
 
 		fmt.Println("\nРезультаты:")
 		fmt.Println(resultStr)
 		fmt.Println("\nТаблица итераций:")
 		fmt.Println(tableStr)
+		fmt.Println(diagStr)
+
+		// Линии уровня и путь метода
+		if n == 2 && readYesNo(reader, "\nСохранить линии уровня и путь метода в PNG?", true) {
+			var path [][]float64
+			for _, row := range iterData {
+				path = append(path, row[:2])
+			}
+			path = append(path, x)
+			createSystemPlot(system, path, methodStr+": f₁ = 0, f₂ = 0 и путь метода", "nonlinear_system.png")
+		}
 
 		fmt.Print("\nСохранить результаты в файл? (y/n, по умолчанию y): ")
 		saveChoice, _ := reader.ReadString('\n')
 		saveChoice = strings.TrimSpace(saveChoice)
 		if saveChoice == "" || strings.ToLower(saveChoice) == "y" {
 			filename := "nonlinear_system_results.txt"
-			content := resultStr + "\n\nТаблица итераций:\n" + tableStr
+			content := resultStr + "\n\nТаблица итераций:\n" + tableStr + "\n" + diagStr
 			if err := writeToFile(filename, content); err != nil {
 				fmt.Println("Ошибка сохранения в файл:", err)
 			} else {
 				fmt.Println("Результаты сохранены в", filename)
 			}
 		}
+
+		if n == 2 {
+			fmt.Print("\nПостроить карту бассейнов притяжения? (y/n, по умолчанию n): ")
+			basinChoice, _ := reader.ReadString('\n')
+			if strings.ToLower(strings.TrimSpace(basinChoice)) == "y" {
+				var solve basinSolver
+				if sysMethodChoice == 4 {
+					solve = func(x0 []float64) ([]float64, int, error) {
+						x, k, _, err := solveSimpleIterationSystem(phi, x0, eps)
+						return x, k, err
+					}
+				} else {
+					_, J := exprJacobian(system)
+					opts := NewtonOptions{LineSearch: sysMethodChoice == 2, Broyden: sysMethodChoice == 3}
+					solve = func(x0 []float64) ([]float64, int, error) {
+						x, k, _, err := solveNewtonSystem(F, J, x0, eps, opts)
+						return x, k, err
+					}
+				}
+				xmin, xmax := readInterval(reader, "Диапазон "+vars[0]+"0 (два числа через пробел)", -2, 2)
+				ymin, ymax := readInterval(reader, "Диапазон "+vars[1]+"0 (два числа через пробел)", -2, 2)
+				size := readInt(reader, "Размер сетки (точек по каждой оси)", 300, 20, 1000)
+
+				fmt.Println("\nПостроение карты бассейнов притяжения...")
+				tol := math.Max(100*eps, 1e-6)
+				basin := computeBasin(withResidualCheck(F, tol, solve), xmin, xmax, ymin, ymax, size, size, tol)
+				fmt.Print(basin.Summary(vars))
+				createBasinPlot(basin, methodStr+": бассейны притяжения", vars, "nonlinear_system_basins.png")
+			}
+		}
+	} else {
+		solveComplex(reader)
+	}
+}
+
+// Поиск комплексных корней: все корни многочлена или один корень
+// аналитической функции методом Ньютона или секущих в комплексной области.
+func solveComplex(reader *bufio.Reader) {
+	fmt.Println("\nВыберите метод:")
+	fmt.Println("1. Все корни многочлена методом Аберта")
+	fmt.Println("2. Все корни многочлена методом Ньютона с понижением степени")
+	fmt.Println("3. Метод Ньютона в комплексной области")
+	fmt.Println("4. Метод секущих в комплексной области")
+	methodChoice := readInt(reader, "Введите ваш выбор (1-4)", 1, 1, 4)
+
+	var resultStr, tableStr, diagStr string
+	eps := 0.0
+	if methodChoice <= 2 {
+		fmt.Println("Многочлен задаётся коэффициентами от старшего к младшему (1 -1.89 -2 1.76)")
+		fmt.Println("или выражением от x (x^3 - 1.89x^2 - 2x + 1.76)")
+		p := readPoly(reader, "Многочлен")
+		eps = readFloat(reader, "Введите точность", 0.000001)
+
+		var zs []complex128
+		var iterCount int
+		var iterData [][]float64
+		var headers []string
+		var methodStr string
+		if methodChoice == 1 {
+			methodStr = "Метод Аберта"
+			zs, iterCount, iterData = aberthMethod(p, eps)
+			headers = []string{"k", "max|Δz|", "max|P(z)|"}
+			diagStr = diagnoseSteps(column(iterData, 1)).String()
+		} else {
+			methodStr = "Метод Ньютона с понижением степени"
+			zs, iterCount, iterData = deflationMethod(p, eps)
+			headers = []string{"№", "Re z", "Im z", "Итер. Ньютона", "Итер. уточн."}
+		}
+		roots := groupRoots(p, zs)
+		resultStr = fmt.Sprintf("Многочлен: P(x) = %s\nСтепень: %d\nМетод: %s\nЧисло итераций: %d\nРазличных корней: %d\n\n%s",
+			p, p.Degree(), methodStr, iterCount, len(roots), formatPolyRoots(roots))
+		tableStr = formatTable(headers, iterData)
+	} else {
+		printExprHelp()
+		expr := readEquation(reader, "Введите аналитическую функцию f(x)")
+		f := expr.CFunc1()
+		eps = readFloat(reader, "Введите точность", 0.0001)
+
+		var z complex128
+		var iterCount int
+		var iterData [][]float64
+		var methodStr, infoStr string
+		if methodChoice == 3 {
+			methodStr = "Метод Ньютона в комплексной области"
+			deriv := expr.Derivative()
+			infoStr = "f'(x) = " + deriv.String() + "\n"
+			z0 := readComplex(reader, "Введите начальное приближение z0 (Re Im)", complex(0.5, 0.5))
+			infoStr += fmt.Sprintf("Начальное приближение: %s", formatComplex(z0))
+			z, iterCount, iterData = complexNewtonMethod(f, deriv.CFunc1(), z0, eps)
+		} else {
+			methodStr = "Метод секущих в комплексной области"
+			z0 := readComplex(reader, "Введите z0 (Re Im)", complex(0.5, 0.5))
+			z1 := readComplex(reader, "Введите z1 (Re Im)", complex(0.6, 0.6))
+			infoStr = fmt.Sprintf("Начальные приближения: %s, %s", formatComplex(z0), formatComplex(z1))
+			z, iterCount, iterData = complexSecantMethod(f, z0, z1, eps)
+		}
+		resultStr = fmt.Sprintf("Функция: f(x) = %s\nМетод: %s\n%s\nКорень: %s\n|f(z)|: %.3e\nЧисло итераций: %d",
+			expr, methodStr, infoStr, formatComplex(z), cmplx.Abs(f(z)), iterCount)
+		tableStr = formatTable([]string{"Re zₖ", "Im zₖ", "|f(zₖ)|", "|zₖ₊₁-zₖ|"}, iterData)
+		diagStr = diagnoseVectors(iterates(iterData, 2, []float64{real(z), imag(z)})).String()
+	}
+
+	fmt.Println("\nРезультаты:")
+	fmt.Println(resultStr)
+	fmt.Println("\nТаблица итераций:")
+	fmt.Println(tableStr)
+	if diagStr != "" {
+		fmt.Println(diagStr)
+	}
+
+	fmt.Print("\nСохранить результаты в файл? (y/n, по умолчанию y): ")
+	saveChoice, _ := reader.ReadString('\n')
+	saveChoice = strings.TrimSpace(saveChoice)
+	if saveChoice == "" || strings.ToLower(saveChoice) == "y" {
+		filename := "nonlinear_complex_results.txt"
+		content := resultStr + "\n\nТаблица итераций:\n" + tableStr + "\n" + diagStr
+		if err := writeToFile(filename, content); err != nil {
+			fmt.Println("Ошибка сохранения в файл:", err)
+		} else {
+			fmt.Println("Результаты сохранены в", filename)
+		}
 	}
 }
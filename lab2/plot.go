@@ -0,0 +1,432 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Графики для лабораторной: функция с корнями, шаги методов и линии
+// уровня систем.
+
+const (
+	// plotSamples — число точек при построении кривой
+	plotSamples = 1000
+	// maxPlotSteps — сколько первых итераций показывать на графиках шагов
+	maxPlotSteps = 10
+)
+
+var (
+	curveColor = color.RGBA{R: 0, G: 0, B: 255, A: 255}     // Синий
+	stepColor  = color.RGBA{R: 255, G: 0, B: 0, A: 255}     // Красный
+	helpColor  = color.RGBA{R: 0, G: 128, B: 0, A: 255}     // Зеленый
+	rootColor  = color.RGBA{R: 0, G: 0, B: 0, A: 255}       // Черный
+	axisColor  = color.RGBA{R: 128, G: 128, B: 128, A: 255} // Серый
+)
+
+// plotRange расширяет отрезок [a, b], чтобы в него попали точки xs, но не
+// более чем в три раза: далеко ушедшие приближения не показываются.
+func plotRange(a, b float64, xs ...float64) (float64, float64) {
+	lo, hi := math.Min(a, b), math.Max(a, b)
+	width := hi - lo
+	for _, x := range xs {
+		if math.IsNaN(x) || math.IsInf(x, 0) || x < lo-width || x > hi+width {
+			continue
+		}
+		lo, hi = math.Min(lo, x), math.Max(hi, x)
+	}
+	pad := (hi - lo) * 0.05
+	return lo - pad, hi + pad
+}
+
+// curve строит кривую y = f(x) на [a, b]. Кривая разбивается на части в
+// точках, где f не определена или уходит далеко за типичный диапазон
+// значений (полюсы); возвращает части и диапазон по y для осей.
+func curve(f func(float64) float64, a, b float64) ([]plotter.XYs, float64, float64) {
+	xs := make([]float64, plotSamples+1)
+	ys := make([]float64, plotSamples+1)
+	var finite []float64
+	for i := range xs {
+		xs[i] = a + (b-a)*float64(i)/plotSamples
+		ys[i] = f(xs[i])
+		if !math.IsNaN(ys[i]) && !math.IsInf(ys[i], 0) {
+			finite = append(finite, ys[i])
+		}
+	}
+	if len(finite) == 0 {
+		return nil, -1, 1
+	}
+
+	// Диапазон по квантилям 2% и 98%, чтобы полюсы не сжимали график
+	sort.Float64s(finite)
+	lo, hi := finite[len(finite)*2/100], finite[len(finite)*98/100]
+	lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+	if hi-lo == 0 {
+		lo, hi = lo-1, hi+1
+	}
+	pad := (hi - lo) * 0.1
+	lo, hi = lo-pad, hi+pad
+
+	var parts []plotter.XYs
+	var part plotter.XYs
+	for i := range xs {
+		y := ys[i]
+		if math.IsNaN(y) || math.IsInf(y, 0) || y < lo-10*(hi-lo) || y > hi+10*(hi-lo) {
+			if len(part) > 1 {
+				parts = append(parts, part)
+			}
+			part = nil
+			continue
+		}
+		part = append(part, plotter.XY{X: xs[i], Y: y})
+	}
+	if len(part) > 1 {
+		parts = append(parts, part)
+	}
+	return parts, lo, hi
+}
+
+// functionPlot создаёт график y = f(x) на [a, b] с осью y = 0.
+func functionPlot(f func(float64) float64, a, b float64, title string) *plot.Plot {
+	p := plot.New()
+
+	p.Title.Text = title
+	p.X.Label.Text = "x"
+	p.Y.Label.Text = "y"
+
+	parts, lo, hi := curve(f, a, b)
+	addLine(p, plotter.XYs{{X: a, Y: 0}, {X: b, Y: 0}}, axisColor, 1)
+	for i, part := range parts {
+		line := addLine(p, part, curveColor, 1.5)
+		if i == 0 {
+			p.Legend.Add("f(x)", line)
+		}
+	}
+
+	p.X.Min, p.X.Max = a, b
+	p.Y.Min, p.Y.Max = lo, hi
+	p.Add(plotter.NewGrid())
+	return p
+}
+
+// addLine добавляет ломаную заданного цвета и толщины.
+func addLine(p *plot.Plot, pts plotter.XYs, c color.Color, width float64, dashes ...vg.Length) *plotter.Line {
+	line, _ := plotter.NewLine(pts)
+	line.LineStyle.Width = vg.Points(width)
+	line.LineStyle.Color = c
+	line.LineStyle.Dashes = dashes
+	p.Add(line)
+	return line
+}
+
+// addPoints добавляет точки заданного цвета.
+func addPoints(p *plot.Plot, pts plotter.XYs, c color.Color, radius float64) *plotter.Scatter {
+	scatter, _ := plotter.NewScatter(pts)
+	scatter.GlyphStyle.Shape = draw.CircleGlyph{}
+	scatter.GlyphStyle.Color = c
+	scatter.GlyphStyle.Radius = vg.Points(radius)
+	p.Add(scatter)
+	return scatter
+}
+
+// savePlot сохраняет график в PNG и сообщает о результате.
+func savePlot(p *plot.Plot, width, height vg.Length, filename string) {
+	p.Legend.Top = true
+	p.Legend.Left = true
+	if err := p.Save(width, height, filename); err != nil {
+		fmt.Printf("Ошибка сохранения графика: %v\n", err)
+	} else {
+		fmt.Printf("График сохранен: %s\n", filename)
+	}
+}
+
+// steps возвращает не более maxPlotSteps первых строк таблицы итераций.
+func steps(table [][]float64) [][]float64 {
+	if len(table) > maxPlotSteps {
+		return table[:maxPlotSteps]
+	}
+	return table
+}
+
+// createFunctionPlot строит график f на [a, b] с отмеченными корнями.
+func createFunctionPlot(f func(float64) float64, a, b float64, roots []float64, title, filename string) {
+	p := functionPlot(f, a, b, title)
+
+	var pts plotter.XYs
+	for _, r := range roots {
+		pts = append(pts, plotter.XY{X: r, Y: 0})
+	}
+	if len(pts) > 0 {
+		p.Legend.Add("Корни", addPoints(p, pts, stepColor, 4))
+	}
+	savePlot(p, 10*vg.Inch, 6*vg.Inch, filename)
+}
+
+// createBisectionPlot показывает сужение отрезка в методе половинного
+// деления: отрезок k-й итерации рисуется под графиком на k-м уровне.
+// Строка таблицы: a, b, x, ...
+func createBisectionPlot(f func(float64) float64, table [][]float64, a, b float64, filename string) {
+	p := functionPlot(f, a, b, "Метод половинного деления: сужение отрезка")
+	lo, hi := p.Y.Min, p.Y.Max
+	rows := steps(table)
+	level := (hi - lo) / 40
+
+	for k, row := range rows {
+		y := lo - float64(k+1)*level
+		line := addLine(p, plotter.XYs{{X: row[0], Y: y}, {X: row[1], Y: y}}, stepColor, 2)
+		addPoints(p, plotter.XYs{{X: row[2], Y: y}}, rootColor, 2)
+		if k == 0 {
+			p.Legend.Add("Отрезки и их середины", line)
+		}
+	}
+	p.Y.Min = lo - float64(len(rows)+1)*level
+	savePlot(p, 10*vg.Inch, 6*vg.Inch, filename)
+}
+
+// createChordPlot рисует хорды через (aₖ, f(aₖ)) и (bₖ, f(bₖ)) и их точки
+// пересечения с осью. Строка таблицы: a, b, x, f(a), f(b), ...
+func createChordPlot(f func(float64) float64, table [][]float64, a, b float64, filename string) {
+	p := functionPlot(f, a, b, "Метод хорд: хорды и их пересечения с осью x")
+	for k, row := range steps(table) {
+		line := addLine(p, plotter.XYs{{X: row[0], Y: row[3]}, {X: row[1], Y: row[4]}}, stepColor, 1)
+		points := addPoints(p, plotter.XYs{{X: row[2], Y: 0}}, rootColor, 2.5)
+		if k == 0 {
+			p.Legend.Add("Хорды", line)
+			p.Legend.Add("Приближения", points)
+		}
+	}
+	savePlot(p, 10*vg.Inch, 6*vg.Inch, filename)
+}
+
+// createNewtonPlot рисует касательные метода Ньютона: от (xₖ, f(xₖ)) до
+// пересечения с осью в xₖ₊₁ и вертикаль к следующей точке графика.
+// Строка таблицы: xₖ, f(xₖ), f'(xₖ), xₖ₊₁, ...
+func createNewtonPlot(f func(float64) float64, table [][]float64, a, b float64, filename string) {
+	rows := steps(table)
+	var xs []float64
+	for _, row := range rows {
+		xs = append(xs, row[0], row[3])
+	}
+	a, b = plotRange(a, b, xs...)
+	p := functionPlot(f, a, b, "Метод Ньютона: касательные")
+	for k, row := range rows {
+		tangent := addLine(p, plotter.XYs{{X: row[0], Y: row[1]}, {X: row[3], Y: 0}}, stepColor, 1)
+		vertical := addLine(p, plotter.XYs{{X: row[3], Y: 0}, {X: row[3], Y: f(row[3])}}, helpColor, 1, vg.Points(3), vg.Points(2))
+		points := addPoints(p, plotter.XYs{{X: row[0], Y: row[1]}}, rootColor, 2.5)
+		if k == 0 {
+			p.Legend.Add("Касательные", tangent)
+			p.Legend.Add("Переход к следующей точке", vertical)
+			p.Legend.Add("Точки (x, f(x))", points)
+		}
+	}
+	savePlot(p, 10*vg.Inch, 6*vg.Inch, filename)
+}
+
+// createSecantPlot рисует секущие через (xₖ₋₁, f(xₖ₋₁)) и (xₖ, f(xₖ)) до
+// пересечения с осью в xₖ₊₁. Строка таблицы: xₖ₋₁, xₖ, xₖ₊₁, ...
+func createSecantPlot(f func(float64) float64, table [][]float64, a, b float64, filename string) {
+	rows := steps(table)
+	var xs []float64
+	for _, row := range rows {
+		xs = append(xs, row[0], row[1], row[2])
+	}
+	a, b = plotRange(a, b, xs...)
+	p := functionPlot(f, a, b, "Метод секущих: секущие")
+	for k, row := range rows {
+		line := addLine(p, plotter.XYs{{X: row[0], Y: f(row[0])}, {X: row[1], Y: f(row[1])}, {X: row[2], Y: 0}}, stepColor, 1)
+		points := addPoints(p, plotter.XYs{{X: row[2], Y: 0}}, rootColor, 2.5)
+		if k == 0 {
+			p.Legend.Add("Секущие", line)
+			p.Legend.Add("Приближения", points)
+		}
+	}
+	savePlot(p, 10*vg.Inch, 6*vg.Inch, filename)
+}
+
+// createCobwebPlot строит «паутину» метода простой итерации: графики
+// y = φ(x) и y = x и ломаную (xₖ, xₖ) → (xₖ, xₖ₊₁) → (xₖ₊₁, xₖ₊₁).
+// Строка таблицы: xₖ, xₖ₊₁, ...
+func createCobwebPlot(phi func(float64) float64, table [][]float64, a, b float64, filename string) {
+	rows := steps(table)
+	var xs []float64
+	for _, row := range rows {
+		xs = append(xs, row[0], row[1])
+	}
+	a, b = plotRange(a, b, xs...)
+
+	p := plot.New()
+	p.Title.Text = "Метод простой итерации: диаграмма «паутина»"
+	p.X.Label.Text = "x"
+	p.Y.Label.Text = "y"
+
+	parts, _, _ := curve(phi, a, b)
+	for i, part := range parts {
+		line := addLine(p, part, curveColor, 1.5)
+		if i == 0 {
+			p.Legend.Add("y = φ(x)", line)
+		}
+	}
+	p.Legend.Add("y = x", addLine(p, plotter.XYs{{X: a, Y: a}, {X: b, Y: b}}, helpColor, 1, vg.Points(4), vg.Points(2)))
+
+	var web plotter.XYs
+	for _, row := range rows {
+		web = append(web, plotter.XY{X: row[0], Y: row[0]}, plotter.XY{X: row[0], Y: row[1]})
+	}
+	if len(rows) > 0 {
+		last := rows[len(rows)-1][1]
+		web = append(web, plotter.XY{X: last, Y: last})
+		p.Legend.Add("Итерации", addLine(p, web, stepColor, 1))
+		addPoints(p, plotter.XYs{{X: rows[0][0], Y: rows[0][0]}}, rootColor, 2.5)
+	}
+
+	p.X.Min, p.X.Max = a, b
+	p.Y.Min, p.Y.Max = a, b
+	p.Add(plotter.NewGrid())
+	savePlot(p, 8*vg.Inch, 8*vg.Inch, filename)
+}
+
+// createIteratesPlot отмечает на графике f последовательность приближений
+// (для гибридных методов).
+func createIteratesPlot(f func(float64) float64, xs []float64, a, b float64, title, filename string) {
+	shown := xs
+	if len(shown) > maxPlotSteps {
+		shown = shown[:maxPlotSteps]
+	}
+	a, b = plotRange(a, b, shown...)
+	p := functionPlot(f, a, b, title)
+	var pts plotter.XYs
+	for _, x := range shown {
+		pts = append(pts, plotter.XY{X: x, Y: f(x)})
+	}
+	if len(pts) > 0 {
+		p.Legend.Add("Путь приближений", addLine(p, pts, stepColor, 1, vg.Points(3), vg.Points(2)))
+		p.Legend.Add("Точки (x, f(x))", addPoints(p, pts, rootColor, 2.5))
+	}
+	savePlot(p, 10*vg.Inch, 6*vg.Inch, filename)
+}
+
+// zeroSet — нулевая линия уровня f(x, y) = 0, построенная методом
+// марширующих квадратов. На ребре клетки со сменой знака точка нуля
+// уточняется делением пополам и принимается, только если |f| в ней меньше,
+// чем в концах ребра: так отбрасываются смены знака через полюс (как у tan).
+type zeroSet struct {
+	segments [][2]plotter.XY
+	draw.LineStyle
+}
+
+func newZeroSet(f func(x, y float64) float64, xmin, xmax, ymin, ymax float64, n int) *zeroSet {
+	xs := make([]float64, n+1)
+	ys := make([]float64, n+1)
+	z := make([][]float64, n+1)
+	for i := 0; i <= n; i++ {
+		xs[i] = xmin + (xmax-xmin)*float64(i)/float64(n)
+		ys[i] = ymin + (ymax-ymin)*float64(i)/float64(n)
+	}
+	for i := range xs {
+		z[i] = make([]float64, n+1)
+		for j := range ys {
+			z[i][j] = f(xs[i], ys[j])
+		}
+	}
+
+	// crossing ищет нуль на ребре между узлами (i1, j1) и (i2, j2)
+	crossing := func(i1, j1, i2, j2 int) (plotter.XY, bool) {
+		z1, z2 := z[i1][j1], z[i2][j2]
+		if math.IsNaN(z1) || math.IsNaN(z2) || math.IsInf(z1, 0) || math.IsInf(z2, 0) || (z1 > 0) == (z2 > 0) {
+			return plotter.XY{}, false
+		}
+		at := func(t float64) plotter.XY {
+			return plotter.XY{X: xs[i1] + t*(xs[i2]-xs[i1]), Y: ys[j1] + t*(ys[j2]-ys[j1])}
+		}
+		lo, hi := 0.0, 1.0
+		for k := 0; k < 30; k++ {
+			t := (lo + hi) / 2
+			pt := at(t)
+			if (f(pt.X, pt.Y) > 0) == (z1 > 0) {
+				lo = t
+			} else {
+				hi = t
+			}
+		}
+		pt := at((lo + hi) / 2)
+		return pt, math.Abs(f(pt.X, pt.Y)) <= math.Min(math.Abs(z1), math.Abs(z2))
+	}
+
+	zs := &zeroSet{LineStyle: plotter.DefaultLineStyle}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			var pts []plotter.XY
+			for _, e := range [][4]int{{i, j, i + 1, j}, {i + 1, j, i + 1, j + 1}, {i + 1, j + 1, i, j + 1}, {i, j + 1, i, j}} {
+				if pt, ok := crossing(e[0], e[1], e[2], e[3]); ok {
+					pts = append(pts, pt)
+				}
+			}
+			for k := 0; k+1 < len(pts); k += 2 {
+				zs.segments = append(zs.segments, [2]plotter.XY{pts[k], pts[k+1]})
+			}
+		}
+	}
+	return zs
+}
+
+// Plot реализует plot.Plotter.
+func (zs *zeroSet) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+	for _, s := range zs.segments {
+		c.StrokeLine2(zs.LineStyle, trX(s[0].X), trY(s[0].Y), trX(s[1].X), trY(s[1].Y))
+	}
+}
+
+// Thumbnail реализует plot.Thumbnailer для легенды.
+func (zs *zeroSet) Thumbnail(c *draw.Canvas) {
+	y := c.Center().Y
+	c.StrokeLine2(zs.LineStyle, c.Min.X, y, c.Max.X, y)
+}
+
+// createSystemPlot строит нулевые линии уровня обоих уравнений системы и
+// путь метода: точки пересечения линий — решения системы.
+func createSystemPlot(system []*Expr, path [][]float64, title, filename string) {
+	vars := system[0].Vars
+	var xs, ys []float64
+	for _, x := range path {
+		xs = append(xs, x[0])
+		ys = append(ys, x[1])
+	}
+	xmin, xmax := plotRange(-2, 2, xs...)
+	ymin, ymax := plotRange(-2, 2, ys...)
+
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = vars[0]
+	p.Y.Label.Text = vars[1]
+
+	contourColors := []color.RGBA{curveColor, helpColor}
+	for i, eq := range system {
+		zs := newZeroSet(func(x, y float64) float64 { return eq.Eval(x, y) }, xmin, xmax, ymin, ymax, 300)
+		zs.Width = vg.Points(1.5)
+		zs.Color = contourColors[i%len(contourColors)]
+		p.Add(zs)
+		p.Legend.Add(eq.String()+" = 0", zs)
+	}
+
+	var pts plotter.XYs
+	for _, x := range path {
+		pts = append(pts, plotter.XY{X: x[0], Y: x[1]})
+	}
+	if len(pts) > 0 {
+		p.Legend.Add("Путь метода", addLine(p, pts, stepColor, 1))
+		addPoints(p, pts, stepColor, 2)
+		p.Legend.Add("Решение", addPoints(p, pts[len(pts)-1:], rootColor, 4))
+	}
+
+	p.X.Min, p.X.Max = xmin, xmax
+	p.Y.Min, p.Y.Max = ymin, ymax
+	p.Add(plotter.NewGrid())
+	savePlot(p, 8*vg.Inch, 8*vg.Inch, filename)
+}
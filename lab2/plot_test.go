@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPlotRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   float64
+		xs     []float64
+		lo, hi float64
+	}{
+		{"no points", 0, 1, nil, -0.05, 1.05},
+		{"reversed", 1, 0, nil, -0.05, 1.05},
+		{"inside", 0, 1, []float64{0.5}, -0.05, 1.05},
+		{"close outside", 0, 1, []float64{-1, 1.5}, -1.125, 1.625},
+		{"far away", 0, 1, []float64{-1.5, 100}, -0.05, 1.05},
+		{"not finite", 0, 1, []float64{math.NaN(), math.Inf(1)}, -0.05, 1.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lo, hi := plotRange(tt.a, tt.b, tt.xs...)
+			if math.Abs(lo-tt.lo) > 1e-12 || math.Abs(hi-tt.hi) > 1e-12 {
+				t.Errorf("plotRange = [%g, %g], want [%g, %g]", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+}
+
+func TestCurve(t *testing.T) {
+	tests := []struct {
+		name  string
+		f     func(float64) float64
+		a, b  float64
+		parts int
+	}{
+		{"line", func(x float64) float64 { return 2*x + 1 }, -1, 1, 1},
+		{"pole at a node", func(x float64) float64 { return 1 / x }, -1, 1, 2},
+		{"pole between nodes", func(x float64) float64 { return 1 / (x - 0.3337) }, -1, 1, 2},
+		{"two poles", func(x float64) float64 { return math.Tan(x) }, -3, 3, 3},
+		{"undefined half", math.Sqrt, -1, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, lo, hi := curve(tt.f, tt.a, tt.b)
+			if len(parts) != tt.parts {
+				t.Fatalf("%d parts, want %d", len(parts), tt.parts)
+			}
+			if !(lo <= 0 && 0 <= hi) {
+				t.Errorf("y range [%g, %g] does not contain 0", lo, hi)
+			}
+			for i, part := range parts {
+				for j, p := range part {
+					if p.X < tt.a || p.X > tt.b || math.IsNaN(p.Y) || math.IsInf(p.Y, 0) {
+						t.Fatalf("part %d, point %d: (%g, %g)", i, j, p.X, p.Y)
+					}
+					// Точки по разные стороны полюса не соединяются
+					if j > 0 && (p.Y > 0) != (part[j-1].Y > 0) && math.Abs(p.Y-part[j-1].Y) > 10*(hi-lo) {
+						t.Errorf("part %d joins (%g, %g) and (%g, %g) across a pole",
+							i, part[j-1].X, part[j-1].Y, p.X, p.Y)
+					}
+				}
+			}
+		})
+	}
+
+	// Постоянная функция получает ненулевой диапазон по y
+	if _, lo, hi := curve(func(float64) float64 { return 2 }, 0, 1); math.Abs(lo+0.2) > 1e-12 || math.Abs(hi-2.2) > 1e-12 {
+		t.Errorf("constant: y range [%g, %g], want [-0.2, 2.2]", lo, hi)
+	}
+	if parts, lo, hi := curve(func(float64) float64 { return math.NaN() }, 0, 1); parts != nil || lo != -1 || hi != 1 {
+		t.Errorf("undefined: %d parts, y range [%g, %g], want none and [-1, 1]", len(parts), lo, hi)
+	}
+}
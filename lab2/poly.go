@@ -0,0 +1,447 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Poly — многочлен с вещественными коэффициентами; p[i] — коэффициент
+// при xⁱ.
+type Poly []float64
+
+// PolyRoot — корень многочлена с кратностью и невязкой |P(z)|.
+type PolyRoot struct {
+	Z            complex128
+	Multiplicity int
+	Residual     float64
+}
+
+// ParsePoly разбирает многочлен: список коэффициентов от старшего к
+// младшему ("1 -1.89 -2 1.76") или выражение от x ("x^3 - 1.89x^2 - 2x + 1.76").
+func ParsePoly(src string) (Poly, error) {
+	fields := strings.Fields(strings.Replace(src, ",", " ", -1))
+	coeffs := make([]float64, len(fields))
+	numeric := len(fields) > 0
+	for i, s := range fields {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			numeric = false
+			break
+		}
+		coeffs[len(fields)-1-i] = v
+	}
+	if numeric {
+		return Poly(coeffs).trim()
+	}
+
+	expr, err := ParseExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p, err := polyOf(expr.root)
+	if err != nil {
+		return nil, err
+	}
+	return p.trim()
+}
+
+// trim убирает нулевые старшие коэффициенты.
+func (p Poly) trim() (Poly, error) {
+	for len(p) > 0 && p[len(p)-1] == 0 {
+		p = p[:len(p)-1]
+	}
+	if len(p) < 2 {
+		return nil, errors.New("степень многочлена должна быть не меньше 1")
+	}
+	return p, nil
+}
+
+// polyOf раскрывает дерево выражения в многочлен. Допускаются +, -, *,
+// деление на число и возведение в неотрицательную целую степень.
+func polyOf(n node) (Poly, error) {
+	switch n := n.(type) {
+	case numNode:
+		return Poly{n.value}, nil
+	case varNode:
+		return Poly{0, 1}, nil
+	case negNode:
+		p, err := polyOf(n.arg)
+		if err != nil {
+			return nil, err
+		}
+		return p.scale(-1), nil
+	case binaryNode:
+		l, err := polyOf(n.left)
+		if err != nil {
+			return nil, err
+		}
+		r, err := polyOf(n.right)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case '+':
+			return l.add(r), nil
+		case '-':
+			return l.add(r.scale(-1)), nil
+		case '*':
+			return l.mul(r), nil
+		case '/':
+			if len(r) != 1 || r[0] == 0 {
+				return nil, errors.New("делить можно только на ненулевое число")
+			}
+			return l.scale(1 / r[0]), nil
+		default:
+			if len(r) != 1 || r[0] < 0 || r[0] != math.Trunc(r[0]) || r[0] > 100 {
+				return nil, errors.New("показатель степени должен быть целым числом от 0 до 100")
+			}
+			result := Poly{1}
+			for i := 0; i < int(r[0]); i++ {
+				result = result.mul(l)
+			}
+			return result, nil
+		}
+	case callNode:
+		return nil, fmt.Errorf("функция %s — выражение не является многочленом", n.name)
+	}
+	panic("unknown node")
+}
+
+func (p Poly) scale(c float64) Poly {
+	q := make(Poly, len(p))
+	for i, v := range p {
+		q[i] = c * v
+	}
+	return q
+}
+
+func (p Poly) add(r Poly) Poly {
+	q := make(Poly, int(math.Max(float64(len(p)), float64(len(r)))))
+	for i, v := range p {
+		q[i] += v
+	}
+	for i, v := range r {
+		q[i] += v
+	}
+	return q
+}
+
+func (p Poly) mul(r Poly) Poly {
+	q := make(Poly, len(p)+len(r)-1)
+	for i, a := range p {
+		for j, b := range r {
+			q[i+j] += a * b
+		}
+	}
+	return q
+}
+
+// derivative возвращает производную многочлена.
+func (p Poly) derivative() Poly {
+	if len(p) < 2 {
+		return Poly{0}
+	}
+	q := make(Poly, len(p)-1)
+	for i := 1; i < len(p); i++ {
+		q[i-1] = float64(i) * p[i]
+	}
+	return q
+}
+
+// Degree возвращает степень многочлена.
+func (p Poly) Degree() int { return len(p) - 1 }
+
+// String записывает многочлен от старшей степени к младшей.
+func (p Poly) String() string {
+	var sb strings.Builder
+	for i := len(p) - 1; i >= 0; i-- {
+		c := p[i]
+		if c == 0 {
+			continue
+		}
+		switch {
+		case sb.Len() == 0 && c < 0:
+			sb.WriteString("-")
+		case sb.Len() > 0 && c < 0:
+			sb.WriteString(" - ")
+		case sb.Len() > 0:
+			sb.WriteString(" + ")
+		}
+		c = math.Abs(c)
+		if c != 1 || i == 0 {
+			sb.WriteString(strconv.FormatFloat(c, 'g', -1, 64))
+		}
+		switch {
+		case i == 1:
+			sb.WriteString("x")
+		case i > 1:
+			sb.WriteString("x^" + strconv.Itoa(i))
+		}
+	}
+	return sb.String()
+}
+
+// horner вычисляет P(z) и P'(z) схемой Горнера.
+func horner(p []complex128, z complex128) (complex128, complex128) {
+	n := len(p) - 1
+	value, deriv := p[n], complex(0, 0)
+	for i := n - 1; i >= 0; i-- {
+		deriv = deriv*z + value
+		value = value*z + p[i]
+	}
+	return value, deriv
+}
+
+func (p Poly) complexCoeffs() []complex128 {
+	c := make([]complex128, len(p))
+	for i, v := range p {
+		c[i] = complex(v, 0)
+	}
+	return c
+}
+
+// Eval вычисляет P(z).
+func (p Poly) Eval(z complex128) complex128 {
+	v, _ := horner(p.complexCoeffs(), z)
+	return v
+}
+
+// roundoffBound оценивает погрешность вычисления P(z) схемой Горнера:
+// пока |P(z)| не больше неё, значение неотличимо от нуля.
+func (p Poly) roundoffBound(z complex128) float64 {
+	r := cmplx.Abs(z)
+	sum := 0.0
+	for i := len(p) - 1; i >= 0; i-- {
+		sum = sum*r + math.Abs(p[i])
+	}
+	return 4 * float64(len(p)) * machineEpsilon * sum
+}
+
+// cauchyBound — радиус круга, содержащего все корни: 1 + max|aᵢ/aₙ|.
+func (p Poly) cauchyBound() float64 {
+	n := len(p) - 1
+	bound := 0.0
+	for i := 0; i < n; i++ {
+		bound = math.Max(bound, math.Abs(p[i]/p[n]))
+	}
+	return 1 + bound
+}
+
+// Метод Аберта (Эрлиха–Аберта): все корни уточняются одновременно,
+// zₖ ← zₖ - w, w = r / (1 - r·Σⱼ≠ₖ 1/(zₖ - zⱼ)), r = P(zₖ)/P'(zₖ).
+// Начальные приближения — на окружности вокруг центра масс корней.
+// Итерации прекращаются, когда шаг меньше eps или все |P(zₖ)| на уровне
+// ошибок округления (у кратных корней шаг дальше не уменьшается).
+// Строка таблицы: номер итерации, max|Δz|, max|P(z)|.
+func aberthMethod(p Poly, eps float64) ([]complex128, int, [][]float64) {
+	var iterations [][]float64
+	c := p.complexCoeffs()
+	n := p.Degree()
+
+	center := -p[n-1] / (float64(n) * p[n])
+	radius := p.cauchyBound()
+	z := make([]complex128, n)
+	for k := range z {
+		// Сдвиг угла нарушает симметрию относительно вещественной оси
+		z[k] = complex(center, 0) + cmplx.Rect(radius, 2*math.Pi*float64(k)/float64(n)+0.4)
+	}
+
+	iteration := 0
+	for {
+		iteration++
+		maxStep, maxResidual := 0.0, 0.0
+		converged := true
+		for k := range z {
+			value, deriv := horner(c, z[k])
+			maxResidual = math.Max(maxResidual, cmplx.Abs(value))
+			if cmplx.Abs(value) <= p.roundoffBound(z[k]) {
+				continue
+			}
+			converged = false
+			ratio := value / deriv
+			var sum complex128
+			for j := range z {
+				if j != k {
+					sum += 1 / (z[k] - z[j])
+				}
+			}
+			w := ratio / (1 - ratio*sum)
+			z[k] -= w
+			maxStep = math.Max(maxStep, cmplx.Abs(w)/math.Max(1, cmplx.Abs(z[k])))
+		}
+		iterations = append(iterations, []float64{float64(iteration), maxStep, maxResidual})
+
+		if maxStep < eps || converged {
+			break
+		}
+		if iteration >= maxComplexIter {
+			fmt.Println("Предупреждение: достигнуто максимальное число итераций")
+			break
+		}
+	}
+	return z, iteration, iterations
+}
+
+// Метод Ньютона с понижением степени: корень ищется методом Ньютона для
+// текущего многочлена, уточняется (полируется) по исходному многочлену и
+// выделяется делением на (x - z) по схеме Горнера. Строка таблицы: номер
+// корня, Re z, Im z, итерации Ньютона, итерации уточнения.
+func deflationMethod(p Poly, eps float64) ([]complex128, int, [][]float64) {
+	var iterations [][]float64
+	original := p.complexCoeffs()
+	q := p.complexCoeffs()
+	var roots []complex128
+	total := 0
+
+	for len(q) > 2 {
+		f := func(z complex128) complex128 { v, _ := horner(q, z); return v }
+		df := func(z complex128) complex128 { _, d := horner(q, z); return d }
+		// Комплексное начальное приближение позволяет найти комплексные
+		// корни многочлена с вещественными коэффициентами
+		z, k, _ := quietNewton(f, df, complex(0.4, 0.9), eps)
+		total += k
+
+		z, pk := polishRoot(original, z, eps)
+		total += pk
+
+		roots = append(roots, z)
+		iterations = append(iterations, []float64{float64(len(roots)), real(z), imag(z), float64(k), float64(pk)})
+
+		// Деление на (x - z): коэффициенты частного по схеме Горнера
+		n := len(q) - 1
+		quotient := make([]complex128, n)
+		carry := q[n]
+		for i := n - 1; i >= 0; i-- {
+			quotient[i] = carry
+			carry = carry*z + q[i]
+		}
+		q = quotient
+	}
+	z, pk := polishRoot(original, -q[0]/q[1], eps)
+	total += pk
+	roots = append(roots, z)
+	iterations = append(iterations, []float64{float64(len(roots)), real(z), imag(z), 0, float64(pk)})
+	return roots, total, iterations
+}
+
+// polishRoot уточняет приближение z методом Ньютона по исходному
+// многочлену; уточнённое значение принимается, только если невязка не выросла.
+func polishRoot(c []complex128, z complex128, eps float64) (complex128, int) {
+	f := func(z complex128) complex128 { v, _ := horner(c, z); return v }
+	df := func(z complex128) complex128 { _, d := horner(c, z); return d }
+	polished, k, _ := quietNewton(f, df, z, eps)
+	if !cmplx.IsNaN(polished) && cmplx.Abs(f(polished)) <= cmplx.Abs(f(z)) {
+		return polished, k
+	}
+	return z, k
+}
+
+// quietNewton — комплексный метод Ньютона без вывода предупреждений, для
+// вспомогательных вычислений.
+func quietNewton(f, df func(complex128) complex128, z complex128, eps float64) (complex128, int, bool) {
+	for k := 1; k <= maxComplexIter; k++ {
+		d := df(z)
+		if d == 0 {
+			return z, k, f(z) == 0
+		}
+		step := f(z) / d
+		z -= step
+		if cmplx.Abs(step) < eps*math.Max(1, cmplx.Abs(z)) {
+			return z, k, true
+		}
+	}
+	return z, maxComplexIter, false
+}
+
+// groupRoots объединяет близкие корни в один кратный. Кратный корень
+// кратности m находится с погрешностью порядка ε^(1/m), поэтому допуск
+// относительно широкий. Среднее группы точнее каждого приближения в
+// отдельности; оно дополнительно уточняется по P⁽ᵐ⁻¹⁾, для которой корень
+// кратности m простой. Группа считается кратным корнем, только если в
+// уточнённой точке P, P', ..., P⁽ᵐ⁻¹⁾ обращаются в ноль с точностью до
+// ошибок округления; иначе это близкие, но различные корни, и они
+// остаются отдельными.
+func groupRoots(p Poly, zs []complex128) []PolyRoot {
+	const tol = 1e-3
+	used := make([]bool, len(zs))
+	var roots []PolyRoot
+	add := func(z complex128, multiplicity int) {
+		// Части на уровне ошибок округления: вещественный или чисто мнимый корень
+		tiny := 1e-10 * math.Max(1, cmplx.Abs(z))
+		if math.Abs(imag(z)) < tiny {
+			z = complex(real(z), 0)
+		}
+		if math.Abs(real(z)) < tiny {
+			z = complex(0, imag(z))
+		}
+		roots = append(roots, PolyRoot{Z: z, Multiplicity: multiplicity, Residual: cmplx.Abs(p.Eval(z))})
+	}
+	for i, z := range zs {
+		if used[i] {
+			continue
+		}
+		group := []complex128{z}
+		used[i] = true
+		sum := z
+		for j := i + 1; j < len(zs); j++ {
+			if !used[j] && cmplx.Abs(zs[j]-z) < tol*math.Max(1, cmplx.Abs(z)) {
+				used[j] = true
+				group = append(group, zs[j])
+				sum += zs[j]
+			}
+		}
+		count := len(group)
+		mean := sum / complex(float64(count), 0)
+		if count > 1 {
+			d := p
+			for k := 1; k < count; k++ {
+				d = d.derivative()
+			}
+			mean, _ = polishRoot(d.complexCoeffs(), mean, 1e-15)
+			if !p.vanishes(mean, count) {
+				for _, g := range group {
+					add(g, 1)
+				}
+				continue
+			}
+		}
+		add(mean, count)
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if math.Abs(real(roots[i].Z)-real(roots[j].Z)) > 1e-9 {
+			return real(roots[i].Z) < real(roots[j].Z)
+		}
+		return imag(roots[i].Z) < imag(roots[j].Z)
+	})
+	return roots
+}
+
+// vanishes проверяет, что P и её производные до порядка m-1 в точке z не
+// больше своих ошибок округления (с запасом): так ведёт себя многочлен
+// в окрестности корня кратности m.
+func (p Poly) vanishes(z complex128, m int) bool {
+	d := p
+	for k := 0; k < m; k++ {
+		if cmplx.Abs(d.Eval(z)) > 100*d.roundoffBound(z) {
+			return false
+		}
+		d = d.derivative()
+	}
+	return true
+}
+
+// Функция для форматирования таблицы корней многочлена
+func formatPolyRoots(roots []PolyRoot) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-5s%-20s%-20s%-18s%-10s\n", "№", "Re z", "Im z", "|P(z)|", "Кратность"))
+	sb.WriteString(strings.Repeat("-", 73) + "\n")
+	for i, r := range roots {
+		sb.WriteString(fmt.Sprintf("%-5d%-20.12f%-20.12f%-18.3e%-10d\n", i+1, real(r.Z), imag(r.Z), r.Residual, r.Multiplicity))
+	}
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math/cmplx"
+	"testing"
+)
+
+func TestPolyRoots(t *testing.T) {
+	tests := []struct {
+		poly string
+		want []PolyRoot // по возрастанию Re, затем Im; Residual не сравнивается
+	}{
+		{"(x-1)*(x-1.0005)", []PolyRoot{{Z: 1, Multiplicity: 1}, {Z: 1.0005, Multiplicity: 1}}},
+		{"(x-1)^2*(x+2)", []PolyRoot{{Z: -2, Multiplicity: 1}, {Z: 1, Multiplicity: 2}}},
+		{"(x-2)^3", []PolyRoot{{Z: 2, Multiplicity: 3}}},
+		{"(x-0.1)^2", []PolyRoot{{Z: 0.1, Multiplicity: 2}}},
+		{"x^2+1", []PolyRoot{{Z: -1i, Multiplicity: 1}, {Z: 1i, Multiplicity: 1}}},
+		{"1 -1.89 -2 1.76", nil}, // только проверка невязок
+	}
+	methods := []struct {
+		name  string
+		solve func(Poly, float64) ([]complex128, int, [][]float64)
+	}{
+		{"aberth", aberthMethod},
+		{"deflation", deflationMethod},
+	}
+	for _, tt := range tests {
+		p, err := ParsePoly(tt.poly)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.poly, err)
+		}
+		for _, m := range methods {
+			zs, _, _ := m.solve(p, 1e-12)
+			if len(zs) != p.Degree() {
+				t.Fatalf("%s, %s: %d roots, want %d", tt.poly, m.name, len(zs), p.Degree())
+			}
+			roots := groupRoots(p, zs)
+			for _, r := range roots {
+				if r.Residual > 100*p.roundoffBound(r.Z) {
+					t.Errorf("%s, %s: |P(%v)| = %g", tt.poly, m.name, r.Z, r.Residual)
+				}
+			}
+			if tt.want == nil {
+				continue
+			}
+			if len(roots) != len(tt.want) {
+				t.Errorf("%s, %s: roots %v, want %v", tt.poly, m.name, roots, tt.want)
+				continue
+			}
+			for i, r := range roots {
+				if r.Multiplicity != tt.want[i].Multiplicity || cmplx.Abs(r.Z-tt.want[i].Z) > 1e-7 {
+					t.Errorf("%s, %s: roots %v, want %v", tt.poly, m.name, roots, tt.want)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// VectorFunc — система F(x) = 0 из n уравнений от n неизвестных.
+type VectorFunc func(x []float64) []float64
+
+// JacobianFunc возвращает матрицу Якоби системы в точке x.
+type JacobianFunc func(x []float64) [][]float64
+
+// NewtonOptions задаёт вариант метода Ньютона для систем.
+type NewtonOptions struct {
+	// LineSearch включает дробление шага: шаг уменьшается вдвое, пока
+	// норма невязки не уменьшится (условие Армихо).
+	LineSearch bool
+	// Broyden включает квазиньютоновский метод Бройдена: матрица Якоби
+	// вычисляется один раз в начальной точке, далее обновляется рангом 1.
+	Broyden bool
+}
+
+const (
+	maxSystemIter = 100
+	// minStep — наименьший множитель шага при дроблении
+	minStep = 1.0 / 1024
+)
+
+var (
+	errSingular = errors.New("матрица вырождена")
+	errDiverged = errors.New("метод расходится или достигнуто максимальное число итераций")
+)
+
+// finiteDifferenceJacobian строит матрицу Якоби F центральными разностями.
+func finiteDifferenceJacobian(F VectorFunc) JacobianFunc {
+	return func(x []float64) [][]float64 {
+		n := len(x)
+		jac := make([][]float64, n)
+		for i := range jac {
+			jac[i] = make([]float64, n)
+		}
+		xp := make([]float64, n)
+		xm := make([]float64, n)
+		for j := 0; j < n; j++ {
+			copy(xp, x)
+			copy(xm, x)
+			h := 1e-6 * math.Max(1, math.Abs(x[j]))
+			xp[j] += h
+			xm[j] -= h
+			fp, fm := F(xp), F(xm)
+			for i := 0; i < n; i++ {
+				jac[i][j] = (fp[i] - fm[i]) / (2 * h)
+			}
+		}
+		return jac
+	}
+}
+
+// luSolve решает A·x = b LU-разложением с выбором главного элемента по
+// столбцу. A и b не изменяются.
+func luSolve(A [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	lu := make([][]float64, n)
+	scale := 0.0
+	for i := range A {
+		lu[i] = append([]float64(nil), A[i]...)
+		for _, v := range A[i] {
+			scale = math.Max(scale, math.Abs(v))
+		}
+	}
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu[i][k]) > math.Abs(lu[p][k]) {
+				p = i
+			}
+		}
+		if math.Abs(lu[p][k]) <= 1e-14*scale {
+			return nil, errSingular
+		}
+		lu[k], lu[p] = lu[p], lu[k]
+		perm[k], perm[p] = perm[p], perm[k]
+		for i := k + 1; i < n; i++ {
+			lu[i][k] /= lu[k][k]
+			for j := k + 1; j < n; j++ {
+				lu[i][j] -= lu[i][k] * lu[k][j]
+			}
+		}
+	}
+
+	// Прямой ход: L·y = P·b
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = b[perm[i]]
+		for j := 0; j < i; j++ {
+			x[i] -= lu[i][j] * x[j]
+		}
+	}
+	// Обратный ход: U·x = y
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= lu[i][j] * x[j]
+		}
+		x[i] /= lu[i][i]
+	}
+	return x, nil
+}
+
+func norm2(v []float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// Метод Ньютона для системы из n уравнений. Если J равна nil, матрица
+// Якоби считается конечными разностями. Строка таблицы: xₖ, ‖F(xₖ)‖,
+// множитель шага λ и ‖xₖ₊₁ - xₖ‖.
+func newtonSystemMethod(F VectorFunc, J JacobianFunc, x0 []float64, eps float64, opts NewtonOptions) ([]float64, int, [][]float64) {
+	x, iterCount, iterations, err := solveNewtonSystem(F, J, x0, eps, opts)
+	switch err {
+	case errSingular:
+		fmt.Println("Предупреждение: матрица Якоби вырождена, метод может не сходиться")
+	case errDiverged:
+		fmt.Println("Предупреждение: метод расходится или достигнуто максимальное число итераций")
+	}
+	return x, iterCount, iterations
+}
+
+// solveNewtonSystem — метод Ньютона без вывода предупреждений: причина
+// остановки возвращается ошибкой errSingular или errDiverged.
+func solveNewtonSystem(F VectorFunc, J JacobianFunc, x0 []float64, eps float64, opts NewtonOptions) ([]float64, int, [][]float64, error) {
+	if J == nil {
+		J = finiteDifferenceJacobian(F)
+	}
+	n := len(x0)
+	x := append([]float64(nil), x0...)
+	fx := F(x)
+
+	var iterations [][]float64
+	var B [][]float64
+	if opts.Broyden {
+		B = J(x)
+	}
+
+	iterCount := 0
+	for {
+		iterCount++
+		jac := B
+		if !opts.Broyden {
+			jac = J(x)
+		}
+
+		rhs := make([]float64, n)
+		for i, v := range fx {
+			rhs[i] = -v
+		}
+		dx, err := luSolve(jac, rhs)
+		if err != nil {
+			return x, iterCount, iterations, err
+		}
+
+		lambda := 1.0
+		xNew := make([]float64, n)
+		step := func() {
+			for i := range x {
+				xNew[i] = x[i] + lambda*dx[i]
+			}
+		}
+		step()
+		fNew := F(xNew)
+		if opts.LineSearch {
+			norm := norm2(fx)
+			for !(norm2(fNew) <= (1-1e-4*lambda)*norm) && lambda > minStep {
+				lambda /= 2
+				step()
+				fNew = F(xNew)
+			}
+		}
+
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = xNew[i] - x[i]
+		}
+		row := append(append([]float64(nil), x...), norm2(fx), lambda, norm2(s))
+		iterations = append(iterations, row)
+
+		if opts.Broyden {
+			// B += (Δf - B·s)·sᵀ / (sᵀ·s)
+			ss := 0.0
+			for _, v := range s {
+				ss += v * v
+			}
+			if ss > 0 {
+				for i := 0; i < n; i++ {
+					bs := 0.0
+					for j := 0; j < n; j++ {
+						bs += B[i][j] * s[j]
+					}
+					u := (fNew[i] - fx[i] - bs) / ss
+					for j := 0; j < n; j++ {
+						B[i][j] += u * s[j]
+					}
+				}
+			}
+		}
+
+		x, fx = xNew, fNew
+		if norm2(s) < eps {
+			break
+		}
+
+		if iterCount >= maxSystemIter || math.IsNaN(norm2(x)) {
+			return x, iterCount, iterations, errDiverged
+		}
+	}
+
+	return x, iterCount, iterations, nil
+}
+
+// Метод простой итерации для системы x = φ(x). Строка таблицы: xₖ и
+// ‖xₖ₊₁ - xₖ‖.
+func simpleIterationSystemMethod(phi VectorFunc, x0 []float64, eps float64) ([]float64, int, [][]float64) {
+	x, iterCount, iterations, err := solveSimpleIterationSystem(phi, x0, eps)
+	if err != nil {
+		fmt.Println("Предупреждение: метод расходится или достигнуто максимальное число итераций")
+	}
+	return x, iterCount, iterations
+}
+
+// solveSimpleIterationSystem — метод простой итерации без вывода
+// предупреждений; при расходимости возвращает errDiverged.
+func solveSimpleIterationSystem(phi VectorFunc, x0 []float64, eps float64) ([]float64, int, [][]float64, error) {
+	var iterations [][]float64
+
+	x := append([]float64(nil), x0...)
+	iterCount := 0
+
+	for {
+		iterCount++
+		xNew := phi(x)
+		diff := 0.0
+		for i := range x {
+			diff = math.Max(diff, math.Abs(xNew[i]-x[i]))
+		}
+
+		iterations = append(iterations, append(append([]float64(nil), x...), diff))
+
+		x = xNew
+		if diff < eps {
+			break
+		}
+
+		if iterCount > maxSystemIter || math.IsNaN(norm2(x)) || norm2(x) > 1e10 {
+			return x, iterCount, iterations, errDiverged
+		}
+	}
+
+	return x, iterCount, iterations, nil
+}
+
+// exprSystem превращает разобранные уравнения в вектор-функцию.
+func exprSystem(system []*Expr) VectorFunc {
+	return func(x []float64) []float64 {
+		values := make([]float64, len(system))
+		for i, f := range system {
+			values[i] = f.Eval(x...)
+		}
+		return values
+	}
+}
+
+// exprJacobian строит символьную матрицу Якоби системы и возвращает её
+// вместе с функцией вычисления в точке.
+func exprJacobian(system []*Expr) ([][]*Expr, JacobianFunc) {
+	jac := Jacobian(system)
+	return jac, func(x []float64) [][]float64 { return EvalJacobian(jac, x...) }
+}
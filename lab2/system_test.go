@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewtonSystem(t *testing.T) {
+	tests := []struct {
+		name     string
+		system   []string
+		x0, want []float64
+	}{
+		{"circle and line", []string{"x^2 + y^2 = 4", "y = x"}, []float64{1, 2}, []float64{math.Sqrt2, math.Sqrt2}},
+		{"trigonometric", []string{"sin(x + 1) - y = 1.2", "2x + cos(y) = 2"}, []float64{0.5, -0.5}, nil},
+		{"exponential", []string{"exp(x) + y = 3", "x - y^2 = 0"}, []float64{1, 1}, nil},
+		{"three unknowns", []string{"x^2 + y^2 + z^2 = 14", "x*y*z = 6", "x + y - z = 0"}, []float64{0.8, 2.2, 2.9}, []float64{1, 2, 3}},
+		{"four unknowns", []string{"4x1 - x2 + 0.1x3^2 = 3", "-x1 + 4x2 - x3 = 2", "-x2 + 4x3 - x4 = 2", "0.1x1^2 - x3 + 4x4 = 3.1"}, []float64{0, 0, 0, 0}, nil},
+	}
+	variants := []struct {
+		name     string
+		opts     NewtonOptions
+		symbolic bool
+	}{
+		{"newton", NewtonOptions{}, true},
+		{"finite differences", NewtonOptions{}, false},
+		{"line search", NewtonOptions{LineSearch: true}, true},
+		{"broyden", NewtonOptions{Broyden: true}, true},
+	}
+	const eps = 1e-10
+	for _, tt := range tests {
+		vars := systemVars(len(tt.system))
+		system := make([]*Expr, len(tt.system))
+		for i, src := range tt.system {
+			system[i] = mustParse(src, vars...)
+		}
+		F := exprSystem(system)
+		_, symbolic := exprJacobian(system)
+		for _, v := range variants {
+			var J JacobianFunc
+			if v.symbolic {
+				J = symbolic
+			}
+			x, _, _, err := solveNewtonSystem(F, J, tt.x0, eps, v.opts)
+			if err != nil {
+				t.Errorf("%s, %s: %v", tt.name, v.name, err)
+				continue
+			}
+			if r := norm2(F(x)); r > 1e-8 {
+				t.Errorf("%s, %s: ‖F(%v)‖ = %g", tt.name, v.name, x, r)
+			}
+			for i := range tt.want {
+				if math.Abs(x[i]-tt.want[i]) > 1e-8 {
+					t.Errorf("%s, %s: x = %v, want %v", tt.name, v.name, x, tt.want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestNewtonSystemSingular(t *testing.T) {
+	vars := systemVars(2)
+	system := []*Expr{mustParse("x + y = 1", vars...), mustParse("2x + 2y = 3", vars...)}
+	_, J := exprJacobian(system)
+	if _, _, _, err := solveNewtonSystem(exprSystem(system), J, []float64{0, 0}, 1e-10, NewtonOptions{}); err != errSingular {
+		t.Errorf("error = %v, want %v", err, errSingular)
+	}
+}